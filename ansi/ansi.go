@@ -16,7 +16,23 @@ type SGR uint
 // String returns the ANSI escape sequence as a string for this SGR code.
 // For example, SGR code 31 (FgRed) returns "\033[31m".
 func (s SGR) String() string {
-	return fmt.Sprintf("%s%dm", CSI, s)
+	return fmt.Sprintf("%s%sm", CSI, s.parameters())
+}
+
+// parameters returns the SGR parameters, decoding extended colors.
+func (s SGR) parameters() string {
+	switch s & sgrExtendedMask {
+	case sgrFgIndexed:
+		return fmt.Sprintf("38;5;%d", s&0xff)
+	case sgrBgIndexed:
+		return fmt.Sprintf("48;5;%d", s&0xff)
+	case sgrFgRGB:
+		return fmt.Sprintf("38;2;%d;%d;%d", (s>>16)&0xff, (s>>8)&0xff, s&0xff)
+	case sgrBgRGB:
+		return fmt.Sprintf("48;2;%d;%d;%d", (s>>16)&0xff, (s>>8)&0xff, s&0xff)
+	default:
+		return fmt.Sprintf("%d", s)
+	}
 }
 
 // Sprintf works similar to fmt.Sprintf, but it wraps the formatted text with the SGR codes and
@@ -110,9 +126,9 @@ func (s SGRs) String() string {
 	buff.Write([]byte(CSI))
 	for i, sgr := range s {
 		if i+1 < len(s) {
-			fmt.Fprintf(&buff, "%d;", sgr)
+			fmt.Fprintf(&buff, "%s;", sgr.parameters())
 		} else {
-			fmt.Fprintf(&buff, "%d", sgr)
+			fmt.Fprintf(&buff, "%s", sgr.parameters())
 		}
 	}
 	buff.WriteString("m")
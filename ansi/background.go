@@ -0,0 +1,170 @@
+package ansi
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// IsLight returns whether the color is light, with a relative luminance above 50%, such that dark
+// text is more readable than light text over it.
+func (c RGBColor) IsLight() bool {
+	// ITU-R BT.709 luma coefficients
+	return 0.2126*float64(c.R)+0.7152*float64(c.G)+0.0722*float64(c.B) > 127.5
+}
+
+// colorFGBGBackground returns the background color from a COLORFGBG environment variable value,
+// as set by rxvt and derived terminals, eg: "15;0" or "0;default;15", where the last field is the
+// background color index.
+func colorFGBGBackground(value string) (RGBColor, bool) {
+	fields := strings.Split(value, ";")
+	if len(fields) < 2 {
+		return RGBColor{}, false
+	}
+	index, err := strconv.ParseUint(fields[len(fields)-1], 10, 8)
+	if err != nil || index > 15 {
+		return RGBColor{}, false
+	}
+	return IndexedColor(index).RGB(), true
+}
+
+// QueryBackgroundColor is the OSC 11 query for the background color, followed by a primary
+// device attributes query, that all terminals reply to, so that terminals that do not support
+// OSC 11 are detected without waiting for a timeout.
+const QueryBackgroundColor = "\033]11;?\033\\" + CSI + "c"
+
+// parseColorSpec parses an X11 color specification, as replied to OSC 11, eg:
+// "rgb:ffff/ffff/ffff", where each component has 1 to 4 hexadecimal digits.
+func parseColorSpec(spec string) (RGBColor, bool) {
+	spec, ok := strings.CutPrefix(spec, "rgb:")
+	if !ok {
+		return RGBColor{}, false
+	}
+	components := strings.Split(spec, "/")
+	if len(components) != 3 {
+		return RGBColor{}, false
+	}
+	var rgb [3]uint8
+	for i, component := range components {
+		if len(component) < 1 || len(component) > 4 {
+			return RGBColor{}, false
+		}
+		value, err := strconv.ParseUint(component, 16, 16)
+		if err != nil {
+			return RGBColor{}, false
+		}
+		maxValue := uint64(1)<<(4*len(component)) - 1
+		rgb[i] = uint8((value*255 + maxValue/2) / maxValue)
+	}
+	return RGBColor{R: rgb[0], G: rgb[1], B: rgb[2]}, true
+}
+
+// ParseBackgroundColorReply parses the reply of a terminal to QueryBackgroundColor. It returns
+// the background color, if replied to, and whether the reply is complete: the device attributes
+// reply, which terminals send after the background color one, was received.
+func ParseBackgroundColorReply(reply string) (color RGBColor, ok bool, complete bool) {
+	for _, token := range Tokenize(reply) {
+		switch token.Kind {
+		case TokenOSC:
+			spec, found := strings.CutPrefix(token.Text, "\033]11;")
+			if !found {
+				continue
+			}
+			if s, found := strings.CutSuffix(spec, "\a"); found {
+				spec = s
+			} else if s, found := strings.CutSuffix(spec, "\033\\"); found {
+				spec = s
+			} else {
+				continue
+			}
+			color, ok = parseColorSpec(spec)
+		case TokenCSI:
+			if strings.HasPrefix(token.Text, CSI+"?") && strings.HasSuffix(token.Text, "c") {
+				return color, ok, true
+			}
+		}
+	}
+	return color, ok, false
+}
+
+// QueryTerminalBackgroundColor queries the terminal at tty for its background color with
+// QueryBackgroundColor, waiting at most timeout for its reply. The terminal is put in raw mode
+// while waiting, so the reply is not echoed; input typed meanwhile is discarded. If the terminal
+// does not reply in time, its input is drained for up to another timeout before the terminal is
+// restored, so that a late reply is not read by the shell.
+func QueryTerminalBackgroundColor(tty *os.File, timeout time.Duration) (RGBColor, error) {
+	fd := int(tty.Fd())
+	if !term.IsTerminal(fd) {
+		return RGBColor{}, errors.New("not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return RGBColor{}, err
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return RGBColor{}, err
+	}
+	if _, err := tty.WriteString(QueryBackgroundColor); err != nil {
+		return RGBColor{}, err
+	}
+	var reply []byte
+	buf := make([]byte, 256)
+	var timedOut bool
+	for {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		color, ok, complete := ParseBackgroundColorReply(string(reply))
+		if timedOut {
+			if complete || err != nil {
+				return RGBColor{}, os.ErrDeadlineExceeded
+			}
+			continue
+		}
+		if complete {
+			if ok {
+				return color, nil
+			}
+			return RGBColor{}, errors.New("terminal does not support background color query")
+		}
+		if err != nil {
+			if !errors.Is(err, os.ErrDeadlineExceeded) {
+				return RGBColor{}, err
+			}
+			timedOut = true
+			if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+				return RGBColor{}, err
+			}
+		}
+	}
+}
+
+// EnvBackgroundColor returns the background color of the terminal from the COLORFGBG environment
+// variable, as set by rxvt and derived terminals. It returns false if it is not set.
+func EnvBackgroundColor() (RGBColor, bool) {
+	return colorFGBGBackground(os.Getenv("COLORFGBG"))
+}
+
+// DetectBackgroundColor returns the background color of the terminal, from the COLORFGBG
+// environment variable, or else queried from the controlling terminal with
+// QueryTerminalBackgroundColor. It returns false if the background color is unknown.
+func DetectBackgroundColor(timeout time.Duration) (RGBColor, bool) {
+	if color, ok := EnvBackgroundColor(); ok {
+		return color, true
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return RGBColor{}, false
+	}
+	defer func() { _ = tty.Close() }()
+	color, err := QueryTerminalBackgroundColor(tty, timeout)
+	if err != nil {
+		return RGBColor{}, false
+	}
+	return color, true
+}
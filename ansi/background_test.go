@@ -0,0 +1,90 @@
+package ansi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRGBColorIsLight(t *testing.T) {
+	require.True(t, RGBColor{R: 255, G: 255, B: 255}.IsLight())
+	require.True(t, RGBColor{R: 0xfd, G: 0xf6, B: 0xe3}.IsLight())
+	require.False(t, RGBColor{}.IsLight())
+	require.False(t, RGBColor{R: 0x00, G: 0x2b, B: 0x36}.IsLight())
+	require.False(t, RGBColor{R: 0, G: 0, B: 255}.IsLight())
+}
+
+func TestColorFGBGBackground(t *testing.T) {
+	tests := []struct {
+		value      string
+		expected   RGBColor
+		expectedOk bool
+	}{
+		{"", RGBColor{}, false},
+		{"15;0", palette16[0], true},
+		{"0;15", palette16[15], true},
+		{"0;default;7", palette16[7], true},
+		{"15;default", RGBColor{}, false},
+		{"0;16", RGBColor{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			color, ok := colorFGBGBackground(tt.value)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, color)
+		})
+	}
+}
+
+func TestParseBackgroundColorReply(t *testing.T) {
+	tests := []struct {
+		name             string
+		reply            string
+		expected         RGBColor
+		expectedOk       bool
+		expectedComplete bool
+	}{
+		{"empty", "", RGBColor{}, false, false},
+		{
+			"ST",
+			"\033]11;rgb:ffff/ffff/ffff\033\\\033[?62;22c",
+			RGBColor{R: 255, G: 255, B: 255}, true, true,
+		},
+		{
+			"BEL",
+			"\033]11;rgb:00/2b/36\a\033[?1;2c",
+			RGBColor{R: 0x00, G: 0x2b, B: 0x36}, true, true,
+		},
+		{"one digit", "\033]11;rgb:f/8/0\a", RGBColor{R: 255, G: 136, B: 0}, true, false},
+		{"partial", "\033]11;rgb:ffff/ff", RGBColor{}, false, false},
+		{"unsupported", "\033[?1;2c", RGBColor{}, false, true},
+		{"invalid spec", "\033]11;#ffffff\a\033[?1;2c", RGBColor{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, ok, complete := ParseBackgroundColorReply(tt.reply)
+			require.Equal(t, tt.expected, color)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expectedComplete, complete)
+		})
+	}
+}
+
+func TestDetectBackgroundColor(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	color, ok := DetectBackgroundColor(time.Millisecond)
+	require.True(t, ok)
+	require.True(t, color.IsLight())
+}
+
+func TestEnvBackgroundColor(t *testing.T) {
+	t.Setenv("COLORFGBG", "15;0")
+	color, ok := EnvBackgroundColor()
+	require.True(t, ok)
+	require.False(t, color.IsLight())
+
+	t.Setenv("COLORFGBG", "")
+	_, ok = EnvBackgroundColor()
+	require.False(t, ok)
+}
@@ -0,0 +1,196 @@
+package ansi
+
+import (
+	"os"
+	"strings"
+)
+
+// Extended colors are encoded at the high bits of SGR, so they can be composed with other
+// display attributes in SGRs.
+const (
+	sgrExtendedMask SGR = 0xf << 24
+	sgrFgIndexed    SGR = 1 << 24
+	sgrBgIndexed    SGR = 2 << 24
+	sgrFgRGB        SGR = 3 << 24
+	sgrBgRGB        SGR = 4 << 24
+)
+
+// IndexedColor is a color from the 256 colors palette: 0-15 are the standard and bright colors,
+// 16-231 a 6x6x6 color cube and 232-255 a grayscale ramp.
+type IndexedColor uint8
+
+// Fg returns the SGR that sets the foreground to this color.
+func (c IndexedColor) Fg() SGR {
+	return sgrFgIndexed | SGR(c)
+}
+
+// Bg returns the SGR that sets the background to this color.
+func (c IndexedColor) Bg() SGR {
+	return sgrBgIndexed | SGR(c)
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// RGB returns the approximate 24-bit color for this color. Colors 0-15 are configurable in most
+// terminals, so xterm defaults are used.
+func (c IndexedColor) RGB() RGBColor {
+	switch {
+	case c < 16:
+		return palette16[c]
+	case c < 232:
+		i := c - 16
+		return RGBColor{R: cubeLevels[i/36], G: cubeLevels[(i/6)%6], B: cubeLevels[i%6]}
+	default:
+		level := 8 + 10*uint8(c-232)
+		return RGBColor{R: level, G: level, B: level}
+	}
+}
+
+// RGBColor is a 24-bit truecolor.
+type RGBColor struct {
+	R, G, B uint8
+}
+
+// Fg returns the SGR that sets the foreground to this color.
+func (c RGBColor) Fg() SGR {
+	return sgrFgRGB | SGR(c.R)<<16 | SGR(c.G)<<8 | SGR(c.B)
+}
+
+// Bg returns the SGR that sets the background to this color.
+func (c RGBColor) Bg() SGR {
+	return sgrBgRGB | SGR(c.R)<<16 | SGR(c.G)<<8 | SGR(c.B)
+}
+
+func (c RGBColor) distance(o RGBColor) int {
+	dr := int(c.R) - int(o.R)
+	dg := int(c.G) - int(o.G)
+	db := int(c.B) - int(o.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// nearest returns the index of the color closest to c in the [from, to) range of the 256 colors
+// palette.
+func (c RGBColor) nearest(from, to int) IndexedColor {
+	nearest := IndexedColor(from)
+	nearestDistance := -1
+	for i := from; i < to; i++ {
+		distance := c.distance(IndexedColor(i).RGB())
+		if nearestDistance < 0 || distance < nearestDistance {
+			nearest = IndexedColor(i)
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// xterm default 16 colors.
+var palette16 = [16]RGBColor{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+// ColorLevel is the level of color support of a terminal.
+type ColorLevel int
+
+const (
+	// 16 colors: 3-bit and 4-bit colors only.
+	ColorLevel16 ColorLevel = iota + 1
+	// 256 colors: adds IndexedColor.
+	ColorLevel256
+	// 24-bit colors: adds RGBColor.
+	ColorLevelTrueColor
+)
+
+// DetectColorLevel returns the color level supported by the terminal, as advertised by the
+// COLORTERM and TERM environment variables.
+func DetectColorLevel() ColorLevel {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorLevelTrueColor
+	}
+	term := os.Getenv("TERM")
+	if strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor") {
+		return ColorLevelTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return ColorLevel256
+	}
+	return ColorLevel16
+}
+
+// indexedColor16 returns the 3-bit or 4-bit color SGR for the 0-15 color c.
+func indexedColor16(c IndexedColor, bg bool) SGR {
+	var sgr SGR
+	if c < 8 {
+		sgr = FgBlack + SGR(c)
+	} else {
+		sgr = FgDarkGray + SGR(c-8)
+	}
+	if bg {
+		sgr += BgBlack - FgBlack
+	}
+	return sgr
+}
+
+// Downsample returns the closest color to this SGR that is supported at the given color level.
+// SGRs other than extended colors are returned as is.
+func (s SGR) Downsample(level ColorLevel) SGR {
+	var rgb RGBColor
+	var indexed IndexedColor
+	var bg bool
+	switch s & sgrExtendedMask {
+	case sgrFgIndexed, sgrBgIndexed:
+		if level >= ColorLevel256 {
+			return s
+		}
+		indexed = IndexedColor(s & 0xff)
+		bg = s&sgrExtendedMask == sgrBgIndexed
+		if indexed < 16 {
+			return indexedColor16(indexed, bg)
+		}
+		rgb = indexed.RGB()
+	case sgrFgRGB, sgrBgRGB:
+		if level >= ColorLevelTrueColor {
+			return s
+		}
+		rgb = RGBColor{R: uint8(s >> 16), G: uint8(s >> 8), B: uint8(s)}
+		bg = s&sgrExtendedMask == sgrBgRGB
+		if level == ColorLevel256 {
+			indexed = rgb.nearest(16, 256)
+			if bg {
+				return indexed.Bg()
+			}
+			return indexed.Fg()
+		}
+	default:
+		return s
+	}
+	return indexedColor16(rgb.nearest(0, 16), bg)
+}
+
+// Downsample returns a copy of these SGRs, with all colors downsampled to the given color level
+// with [SGR.Downsample].
+func (s SGRs) Downsample(level ColorLevel) SGRs {
+	if s == nil {
+		return nil
+	}
+	downsampled := make(SGRs, len(s))
+	for i, sgr := range s {
+		downsampled[i] = sgr.Downsample(level)
+	}
+	return downsampled
+}
@@ -0,0 +1,108 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexedColor(t *testing.T) {
+	t.Run("SGRs", func(t *testing.T) {
+		require.Equal(t, "\033[38;5;208m", IndexedColor(208).Fg().String())
+		require.Equal(t, "\033[48;5;0m", IndexedColor(0).Bg().String())
+		require.Equal(
+			t,
+			"\033[1;38;5;208;48;5;17mHello\033[0m",
+			SGRs{Bold, IndexedColor(208).Fg(), IndexedColor(17).Bg()}.Sprintf("Hello"),
+		)
+	})
+
+	t.Run("RGB", func(t *testing.T) {
+		tests := []struct {
+			color    IndexedColor
+			expected RGBColor
+		}{
+			{1, RGBColor{205, 0, 0}},
+			{16, RGBColor{0, 0, 0}},
+			{196, RGBColor{255, 0, 0}},
+			{208, RGBColor{255, 135, 0}},
+			{231, RGBColor{255, 255, 255}},
+			{232, RGBColor{8, 8, 8}},
+			{255, RGBColor{238, 238, 238}},
+		}
+
+		for _, test := range tests {
+			require.Equal(t, test.expected, test.color.RGB())
+		}
+	})
+}
+
+func TestRGBColor(t *testing.T) {
+	require.Equal(t, "\033[38;2;255;128;0m", RGBColor{255, 128, 0}.Fg().String())
+	require.Equal(t, "\033[48;2;1;2;3m", RGBColor{1, 2, 3}.Bg().String())
+	require.Equal(
+		t,
+		"\033[38;2;255;128;0;4mHello\033[0m",
+		SGRs{RGBColor{255, 128, 0}.Fg(), Underline}.Sprintf("Hello"),
+	)
+}
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name     string
+		sgr      SGR
+		level    ColorLevel
+		expected SGR
+	}{
+		{"basic", FgRed, ColorLevel16, FgRed},
+		{"indexed_256", IndexedColor(208).Fg(), ColorLevel256, IndexedColor(208).Fg()},
+		{"indexed_16_standard", IndexedColor(1).Fg(), ColorLevel16, FgRed},
+		{"indexed_16_bright", IndexedColor(12).Bg(), ColorLevel16, BgLightBlue},
+		{"indexed_16_cube", IndexedColor(196).Fg(), ColorLevel16, FgLightRed},
+		{"indexed_16_gray", IndexedColor(244).Bg(), ColorLevel16, BgDarkGray},
+		{"rgb_truecolor", RGBColor{1, 2, 3}.Fg(), ColorLevelTrueColor, RGBColor{1, 2, 3}.Fg()},
+		{"rgb_256", RGBColor{255, 135, 0}.Fg(), ColorLevel256, IndexedColor(208).Fg()},
+		{"rgb_256_gray", RGBColor{128, 128, 128}.Bg(), ColorLevel256, IndexedColor(244).Bg()},
+		{"rgb_16", RGBColor{0, 200, 0}.Fg(), ColorLevel16, FgGreen},
+		{"rgb_16_bg", RGBColor{250, 250, 250}.Bg(), ColorLevel16, BgLightWhite},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.sgr.Downsample(test.level))
+		})
+	}
+
+	t.Run("SGRs", func(t *testing.T) {
+		require.Nil(t, SGRs(nil).Downsample(ColorLevel16))
+		require.Equal(
+			t,
+			SGRs{Bold, FgLightRed, BgBlack},
+			SGRs{Bold, RGBColor{255, 0, 0}.Fg(), IndexedColor(16).Bg()}.Downsample(ColorLevel16),
+		)
+	})
+}
+
+func TestDetectColorLevel(t *testing.T) {
+	tests := []struct {
+		colorTerm string
+		term      string
+		expected  ColorLevel
+	}{
+		{"truecolor", "xterm", ColorLevelTrueColor},
+		{"24bit", "", ColorLevelTrueColor},
+		{"", "xterm-direct", ColorLevelTrueColor},
+		{"", "xterm-256color", ColorLevel256},
+		{"", "screen-256color", ColorLevel256},
+		{"", "xterm", ColorLevel16},
+		{"", "", ColorLevel16},
+	}
+
+	for _, test := range tests {
+		t.Run(test.colorTerm+"_"+test.term, func(t *testing.T) {
+			t.Setenv("COLORTERM", test.colorTerm)
+			t.Setenv("TERM", test.term)
+			require.Equal(t, test.expected, DetectColorLevel())
+		})
+	}
+}
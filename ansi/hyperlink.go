@@ -0,0 +1,42 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operating System Command
+const OSC = "\033]"
+
+// String Terminator
+const ST = "\033\\"
+
+// HyperlinkStart returns the OSC 8 escape sequence starting a hyperlink to url. Text written after
+// it is part of the hyperlink, until HyperlinkEnd. The url is written with escapeHyperlinkURL, so
+// that it can not end the sequence early.
+func HyperlinkStart(url string) string {
+	return OSC + "8;;" + escapeHyperlinkURL(url) + ST
+}
+
+// escapeHyperlinkURL percent-encodes the bytes of url outside of the printable ASCII range, as
+// OSC 8 requires, including ESC, BEL, C1 controls and spaces.
+func escapeHyperlinkURL(url string) string {
+	var b strings.Builder
+	for i := 0; i < len(url); i++ {
+		if c := url[i]; c <= ' ' || c >= 0x7f {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// HyperlinkEnd is the OSC 8 escape sequence ending a hyperlink.
+const HyperlinkEnd = OSC + "8;;" + ST
+
+// Hyperlink returns text wrapped in an OSC 8 hyperlink to url, for terminals that support it;
+// other terminals display only the text.
+func Hyperlink(url, text string) string {
+	return HyperlinkStart(url) + text + HyperlinkEnd
+}
@@ -0,0 +1,36 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperlink(t *testing.T) {
+	require.Equal(
+		t,
+		"\033]8;;https://example.com/\033\\example\033]8;;\033\\",
+		Hyperlink("https://example.com/", "example"),
+	)
+}
+
+func TestHyperlinkStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"plain", "https://example.com/a?b=c#d", "\033]8;;https://example.com/a?b=c#d\033\\"},
+		{"string_terminator", "https://example.com/\033\\\033[2J", "\033]8;;https://example.com/%1B\\%1B[2J\033\\"},
+		{"bel", "https://example.com/\a", "\033]8;;https://example.com/%07\033\\"},
+		{"c1", "https://example.com/\u009c\u009b", "\033]8;;https://example.com/%C2%9C%C2%9B\033\\"},
+		{"raw_c1", "https://example.com/\x9c", "\033]8;;https://example.com/%9C\033\\"},
+		{"space_and_unicode", "https://example.com/a b/é", "\033]8;;https://example.com/a%20b/%C3%A9\033\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, HyperlinkStart(tt.url))
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package ansi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	// Text without escape sequences.
+	TokenText TokenKind = iota
+	// Control Sequence Introducer sequence, eg: SGR "\033[31m" or cursor movement "\033[2A".
+	TokenCSI
+	// Operating System Command sequence, eg: hyperlink "\033]8;;https://example.com/\033\\".
+	TokenOSC
+	// Any other escape sequence, eg: "\033c", or a Device Control String.
+	TokenESC
+)
+
+// Token is either text or an escape sequence.
+type Token struct {
+	Kind TokenKind
+	// Text of the token, including the escape sequence introducer and terminator.
+	Text string
+}
+
+// Tokenize splits s into text and escape sequences. Malformed or unterminated escape sequences are
+// returned as TokenCSI, TokenOSC or TokenESC tokens up to where they stop being valid, so that
+// concatenating the text of all tokens always returns s.
+func Tokenize(s string) []Token {
+	tokens := []Token{}
+	start := 0
+	for i := 0; i < len(s); {
+		if s[i] != '\033' {
+			i++
+			continue
+		}
+		if start < i {
+			tokens = append(tokens, Token{Kind: TokenText, Text: s[start:i]})
+		}
+		kind, n := sequence(s[i:])
+		tokens = append(tokens, Token{Kind: kind, Text: s[i : i+n]})
+		i += n
+		start = i
+	}
+	if start < len(s) {
+		tokens = append(tokens, Token{Kind: TokenText, Text: s[start:]})
+	}
+	return tokens
+}
+
+// sequence returns the kind and length of the escape sequence at the start of s, which must start
+// with ESC.
+func sequence(s string) (TokenKind, int) {
+	if len(s) < 2 {
+		return TokenESC, len(s)
+	}
+	switch s[1] {
+	case '[':
+		// Parameter bytes, then intermediate bytes, then a final byte.
+		i := 2
+		for i < len(s) && 0x30 <= s[i] && s[i] <= 0x3f {
+			i++
+		}
+		for i < len(s) && 0x20 <= s[i] && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) && 0x40 <= s[i] && s[i] <= 0x7e {
+			i++
+		}
+		return TokenCSI, i
+	case ']':
+		return TokenOSC, stringSequenceLength(s)
+	case 'P', 'X', '^', '_':
+		// Device Control String, Start Of String, Privacy Message and Application Program Command.
+		return TokenESC, stringSequenceLength(s)
+	default:
+		// Intermediate bytes, then a final byte.
+		i := 1
+		for i < len(s) && 0x20 <= s[i] && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) && 0x30 <= s[i] && s[i] <= 0x7e {
+			i++
+		}
+		return TokenESC, i
+	}
+}
+
+// stringSequenceLength returns the length of the control string at the start of s, terminated by
+// ST or BEL, or the length of s if it is unterminated.
+func stringSequenceLength(s string) int {
+	for i := 2; i < len(s); i++ {
+		if s[i] == '\a' {
+			return i + 1
+		}
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+			return i + 2
+		}
+	}
+	return len(s)
+}
+
+// SGRs returns the display attributes of a Select Graphic Rendition sequence, and whether the
+// token is a valid one. Extended colors, in both the ";" and ":" separated forms, are decoded
+// to IndexedColor and RGBColor SGRs. An empty parameter list is a Reset.
+func (t Token) SGRs() (SGRs, bool) {
+	if t.Kind != TokenCSI || !strings.HasPrefix(t.Text, CSI) || !strings.HasSuffix(t.Text, "m") {
+		return nil, false
+	}
+	parameters := t.Text[len(CSI) : len(t.Text)-1]
+	if strings.ContainsAny(parameters, "<=>?") || strings.ContainsFunc(parameters, func(r rune) bool {
+		return 0x20 <= r && r <= 0x2f
+	}) {
+		return nil, false
+	}
+	if parameters == "" {
+		return SGRs{Reset}, true
+	}
+	fields := strings.Split(parameters, ";")
+	sgrs := SGRs{}
+	for i := 0; i < len(fields); i++ {
+		var subFields []string
+		if strings.Contains(fields[i], ":") {
+			subFields = strings.Split(fields[i], ":")
+		} else if fields[i] == "38" || fields[i] == "48" {
+			// The ";" separated form takes the color from the following fields.
+			end := i + 2
+			if i+1 < len(fields) && fields[i+1] == "2" {
+				end = i + 4
+			}
+			if end >= len(fields) {
+				return nil, false
+			}
+			subFields = fields[i : end+1]
+			i = end
+		} else {
+			subFields = []string{fields[i]}
+		}
+		sgr, ok := parseSGR(subFields)
+		if !ok {
+			return nil, false
+		}
+		sgrs = append(sgrs, sgr)
+	}
+	return sgrs, true
+}
+
+// parseSGR parses a single display attribute, given as its parameter and sub parameters.
+func parseSGR(fields []string) (SGR, bool) {
+	values := make([]uint8, len(fields))
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return 0, false
+		}
+		values[i] = uint8(value)
+	}
+	if values[0] != 38 && values[0] != 48 {
+		if len(values) > 1 && values[0] != 4 {
+			return 0, false
+		}
+		// Underline styles, eg: "4:3" for curly underline.
+		if len(values) > 1 && values[1] == 0 {
+			return SGR(24), true
+		}
+		return SGR(values[0]), true
+	}
+	if len(values) < 2 {
+		return 0, false
+	}
+	switch {
+	case values[1] == 5 && len(values) == 3:
+		if values[0] == 38 {
+			return IndexedColor(values[2]).Fg(), true
+		}
+		return IndexedColor(values[2]).Bg(), true
+	case values[1] == 2 && (len(values) == 5 || len(values) == 6):
+		// The ":" separated form may have a color space identifier before the components.
+		rgb := values[len(values)-3:]
+		color := RGBColor{R: rgb[0], G: rgb[1], B: rgb[2]}
+		if values[0] == 38 {
+			return color.Fg(), true
+		}
+		return color.Bg(), true
+	default:
+		return 0, false
+	}
+}
+
+// Hyperlink returns the URL of an OSC 8 hyperlink sequence, and whether the token is one. The
+// sequence ending a hyperlink has an empty URL.
+func (t Token) Hyperlink() (string, bool) {
+	if t.Kind != TokenOSC {
+		return "", false
+	}
+	body, ok := strings.CutPrefix(t.Text, OSC+"8;")
+	if !ok {
+		return "", false
+	}
+	if b, ok := strings.CutSuffix(body, ST); ok {
+		body = b
+	} else if b, ok := strings.CutSuffix(body, "\a"); ok {
+		body = b
+	} else {
+		return "", false
+	}
+	_, url, ok := strings.Cut(body, ";")
+	return url, ok
+}
+
+// Run is text displayed with the same display attributes and hyperlink.
+type Run struct {
+	Text string
+	// Active display attributes, with at most one attribute of each kind, eg: a single
+	// foreground color. Empty when no attributes are active.
+	SGRs SGRs
+	// URL of the active hyperlink, or empty when not in a hyperlink.
+	URL string
+}
+
+// Runs splits s into text runs with their active display attributes and hyperlink. Escape
+// sequences other than SGR and OSC 8 hyperlinks are ignored.
+func Runs(s string) []Run {
+	runs := []Run{}
+	var state SGRs
+	var url string
+	for _, token := range Tokenize(s) {
+		if token.Kind != TokenText {
+			if sgrs, ok := token.SGRs(); ok {
+				state = applySGRs(state, sgrs)
+			} else if u, ok := token.Hyperlink(); ok {
+				url = u
+			}
+			continue
+		}
+		if len(runs) > 0 {
+			last := &runs[len(runs)-1]
+			if last.URL == url && slices.Equal(last.SGRs, state) {
+				last.Text += token.Text
+				continue
+			}
+		}
+		runs = append(runs, Run{Text: token.Text, SGRs: state, URL: url})
+	}
+	return runs
+}
+
+// sgrKind identifies what display attribute an SGR sets, so that a later SGR of the same kind
+// replaces it.
+func sgrKind(s SGR) SGR {
+	switch {
+	case s&sgrExtendedMask == sgrFgIndexed || s&sgrExtendedMask == sgrFgRGB:
+		return 38
+	case s&sgrExtendedMask == sgrBgIndexed || s&sgrExtendedMask == sgrBgRGB:
+		return 48
+	case 30 <= s && s <= 37, 90 <= s && s <= 97:
+		return 38
+	case 40 <= s && s <= 47, 100 <= s && s <= 107:
+		return 48
+	case s == 6:
+		return Blink
+	case s == 21:
+		return Underline
+	default:
+		return s
+	}
+}
+
+// sgrResets returns the kinds of display attributes an SGR resets.
+func sgrResets(s SGR) []SGR {
+	switch s {
+	case 22:
+		return []SGR{Bold, Dim}
+	case 23, 24, 25, 27, 28, 29:
+		return []SGR{s - 20}
+	case 39:
+		return []SGR{38}
+	case 49:
+		return []SGR{48}
+	default:
+		return nil
+	}
+}
+
+// applySGRs returns state after applying sgrs to it.
+func applySGRs(state, sgrs SGRs) SGRs {
+	next := append(SGRs{}, state...)
+	for _, sgr := range sgrs {
+		if sgr == Reset {
+			next = SGRs{}
+			continue
+		}
+		resets := sgrResets(sgr)
+		if resets == nil {
+			resets = []SGR{sgrKind(sgr)}
+		}
+		filtered := next[:0]
+		for _, active := range next {
+			reset := false
+			for _, kind := range resets {
+				if sgrKind(active) == kind {
+					reset = true
+				}
+			}
+			if !reset {
+				filtered = append(filtered, active)
+			}
+		}
+		next = filtered
+		if sgrResets(sgr) == nil {
+			next = append(next, sgr)
+		}
+	}
+	if len(next) == 0 {
+		return nil
+	}
+	return next
+}
+
+// Strip removes all escape sequences from s.
+func Strip(s string) string {
+	var b strings.Builder
+	for _, token := range Tokenize(s) {
+		if token.Kind == TokenText {
+			b.WriteString(token.Text)
+		}
+	}
+	return b.String()
+}
+
+// isAllowedToken returns whether a token only changes display attributes or hyperlinks.
+func isAllowedToken(token Token) bool {
+	if token.Kind == TokenText {
+		return true
+	}
+	if _, ok := token.SGRs(); ok {
+		return true
+	}
+	_, ok := token.Hyperlink()
+	return ok
+}
+
+// isDisruptiveControl returns whether r is a control character that moves the cursor or
+// otherwise disrupts the display of text, which is all of them but tab and newline.
+func isDisruptiveControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n'
+}
+
+// Validate returns an error if s contains anything other than text, SGR sequences and OSC 8
+// hyperlinks: cursor movement, screen clearing and other escape sequences, or control characters
+// other than tab and newline.
+func Validate(s string) error {
+	offset := 0
+	for _, token := range Tokenize(s) {
+		if !isAllowedToken(token) {
+			return fmt.Errorf("unsupported escape sequence %q at offset %d", token.Text, offset)
+		}
+		if token.Kind == TokenText {
+			for i, r := range token.Text {
+				if isDisruptiveControl(r) {
+					return fmt.Errorf("unsupported control character %q at offset %d", r, offset+i)
+				}
+			}
+		}
+		offset += len(token.Text)
+	}
+	return nil
+}
+
+// Sanitize returns s with everything that Validate rejects removed.
+func Sanitize(s string) string {
+	var b strings.Builder
+	for _, token := range Tokenize(s) {
+		if !isAllowedToken(token) {
+			continue
+		}
+		if token.Kind != TokenText {
+			b.WriteString(token.Text)
+			continue
+		}
+		for i := 0; i < len(token.Text); {
+			r, size := utf8.DecodeRuneInString(token.Text[i:])
+			if !isDisruptiveControl(r) {
+				b.WriteString(token.Text[i : i+size])
+			}
+			i += size
+		}
+	}
+	return b.String()
+}
+
+// SplitLines returns copies of lines, that are consecutive parts of a single string, where the
+// display attributes and hyperlink active at the end of each line are closed at its end, and
+// reopened at the start of the next line. This way, each line can be written on its own, with
+// other text in between, eg: an indentation, without it inheriting the style of the lines.
+func SplitLines(lines []string) []string {
+	split := make([]string, len(lines))
+	var state Style
+	var url string
+	for i, line := range lines {
+		var b strings.Builder
+		b.WriteString(state.String())
+		if url != "" {
+			b.WriteString(HyperlinkStart(url))
+		}
+		b.WriteString(line)
+		for _, token := range Tokenize(line) {
+			if sgrs, ok := token.SGRs(); ok {
+				state = Style{sgrs: applySGRs(state.sgrs, sgrs)}
+			} else if tokenURL, ok := token.Hyperlink(); ok {
+				url = tokenURL
+			}
+		}
+		if url != "" {
+			b.WriteString(HyperlinkEnd)
+		}
+		b.WriteString(state.Transition(Style{}))
+		split[i] = b.String()
+	}
+	return split
+}
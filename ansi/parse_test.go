@@ -0,0 +1,241 @@
+package ansi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{"empty", "", []Token{}},
+		{"text", "plain", []Token{{TokenText, "plain"}}},
+		{
+			"SGR",
+			"a\033[1;31mb\033[0m",
+			[]Token{{TokenText, "a"}, {TokenCSI, "\033[1;31m"}, {TokenText, "b"}, {TokenCSI, "\033[0m"}},
+		},
+		{
+			"cursor movement",
+			"\033[2Ja\033[?25l",
+			[]Token{{TokenCSI, "\033[2J"}, {TokenText, "a"}, {TokenCSI, "\033[?25l"}},
+		},
+		{
+			"hyperlink",
+			"\033]8;;https://example.com/\033\\link\033]8;;\a",
+			[]Token{
+				{TokenOSC, "\033]8;;https://example.com/\033\\"},
+				{TokenText, "link"},
+				{TokenOSC, "\033]8;;\a"},
+			},
+		},
+		{"ESC", "\033ca\033(B", []Token{{TokenESC, "\033c"}, {TokenText, "a"}, {TokenESC, "\033(B"}}},
+		{"DCS", "\033Pq#0\033\\a", []Token{{TokenESC, "\033Pq#0\033\\"}, {TokenText, "a"}}},
+		{"unterminated CSI", "a\033[31", []Token{{TokenText, "a"}, {TokenCSI, "\033[31"}}},
+		{"unterminated OSC", "\033]0;title", []Token{{TokenOSC, "\033]0;title"}}},
+		{"lone ESC", "a\033", []Token{{TokenText, "a"}, {TokenESC, "\033"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Tokenize(tt.input))
+		})
+	}
+}
+
+func TestTokenSGRs(t *testing.T) {
+	tests := []struct {
+		name       string
+		sequence   string
+		expected   SGRs
+		expectedOk bool
+	}{
+		{"reset", "\033[m", SGRs{Reset}, true},
+		{"attributes", "\033[1;31;42m", SGRs{Bold, FgRed, BgGreen}, true},
+		{"empty parameter", "\033[;1m", SGRs{Reset, Bold}, true},
+		{"indexed", "\033[38;5;208;48;5;17m", SGRs{IndexedColor(208).Fg(), IndexedColor(17).Bg()}, true},
+		{"RGB", "\033[38;2;1;2;3m", SGRs{RGBColor{R: 1, G: 2, B: 3}.Fg()}, true},
+		{"RGB colon", "\033[48:2::1:2:3m", SGRs{RGBColor{R: 1, G: 2, B: 3}.Bg()}, true},
+		{"indexed colon", "\033[38:5:9m", SGRs{IndexedColor(9).Fg()}, true},
+		{"curly underline", "\033[4:3m", SGRs{Underline}, true},
+		{"no underline", "\033[4:0m", SGRs{SGR(24)}, true},
+		{"truncated color", "\033[38;5m", nil, false},
+		{"invalid color", "\033[38;5;256m", nil, false},
+		{"private", "\033[?1m", nil, false},
+		{"cursor movement", "\033[2A", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := Tokenize(tt.sequence)
+			require.Len(t, tokens, 1)
+			sgrs, ok := tokens[0].SGRs()
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, sgrs)
+		})
+	}
+}
+
+func TestTokenHyperlink(t *testing.T) {
+	url, ok := Token{TokenOSC, HyperlinkStart("https://example.com/")}.Hyperlink()
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/", url)
+
+	url, ok = Token{TokenOSC, "\033]8;id=1;https://example.com/\a"}.Hyperlink()
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/", url)
+
+	url, ok = Token{TokenOSC, HyperlinkEnd}.Hyperlink()
+	require.True(t, ok)
+	require.Equal(t, "", url)
+
+	_, ok = Token{TokenOSC, "\033]0;title\a"}.Hyperlink()
+	require.False(t, ok)
+}
+
+func TestRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Run
+	}{
+		{"empty", "", []Run{}},
+		{"plain", "plain", []Run{{Text: "plain"}}},
+		{
+			"styles",
+			"a\033[1;31mb\033[32mc\033[22md\033[0me",
+			[]Run{
+				{Text: "a"},
+				{Text: "b", SGRs: SGRs{Bold, FgRed}},
+				{Text: "c", SGRs: SGRs{Bold, FgGreen}},
+				{Text: "d", SGRs: SGRs{FgGreen}},
+				{Text: "e"},
+			},
+		},
+		{
+			"default colors",
+			"\033[31;44ma\033[39mb\033[49mc",
+			[]Run{
+				{Text: "a", SGRs: SGRs{FgRed, BgBlue}},
+				{Text: "b", SGRs: SGRs{BgBlue}},
+				{Text: "c"},
+			},
+		},
+		{
+			"extended color replaces color",
+			"\033[31ma\033[38;5;208mb",
+			[]Run{
+				{Text: "a", SGRs: SGRs{FgRed}},
+				{Text: "b", SGRs: SGRs{IndexedColor(208).Fg()}},
+			},
+		},
+		{
+			"merged",
+			"\033[31ma\033[31mb\033[2Jc",
+			[]Run{{Text: "abc", SGRs: SGRs{FgRed}}},
+		},
+		{
+			"hyperlink",
+			"see " + Hyperlink("https://example.com/", "\033[4mexample\033[0m") + " page",
+			[]Run{
+				{Text: "see "},
+				{Text: "example", SGRs: SGRs{Underline}, URL: "https://example.com/"},
+				{Text: " page"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Runs(tt.input))
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	require.Equal(t, "", Strip(""))
+	require.Equal(t, "red bold", Strip("\033[31mred\033[0m \033[1mbold\033[0m"))
+	require.Equal(t, "example", Strip(Hyperlink("https://example.com/", "example")))
+	require.Equal(t, "ab", Strip("\033[2Ja\033cb\033[?25l"))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedError string
+	}{
+		{"empty", "", ""},
+		{"SGR", "\033[31mred\033[0m\n\tindented", ""},
+		{"hyperlink", Hyperlink("https://example.com/", "example"), ""},
+		{"clear screen", "red\033[2J", `unsupported escape sequence "\x1b[2J" at offset 3`},
+		{"cursor movement", "\033[31m\033[1A", `unsupported escape sequence "\x1b[1A" at offset 5`},
+		{"title", "\033]0;title\a", `unsupported escape sequence "\x1b]0;title\a" at offset 0`},
+		{"carriage return", "a\rb", `unsupported control character '\r' at offset 1`},
+		{"backspace", "ab\b", `unsupported control character '\b' at offset 2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.input)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"SGR", "\033[31mred\033[0m\n\tindented", "\033[31mred\033[0m\n\tindented"},
+		{"hyperlink", Hyperlink("https://example.com/", "example"), Hyperlink("https://example.com/", "example")},
+		{"cursor movement", "\033[2J\033[31mred\033[H\033[0m", "\033[31mred\033[0m"},
+		{"control characters", "a\rb\bc\u0085d", "abcd"},
+		{"unterminated", "red\033[31", "red"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Sanitize(tt.input))
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{"plain", []string{"a", "b"}, []string{"a", "b"}},
+		{
+			"style across lines",
+			[]string{"\033[31;1mred", "still", "red\033[0m plain"},
+			[]string{"\033[31;1mred\033[39;22m", "\033[31;1mstill\033[39;22m", "\033[31;1mred\033[0m plain"},
+		},
+		{
+			"closed within line",
+			[]string{"\033[4ma\033[24m", "b"},
+			[]string{"\033[4ma\033[24m", "b"},
+		},
+		{
+			"hyperlink across lines",
+			[]string{HyperlinkStart("https://example.com/") + "exam", "ple" + HyperlinkEnd},
+			[]string{
+				HyperlinkStart("https://example.com/") + "exam" + HyperlinkEnd,
+				HyperlinkStart("https://example.com/") + "ple" + HyperlinkEnd,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, SplitLines(tt.lines))
+		})
+	}
+}
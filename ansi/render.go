@@ -0,0 +1,391 @@
+package ansi
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fornellas/slogxt/unicode"
+)
+
+// Palette holds the colors used to render text to HTML and SVG.
+type Palette struct {
+	// Colors of text and background when no color is set.
+	Foreground, Background RGBColor
+	// Standard (0-7) and bright (8-15) colors.
+	Colors [16]RGBColor
+}
+
+// DefaultPalette is a dark palette, with xterm default colors.
+var DefaultPalette = Palette{
+	Foreground: RGBColor{R: 229, G: 229, B: 229},
+	Background: RGBColor{R: 0, G: 0, B: 0},
+	Colors:     palette16,
+}
+
+// RenderOptions customizes RenderHTML, RenderSVG and HTMLStyleSheet.
+type RenderOptions struct {
+	// Colors to render with. When nil, DefaultPalette is used.
+	Palette *Palette
+	// Prefix for CSS class names. When empty, "ansi" is used.
+	ClassPrefix string
+	// SVG font size, in pixels. When 0, 14 is used.
+	FontSize int
+}
+
+func (o *RenderOptions) palette() *Palette {
+	if o == nil || o.Palette == nil {
+		return &DefaultPalette
+	}
+	return o.Palette
+}
+
+func (o *RenderOptions) classPrefix() string {
+	if o == nil || o.ClassPrefix == "" {
+		return "ansi"
+	}
+	return o.ClassPrefix
+}
+
+func (o *RenderOptions) fontSize() int {
+	if o == nil || o.FontSize == 0 {
+		return 14
+	}
+	return o.FontSize
+}
+
+// CSS returns the color as a CSS hex color, eg: "#ff8000".
+func (c RGBColor) CSS() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// renderColor is either one of the 16 palette colors, which are rendered with CSS classes, or a
+// truecolor, which is rendered inline.
+type renderColor struct {
+	set     bool
+	indexed bool
+	index   uint8
+	rgb     RGBColor
+}
+
+func (c renderColor) resolve(palette *Palette, fallback RGBColor) RGBColor {
+	switch {
+	case !c.set:
+		return fallback
+	case c.indexed:
+		return palette.Colors[c.index]
+	default:
+		return c.rgb
+	}
+}
+
+// renderStyle is the display attributes of a Run, as rendered to HTML and SVG.
+type renderStyle struct {
+	bold, dim, italic, underline, blink, reverse, hidden, strike bool
+	fg, bg                                                       renderColor
+}
+
+func sgrColor(s SGR) renderColor {
+	switch s & sgrExtendedMask {
+	case sgrFgIndexed, sgrBgIndexed:
+		if index := uint8(s & 0xff); index < 16 {
+			return renderColor{set: true, indexed: true, index: index}
+		}
+		return renderColor{set: true, rgb: IndexedColor(s & 0xff).RGB()}
+	case sgrFgRGB, sgrBgRGB:
+		return renderColor{set: true, rgb: RGBColor{R: uint8(s >> 16), G: uint8(s >> 8), B: uint8(s)}}
+	}
+	switch {
+	case 30 <= s && s <= 37:
+		return renderColor{set: true, indexed: true, index: uint8(s - 30)}
+	case 40 <= s && s <= 47:
+		return renderColor{set: true, indexed: true, index: uint8(s - 40)}
+	case 90 <= s && s <= 97:
+		return renderColor{set: true, indexed: true, index: uint8(s - 90 + 8)}
+	default:
+		return renderColor{set: true, indexed: true, index: uint8(s - 100 + 8)}
+	}
+}
+
+func newRenderStyle(sgrs SGRs) renderStyle {
+	var style renderStyle
+	for _, sgr := range sgrs {
+		switch sgrKind(sgr) {
+		case Bold:
+			style.bold = true
+		case Dim:
+			style.dim = true
+		case Italic:
+			style.italic = true
+		case Underline:
+			style.underline = true
+		case Blink:
+			style.blink = true
+		case Reverse:
+			style.reverse = true
+		case Hidden:
+			style.hidden = true
+		case Strike:
+			style.strike = true
+		case 38:
+			style.fg = sgrColor(sgr)
+		case 48:
+			style.bg = sgrColor(sgr)
+		}
+	}
+	if style.reverse {
+		style.fg, style.bg = style.bg, style.fg
+	}
+	return style
+}
+
+// classes returns the CSS classes and inline CSS declarations for the style.
+func (s renderStyle) classes(prefix string, palette *Palette) ([]string, []string) {
+	classes := []string{}
+	declarations := []string{}
+	for _, attribute := range []struct {
+		enabled bool
+		name    string
+	}{
+		{s.bold, "bold"},
+		{s.dim, "dim"},
+		{s.italic, "italic"},
+		{s.underline, "underline"},
+		{s.blink, "blink"},
+		{s.hidden, "hidden"},
+		{s.strike, "strike"},
+	} {
+		if attribute.enabled {
+			classes = append(classes, prefix+"-"+attribute.name)
+		}
+	}
+	color := func(c renderColor, kind, property string, reversed RGBColor) {
+		switch {
+		case c.indexed:
+			classes = append(classes, fmt.Sprintf("%s-%s-%d", prefix, kind, c.index))
+		case c.set:
+			declarations = append(declarations, property+":"+c.rgb.CSS())
+		case s.reverse:
+			// The reversed default color is not covered by any class.
+			declarations = append(declarations, property+":"+reversed.CSS())
+		}
+	}
+	color(s.fg, "fg", "color", palette.Background)
+	color(s.bg, "bg", "background-color", palette.Foreground)
+	return classes, declarations
+}
+
+// safeURL returns whether a hyperlink URL can be rendered as a link, which excludes schemes that
+// run code, such as "javascript:".
+func safeURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "file", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+// HTMLStyleSheet returns the CSS rules for the classes used by RenderHTML.
+func HTMLStyleSheet(opts *RenderOptions) string {
+	prefix := opts.classPrefix()
+	palette := opts.palette()
+	var b strings.Builder
+	fmt.Fprintf(
+		&b, "pre.%s { color: %s; background-color: %s; }\n",
+		prefix, palette.Foreground.CSS(), palette.Background.CSS(),
+	)
+	fmt.Fprintf(&b, ".%s-bold { font-weight: bold; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-dim { opacity: 0.5; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-italic { font-style: italic; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-underline { text-decoration: underline; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-blink { text-decoration: blink; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-hidden { visibility: hidden; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-strike { text-decoration: line-through; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-underline.%s-strike { text-decoration: underline line-through; }\n", prefix, prefix)
+	for i, color := range palette.Colors {
+		fmt.Fprintf(&b, ".%s-fg-%d { color: %s; }\n", prefix, i, color.CSS())
+	}
+	for i, color := range palette.Colors {
+		fmt.Fprintf(&b, ".%s-bg-%d { background-color: %s; }\n", prefix, i, color.CSS())
+	}
+	return b.String()
+}
+
+// RenderHTML writes s, text with SGR sequences and OSC 8 hyperlinks, as an HTML <pre> element.
+// Display attributes and the 16 standard colors are rendered with CSS classes, defined by
+// HTMLStyleSheet, while 256 colors and truecolors are rendered with inline styles. Hyperlinks are
+// rendered as links, except for URL schemes that can run code. Other escape sequences are
+// ignored.
+func RenderHTML(w io.Writer, s string, opts *RenderOptions) error {
+	prefix := opts.classPrefix()
+	palette := opts.palette()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<pre class="%s">`, prefix)
+	for _, run := range Runs(s) {
+		link := run.URL != "" && safeURL(run.URL)
+		if link {
+			fmt.Fprintf(&b, `<a href="%s">`, html.EscapeString(run.URL))
+		}
+		classes, declarations := newRenderStyle(run.SGRs).classes(prefix, palette)
+		span := len(classes) > 0 || len(declarations) > 0
+		if span {
+			b.WriteString("<span")
+			if len(classes) > 0 {
+				fmt.Fprintf(&b, ` class="%s"`, strings.Join(classes, " "))
+			}
+			if len(declarations) > 0 {
+				fmt.Fprintf(&b, ` style="%s"`, strings.Join(declarations, ";"))
+			}
+			b.WriteString(">")
+		}
+		b.WriteString(html.EscapeString(Sanitize(run.Text)))
+		if span {
+			b.WriteString("</span>")
+		}
+		if link {
+			b.WriteString("</a>")
+		}
+	}
+	b.WriteString("</pre>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// svgLines splits runs into lines of runs.
+func svgLines(runs []Run) [][]Run {
+	lines := [][]Run{{}}
+	for _, run := range runs {
+		for i, text := range strings.Split(run.Text, "\n") {
+			if i > 0 {
+				lines = append(lines, []Run{})
+			}
+			if text != "" {
+				run.Text = text
+				lines[len(lines)-1] = append(lines[len(lines)-1], run)
+			}
+		}
+	}
+	if len(lines) > 1 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// svgNumber formats a length with at most 2 decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// RenderSVG writes s, text with SGR sequences and OSC 8 hyperlinks, as a standalone SVG image of
+// a terminal displaying it. Text is laid out in a monospace font, by display width. Hyperlinks are
+// rendered as links, except for URL schemes that can run code. Other escape sequences are
+// ignored.
+func RenderSVG(w io.Writer, s string, opts *RenderOptions) error {
+	prefix := opts.classPrefix()
+	palette := opts.palette()
+	fontSize := float64(opts.fontSize())
+	columnWidth := fontSize * 0.6
+	lineHeight := fontSize * 1.2
+	padding := fontSize
+
+	lines := svgLines(Runs(strings.ReplaceAll(s, "\t", "        ")))
+	columns := 0
+	for _, line := range lines {
+		width := 0
+		for _, run := range line {
+			width += unicode.StringWidth(run.Text)
+		}
+		columns = max(columns, width)
+	}
+	width := float64(columns)*columnWidth + 2*padding
+	height := float64(len(lines))*lineHeight + 2*padding
+
+	var b strings.Builder
+	fmt.Fprintf(
+		&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height),
+	)
+	b.WriteString("<style>\n")
+	fmt.Fprintf(
+		&b, ".%s { font-family: monospace; font-size: %spx; fill: %s; white-space: pre; }\n",
+		prefix, svgNumber(fontSize), palette.Foreground.CSS(),
+	)
+	fmt.Fprintf(&b, ".%s-bold { font-weight: bold; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-dim { opacity: 0.5; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-italic { font-style: italic; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-underline { text-decoration: underline; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-hidden { visibility: hidden; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-strike { text-decoration: line-through; }\n", prefix)
+	fmt.Fprintf(&b, ".%s-underline.%s-strike { text-decoration: underline line-through; }\n", prefix, prefix)
+	for i, color := range palette.Colors {
+		fmt.Fprintf(&b, ".%s-fg-%d { fill: %s; }\n", prefix, i, color.CSS())
+	}
+	b.WriteString("</style>\n")
+	fmt.Fprintf(
+		&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", palette.Background.CSS(),
+	)
+
+	for i, line := range lines {
+		y := padding + float64(i)*lineHeight
+		column := 0
+		for _, run := range line {
+			runWidth := unicode.StringWidth(run.Text)
+			style := newRenderStyle(run.SGRs)
+			if style.bg.set || style.reverse {
+				fmt.Fprintf(
+					&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+					svgNumber(padding+float64(column)*columnWidth), svgNumber(y),
+					svgNumber(float64(runWidth)*columnWidth), svgNumber(lineHeight),
+					style.bg.resolve(palette, palette.Foreground).CSS(),
+				)
+			}
+			column += runWidth
+		}
+		fmt.Fprintf(&b, `<text class="%s" y="%s">`, prefix, svgNumber(y+fontSize))
+		column = 0
+		for _, run := range line {
+			// Backgrounds are drawn as rectangles, so text only takes the foreground color.
+			style := newRenderStyle(run.SGRs)
+			if style.reverse && !style.fg.set {
+				style.fg = renderColor{set: true, rgb: palette.Background}
+			}
+			style.bg = renderColor{}
+			style.reverse = false
+			classes, declarations := style.classes(prefix, palette)
+			for j, declaration := range declarations {
+				if color, ok := strings.CutPrefix(declaration, "color:"); ok {
+					declarations[j] = "fill:" + color
+				}
+			}
+			link := run.URL != "" && safeURL(run.URL)
+			if link {
+				fmt.Fprintf(&b, `<a href="%s">`, html.EscapeString(run.URL))
+			}
+			fmt.Fprintf(&b, `<tspan x="%s"`, svgNumber(padding+float64(column)*columnWidth))
+			if len(classes) > 0 {
+				fmt.Fprintf(&b, ` class="%s"`, strings.Join(classes, " "))
+			}
+			if len(declarations) > 0 {
+				fmt.Fprintf(&b, ` style="%s"`, strings.Join(declarations, ";"))
+			}
+			fmt.Fprintf(&b, ">%s</tspan>", html.EscapeString(Sanitize(run.Text)))
+			if link {
+				b.WriteString("</a>")
+			}
+			column += unicode.StringWidth(run.Text)
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
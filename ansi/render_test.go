@@ -0,0 +1,130 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     *RenderOptions
+		expected string
+	}{
+		{"empty", "", nil, `<pre class="ansi"></pre>`},
+		{"plain", "a < b & c", nil, `<pre class="ansi">a &lt; b &amp; c</pre>`},
+		{
+			"standard colors",
+			"\033[1;31mred\033[0m \033[94;42mblue\033[0m",
+			nil,
+			`<pre class="ansi"><span class="ansi-bold ansi-fg-1">red</span> ` +
+				`<span class="ansi-fg-12 ansi-bg-2">blue</span></pre>`,
+		},
+		{
+			"extended colors",
+			"\033[38;5;208ma\033[48;2;1;2;3mb",
+			nil,
+			`<pre class="ansi"><span style="color:#ff8700">a</span>` +
+				`<span style="color:#ff8700;background-color:#010203">b</span></pre>`,
+		},
+		{
+			"indexed standard color",
+			"\033[38;5;3ma",
+			nil,
+			`<pre class="ansi"><span class="ansi-fg-3">a</span></pre>`,
+		},
+		{
+			"reverse",
+			"\033[7ma\033[31mb",
+			nil,
+			`<pre class="ansi"><span style="color:#000000;background-color:#e5e5e5">a</span>` +
+				`<span class="ansi-bg-1" style="color:#000000">b</span></pre>`,
+		},
+		{
+			"hyperlink",
+			Hyperlink("https://example.com/?a=1&b=2", "\033[4mexample\033[0m"),
+			nil,
+			`<pre class="ansi"><a href="https://example.com/?a=1&amp;b=2">` +
+				`<span class="ansi-underline">example</span></a></pre>`,
+		},
+		{
+			"unsafe hyperlink",
+			Hyperlink("javascript:alert(1)", "example"),
+			nil,
+			`<pre class="ansi">example</pre>`,
+		},
+		{
+			"class prefix",
+			"\033[2mdim",
+			&RenderOptions{ClassPrefix: "log"},
+			`<pre class="log"><span class="log-dim">dim</span></pre>`,
+		},
+		{
+			"other sequences",
+			"\033[2Ja\033[1Ab",
+			nil,
+			`<pre class="ansi">ab</pre>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, RenderHTML(&buf, tt.input, tt.opts))
+			require.Equal(t, tt.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestHTMLStyleSheet(t *testing.T) {
+	styleSheet := HTMLStyleSheet(&RenderOptions{
+		Palette: &Palette{
+			Foreground: RGBColor{R: 0x10, G: 0x20, B: 0x30},
+			Background: RGBColor{R: 0xff, G: 0xff, B: 0xff},
+			Colors:     palette16,
+		},
+	})
+	require.Contains(t, styleSheet, "pre.ansi { color: #102030; background-color: #ffffff; }\n")
+	require.Contains(t, styleSheet, ".ansi-bold { font-weight: bold; }\n")
+	require.Contains(t, styleSheet, ".ansi-fg-1 { color: #cd0000; }\n")
+	require.Contains(t, styleSheet, ".ansi-bg-15 { background-color: #ffffff; }\n")
+}
+
+func TestRenderSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderSVG(
+		&buf,
+		"\033[1;31mERROR\033[0m <fail>\n  \033[44mkey\033[0m: 世界\n",
+		&RenderOptions{FontSize: 10},
+	))
+	svg := buf.String()
+
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	// 2 lines of at most 12 columns, of 6px per column and 12px per line, with 10px padding
+	require.True(t, strings.HasPrefix(
+		svg, `<svg xmlns="http://www.w3.org/2000/svg" width="92" height="44" viewBox="0 0 92 44">`,
+	))
+	require.Contains(t, svg, `.ansi-fg-1 { fill: #cd0000; }`)
+	require.Contains(t, svg,
+		`<text class="ansi" y="20"><tspan x="10" class="ansi-bold ansi-fg-1">ERROR</tspan>`+
+			`<tspan x="40"> &lt;fail&gt;</tspan></text>`,
+	)
+	require.Contains(t, svg, `<rect x="22" y="22" width="18" height="12" fill="#0000ee"/>`)
+	require.Contains(t, svg,
+		`<text class="ansi" y="32"><tspan x="10">  </tspan><tspan x="22">key</tspan>`+
+			`<tspan x="40">: 世界</tspan></text>`,
+	)
+}
@@ -0,0 +1,362 @@
+package ansi
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Color is a terminal color, that can be set as foreground or background.
+type Color interface {
+	// Fg returns the SGR that sets the foreground to this color.
+	Fg() SGR
+	// Bg returns the SGR that sets the background to this color.
+	Bg() SGR
+}
+
+// BasicColor is one of the 8 standard (3-bit) or 8 bright (4-bit) colors, whose actual color is
+// defined by the terminal.
+type BasicColor uint8
+
+const (
+	Black BasicColor = iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+	BrightBlack
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+// Fg returns the SGR that sets the foreground to this color, eg: FgRed for Red.
+func (c BasicColor) Fg() SGR {
+	return indexedColor16(IndexedColor(c&0xf), false)
+}
+
+// Bg returns the SGR that sets the background to this color, eg: BgRed for Red.
+func (c BasicColor) Bg() SGR {
+	return indexedColor16(IndexedColor(c&0xf), true)
+}
+
+// Style is a set of display attributes, with at most one foreground and one background color.
+// Unlike SGRs, styles can be merged, and written with the minimal sequences to transition
+// between them, instead of a full Reset.
+//
+// The zero Style has no attributes and writes no escape sequences, so it is the fallback for
+// output without colors.
+type Style struct {
+	sgrs SGRs
+}
+
+// NewStyle returns a style with the given attributes. Later attributes take precedence over
+// earlier ones of the same kind, eg: NewStyle(FgRed, FgBlue) is blue, and Reset clears all
+// attributes before it.
+func NewStyle(sgrs ...SGR) Style {
+	return Style{sgrs: applySGRs(nil, sgrs)}
+}
+
+func (s Style) with(sgr SGR) Style {
+	return Style{sgrs: applySGRs(s.sgrs, SGRs{sgr})}
+}
+
+// Fg returns a copy of the style with the foreground color set to c.
+func (s Style) Fg(c Color) Style {
+	return s.with(c.Fg())
+}
+
+// Bg returns a copy of the style with the background color set to c.
+func (s Style) Bg(c Color) Style {
+	return s.with(c.Bg())
+}
+
+// Bold returns a copy of the style with bold text.
+func (s Style) Bold() Style {
+	return s.with(Bold)
+}
+
+// Dim returns a copy of the style with dim text.
+func (s Style) Dim() Style {
+	return s.with(Dim)
+}
+
+// Italic returns a copy of the style with italic text.
+func (s Style) Italic() Style {
+	return s.with(Italic)
+}
+
+// Underline returns a copy of the style with underlined text.
+func (s Style) Underline() Style {
+	return s.with(Underline)
+}
+
+// Blink returns a copy of the style with blinking text.
+func (s Style) Blink() Style {
+	return s.with(Blink)
+}
+
+// Reverse returns a copy of the style with foreground and background colors swapped.
+func (s Style) Reverse() Style {
+	return s.with(Reverse)
+}
+
+// Hidden returns a copy of the style with hidden text.
+func (s Style) Hidden() Style {
+	return s.with(Hidden)
+}
+
+// Strike returns a copy of the style with crossed out text.
+func (s Style) Strike() Style {
+	return s.with(Strike)
+}
+
+// Merge returns a copy of the style with the attributes of other added, so that other inherits
+// the attributes of s it does not set, eg: a red foreground merged with a blue one is blue, while
+// bold merged with a blue foreground is bold and blue.
+func (s Style) Merge(other Style) Style {
+	return Style{sgrs: applySGRs(s.sgrs, other.sgrs)}
+}
+
+// SGRs returns the display attributes of the style.
+func (s Style) SGRs() SGRs {
+	return slices.Clone(s.sgrs)
+}
+
+// IsZero returns whether the style has no attributes.
+func (s Style) IsZero() bool {
+	return len(s.sgrs) == 0
+}
+
+// Equal returns whether both styles have the same attributes.
+func (s Style) Equal(other Style) bool {
+	return slices.Equal(s.sgrs, other.sgrs)
+}
+
+// String returns the escape sequence that sets all display attributes of the style, or an empty
+// string for the zero Style. It does not reset attributes set before it.
+func (s Style) String() string {
+	return s.sgrs.String()
+}
+
+// resetSGR returns the SGR that resets the kind of display attribute of sgr, or Reset if there
+// is none.
+func resetSGR(sgr SGR) SGR {
+	switch sgrKind(sgr) {
+	case Bold, Dim:
+		return 22
+	case Italic, Underline, Blink, Reverse, Hidden, Strike:
+		return sgrKind(sgr) + 20
+	case 38:
+		return 39
+	case 48:
+		return 49
+	default:
+		return Reset
+	}
+}
+
+// Transition returns the shortest escape sequence that changes the display attributes from this
+// style to the other one: attributes of this style not in the other are reset individually, eg:
+// with "\033[22m" for bold, and attributes of the other style not in this one are set. When an
+// attribute can not be reset individually, a full Reset is used.
+func (s Style) Transition(to Style) string {
+	var transition SGRs
+	var reset22 bool
+	for _, sgr := range s.sgrs {
+		if slices.Contains(to.sgrs, sgr) {
+			continue
+		}
+		reset := resetSGR(sgr)
+		if reset == Reset {
+			if to.IsZero() {
+				return Reset.String()
+			}
+			return (append(SGRs{Reset}, to.sgrs...)).String()
+		}
+		if slices.Contains(transition, reset) {
+			continue
+		}
+		// Reset colors and attributes that are not replaced by the other style.
+		if reset != 22 && slices.ContainsFunc(to.sgrs, func(t SGR) bool { return sgrKind(t) == sgrKind(sgr) }) {
+			continue
+		}
+		reset22 = reset22 || reset == 22
+		transition = append(transition, reset)
+	}
+	for _, sgr := range to.sgrs {
+		// Resetting bold also resets dim, and vice versa.
+		if slices.Contains(s.sgrs, sgr) && !(reset22 && (sgr == Bold || sgr == Dim)) {
+			continue
+		}
+		transition = append(transition, sgr)
+	}
+	return transition.String()
+}
+
+// Downsample returns a copy of the style, with all colors downsampled to the given color level
+// with [SGR.Downsample].
+func (s Style) Downsample(level ColorLevel) Style {
+	return Style{sgrs: s.sgrs.Downsample(level)}
+}
+
+// Sprintf works similar to fmt.Sprintf, but it wraps the formatted text with the style and the
+// transition back to no attributes. The zero Style behaves as fmt.Sprintf.
+func (s Style) Sprintf(format string, a ...any) string {
+	if s.IsZero() {
+		return fmt.Sprintf(format, a...)
+	}
+	return s.String() + fmt.Sprintf(format, a...) + s.Transition(Style{})
+}
+
+// Fprintf works similar to fmt.Fprintf, but it wraps the formatted text with the style and the
+// transition back to no attributes. The zero Style behaves as fmt.Fprintf.
+func (s Style) Fprintf(w io.Writer, format string, a ...any) (int, error) {
+	if s.IsZero() {
+		return fmt.Fprintf(w, format, a...)
+	}
+	return fmt.Fprintf(w, "%s%s%s", s.String(), fmt.Sprintf(format, a...), s.Transition(Style{}))
+}
+
+var basicColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+var styleAttributeNames = map[string]SGR{
+	"bold":      Bold,
+	"dim":       Dim,
+	"italic":    Italic,
+	"underline": Underline,
+	"blink":     Blink,
+	"reverse":   Reverse,
+	"hidden":    Hidden,
+	"strike":    Strike,
+}
+
+// StyleNone is the text for the zero Style.
+const StyleNone = "none"
+
+// parseColor parses a color name, a 256 colors palette index or a #rrggbb truecolor.
+func parseColor(s string) (Color, error) {
+	if i := slices.Index(basicColorNames, s); i >= 0 {
+		return BasicColor(i), nil
+	}
+	if s == "gray" || s == "grey" {
+		return BrightBlack, nil
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) != 6 {
+			return nil, fmt.Errorf("invalid color %#v, expected #rrggbb", s)
+		}
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color %#v, expected #rrggbb", s)
+		}
+		return RGBColor{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}, nil
+	}
+	if index, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return IndexedColor(index), nil
+	}
+	return nil, fmt.Errorf(
+		"invalid color %#v, valid options are %s, 0-255 or #rrggbb", s, strings.Join(basicColorNames, ", "),
+	)
+}
+
+// ParseStyle parses a style from a comma separated list of attributes and colors, eg:
+// "red,bold" or "#ff8700,bg-17,underline". Attributes are bold, dim, italic, underline, blink,
+// reverse, hidden and strike. Colors are set as foreground, or as background with a "bg-"
+// prefix, and can be a name (black, red, green, yellow, blue, magenta, cyan, white, their
+// "bright-" variants and gray), a 256 colors palette index or a #rrggbb truecolor. The
+// StyleNone text is the zero Style.
+func ParseStyle(s string) (Style, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == StyleNone {
+		return Style{}, nil
+	}
+	var style Style
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if sgr, ok := styleAttributeNames[field]; ok {
+			style = style.with(sgr)
+			continue
+		}
+		bgField, bg := strings.CutPrefix(field, "bg-")
+		color, err := parseColor(bgField)
+		if err != nil {
+			return Style{}, fmt.Errorf("invalid style %#v: %w", s, err)
+		}
+		if bg {
+			style = style.Bg(color)
+		} else {
+			style = style.Fg(color)
+		}
+	}
+	return style, nil
+}
+
+// sgrText returns the ParseStyle text for sgr.
+func sgrText(sgr SGR) (string, bool) {
+	for name, attribute := range styleAttributeNames {
+		if sgr == attribute {
+			return name, true
+		}
+	}
+	prefix := ""
+	if sgrKind(sgr) == 48 {
+		prefix = "bg-"
+	}
+	switch {
+	case sgr >= FgBlack && sgr <= FgWhite:
+		return basicColorNames[sgr-FgBlack], true
+	case sgr >= BgBlack && sgr <= BgWhite:
+		return prefix + basicColorNames[sgr-BgBlack], true
+	case sgr >= FgDarkGray && sgr <= FgLightWhite:
+		return basicColorNames[8+sgr-FgDarkGray], true
+	case sgr >= BgDarkGray && sgr <= BgLightWhite:
+		return prefix + basicColorNames[8+sgr-BgDarkGray], true
+	}
+	switch sgr & sgrExtendedMask {
+	case sgrFgIndexed, sgrBgIndexed:
+		return fmt.Sprintf("%s%d", prefix, uint(sgr&0xff)), true
+	case sgrFgRGB, sgrBgRGB:
+		return fmt.Sprintf("%s#%06x", prefix, uint(sgr&0xffffff)), true
+	}
+	return "", false
+}
+
+// MarshalText implements encoding.TextMarshaler, with the format of ParseStyle.
+func (s Style) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return []byte(StyleNone), nil
+	}
+	fields := make([]string, 0, len(s.sgrs))
+	for _, sgr := range s.sgrs {
+		text, ok := sgrText(sgr)
+		if !ok {
+			return nil, fmt.Errorf("style attribute %d can not be represented as text", uint(sgr))
+		}
+		fields = append(fields, text)
+	}
+	return []byte(strings.Join(fields, ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with ParseStyle.
+func (s *Style) UnmarshalText(text []byte) error {
+	style, err := ParseStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*s = style
+	return nil
+}
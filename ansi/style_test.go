@@ -0,0 +1,149 @@
+package ansi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicColor(t *testing.T) {
+	require.Equal(t, FgRed, Red.Fg())
+	require.Equal(t, BgRed, Red.Bg())
+	require.Equal(t, FgDarkGray, BrightBlack.Fg())
+	require.Equal(t, BgLightWhite, BrightWhite.Bg())
+}
+
+func TestStyle(t *testing.T) {
+	t.Run("builder", func(t *testing.T) {
+		style := NewStyle().Fg(Red).Bg(IndexedColor(17)).Bold().Underline()
+		require.Equal(t, SGRs{FgRed, IndexedColor(17).Bg(), Bold, Underline}, style.SGRs())
+		require.Equal(t, "\033[31;48;5;17;1;4m", style.String())
+	})
+
+	t.Run("same kind replaces", func(t *testing.T) {
+		require.Equal(t, SGRs{Bold, FgBlue}, NewStyle(FgRed, Bold).Fg(Blue).SGRs())
+		require.Equal(t, SGRs{RGBColor{R: 1, G: 2, B: 3}.Bg()}, NewStyle(BgRed).Bg(RGBColor{R: 1, G: 2, B: 3}).SGRs())
+		require.Equal(t, SGRs{Italic}, NewStyle(Bold, Reset, Italic).SGRs())
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		require.True(t, Style{}.IsZero())
+		require.True(t, NewStyle().IsZero())
+		require.False(t, NewStyle().Dim().IsZero())
+		require.Equal(t, "", Style{}.String())
+		require.Equal(t, "plain 1", Style{}.Sprintf("plain %d", 1))
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		base := NewStyle().Fg(Red).Bold()
+		require.Equal(t, SGRs{Bold, FgBlue}, base.Merge(NewStyle().Fg(Blue)).SGRs())
+		require.Equal(t, SGRs{FgRed, Bold, Italic}, base.Merge(NewStyle().Italic()).SGRs())
+		require.Equal(t, base.SGRs(), base.Merge(Style{}).SGRs())
+		require.True(t, base.Equal(Style{}.Merge(base)))
+	})
+
+	t.Run("Transition", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			from     Style
+			to       Style
+			expected string
+		}{
+			{"zero to zero", Style{}, Style{}, ""},
+			{"same", NewStyle(FgRed), NewStyle(FgRed), ""},
+			{"to zero", NewStyle(FgRed, BgBlue, Bold, Underline), Style{}, "\033[39;49;22;24m"},
+			{"from zero", Style{}, NewStyle(FgRed, Bold), "\033[31;1m"},
+			{"replace color", NewStyle(FgRed, Bold), NewStyle(FgBlue, Bold), "\033[34m"},
+			{"drop attribute", NewStyle(FgRed, Italic), NewStyle(FgRed), "\033[23m"},
+			{"bold to dim", NewStyle(Bold), NewStyle(Dim), "\033[22;2m"},
+			{"bold and dim to dim", NewStyle(Bold, Dim), NewStyle(Dim), "\033[22;2m"},
+			{"no individual reset", NewStyle(SGR(53), FgRed), NewStyle(FgRed), "\033[0;31m"},
+			{"no individual reset to zero", NewStyle(SGR(53)), Style{}, "\033[0m"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Equal(t, tt.expected, tt.from.Transition(tt.to))
+			})
+		}
+	})
+
+	t.Run("Downsample", func(t *testing.T) {
+		style := NewStyle().Fg(RGBColor{R: 255, G: 0, B: 0}).Bold()
+		require.Equal(t, SGRs{FgLightRed, Bold}, style.Downsample(ColorLevel16).SGRs())
+		require.Equal(t, style.SGRs(), style.Downsample(ColorLevelTrueColor).SGRs())
+	})
+
+	t.Run("Sprintf", func(t *testing.T) {
+		style := NewStyle().Fg(Red).Bold()
+		require.Equal(t, "\033[31;1mHello World\033[39;22m", style.Sprintf("Hello %s", "World"))
+	})
+
+	t.Run("Fprintf", func(t *testing.T) {
+		var buf bytes.Buffer
+		style := NewStyle().Dim()
+		n, err := style.Fprintf(&buf, "Hello %s", "World")
+		require.NoError(t, err)
+		require.Equal(t, "\033[2mHello World\033[22m", buf.String())
+		require.Equal(t, buf.Len(), n)
+	})
+}
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      Style
+		expectedError string
+	}{
+		{"empty", "", Style{}, ""},
+		{"none", "none", Style{}, ""},
+		{"attributes", "bold, Italic", NewStyle(Bold, Italic), ""},
+		{"basic colors", "red,bg-bright-blue", NewStyle().Fg(Red).Bg(BrightBlue), ""},
+		{"gray", "gray", NewStyle().Fg(BrightBlack), ""},
+		{"indexed", "208,bg-17", NewStyle().Fg(IndexedColor(208)).Bg(IndexedColor(17)), ""},
+		{"RGB", "#FF8700,bold", NewStyle().Fg(RGBColor{R: 0xff, G: 0x87}).Bold(), ""},
+		{"later color replaces", "red,blue", NewStyle().Fg(Blue), ""},
+		{
+			"invalid color", "orange", Style{},
+			`invalid style "orange": invalid color "orange", valid options are black, red, green, yellow, ` +
+				`blue, magenta, cyan, white, bright-black, bright-red, bright-green, bright-yellow, ` +
+				`bright-blue, bright-magenta, bright-cyan, bright-white, 0-255 or #rrggbb`,
+		},
+		{"invalid RGB", "bg-#fff", Style{}, `invalid style "bg-#fff": invalid color "#fff", expected #rrggbb`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, err := ParseStyle(tt.input)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected.SGRs(), style.SGRs())
+			} else {
+				require.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestStyleText(t *testing.T) {
+	for _, style := range []Style{
+		{},
+		NewStyle(Bold, Dim, Italic, Underline, Blink, Reverse, Hidden, Strike),
+		NewStyle(FgRed, BgWhite),
+		NewStyle(FgLightCyan, BgDarkGray),
+		NewStyle().Fg(IndexedColor(3)).Bg(RGBColor{R: 1, G: 2, B: 3}),
+	} {
+		text, err := style.MarshalText()
+		require.NoError(t, err)
+		var unmarshaled Style
+		require.NoError(t, unmarshaled.UnmarshalText(text))
+		require.True(t, style.Equal(unmarshaled), string(text))
+	}
+
+	text, err := NewStyle(FgRed, Bold).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "red,bold", string(text))
+
+	_, err = NewStyle(SGR(53)).MarshalText()
+	require.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fornellas/slogxt/log"
+)
+
+var DefaultBackground = log.BackgroundAuto
+
+// BackgroundValue implements [pflag.Value] interface for [log.Background].
+type BackgroundValue log.Background
+
+func NewBackgroundValue() *BackgroundValue {
+	backgroundValue := BackgroundValue(DefaultBackground)
+	return &backgroundValue
+}
+
+func (b BackgroundValue) String() string {
+	return log.Background(b).String()
+}
+
+func (b *BackgroundValue) Set(value string) error {
+	return (*log.Background)(b).UnmarshalText([]byte(value))
+}
+
+func (b *BackgroundValue) Reset() {
+	if err := b.Set(DefaultBackground.String()); err != nil {
+		panic(err)
+	}
+}
+
+func (b BackgroundValue) Type() string {
+	return fmt.Sprintf("[%s]", strings.Join(log.BackgroundNames(), "|"))
+}
+
+func (b BackgroundValue) Background() log.Background {
+	return log.Background(b)
+}
@@ -0,0 +1,40 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fornellas/slogxt/log"
+)
+
+var DefaultColorMode = log.ColorModeAuto
+
+// ColorModeValue implements [pflag.Value] interface for [log.ColorMode].
+type ColorModeValue log.ColorMode
+
+func NewColorModeValue() *ColorModeValue {
+	colorModeValue := ColorModeValue(DefaultColorMode)
+	return &colorModeValue
+}
+
+func (c ColorModeValue) String() string {
+	return log.ColorMode(c).String()
+}
+
+func (c *ColorModeValue) Set(value string) error {
+	return (*log.ColorMode)(c).UnmarshalText([]byte(value))
+}
+
+func (c *ColorModeValue) Reset() {
+	if err := c.Set(DefaultColorMode.String()); err != nil {
+		panic(err)
+	}
+}
+
+func (c ColorModeValue) Type() string {
+	return fmt.Sprintf("[%s]", strings.Join(log.ColorModeNames(), "|"))
+}
+
+func (c ColorModeValue) ColorMode() log.ColorMode {
+	return log.ColorMode(c)
+}
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 
 	"github.com/spf13/cobra"
+
+	"github.com/fornellas/slogxt/log"
 )
 
 var logLevelValue = NewLogLevelValue()
@@ -14,8 +16,13 @@ var logHandlerValue = NewLogHandlerValue()
 var defaultLogHandlerAddSource = false
 var logHandlerAddSource = defaultLogHandlerAddSource
 
-var defaultLogHandlerTerminlTime = false
-var logHandlerTerminalTime = defaultLogHandlerTerminlTime
+var logHandlerTerminalTimeValue = NewTimeValue()
+
+var logHandlerTerminalColorValue = NewColorModeValue()
+
+var logHandlerTerminalThemeValue = NewThemeValue()
+
+var logHandlerTerminalBackgroundValue = NewBackgroundValue()
 
 var defaultLogHandlerTerminalForceColor = false
 var logHandlerTerminalForceColor = defaultLogHandlerTerminalForceColor
@@ -34,14 +41,30 @@ func AddLoggerFlags(cmd *cobra.Command) {
 		"Include source code position of the log statement when logging",
 	)
 
-	cmd.PersistentFlags().BoolVarP(
-		&logHandlerTerminalTime, "log-handler-terminal-time", "", defaultLogHandlerTerminlTime,
-		"Enable time for terminal handlers",
+	cmd.PersistentFlags().VarP(
+		logHandlerTerminalTimeValue, "log-handler-terminal-time", "",
+		"Time for terminal handlers; absolute date and time, elapsed since the process started or delta since the previous record",
+	)
+	cmd.PersistentFlags().Lookup("log-handler-terminal-time").NoOptDefVal = log.TimeModeAbsolute.String()
+
+	cmd.PersistentFlags().VarP(
+		logHandlerTerminalColorValue, "log-handler-terminal-color", "",
+		"When to use ANSI colors for terminal handlers; auto honors NO_COLOR, FORCE_COLOR, CLICOLOR, CLICOLOR_FORCE and TERM",
+	)
+
+	cmd.PersistentFlags().VarP(
+		logHandlerTerminalThemeValue, "log-handler-terminal-theme", "",
+		"Color theme for terminal handlers; a built-in theme name, a .toml or .json theme file, or a spec such as \"level.error=red,bold:attr.key=cyan\"",
+	)
+
+	cmd.PersistentFlags().VarP(
+		logHandlerTerminalBackgroundValue, "log-handler-terminal-background", "",
+		"Terminal background that terminal handlers pick their default theme for; auto honors COLORFGBG, query also queries the terminal",
 	)
 
 	cmd.PersistentFlags().BoolVarP(
 		&logHandlerTerminalForceColor, "log-handler-terminal-force-color", "", defaultLogHandlerTerminalForceColor,
-		"Force ANSI colors even when terminal is not detected",
+		"Force ANSI colors even when terminal is not detected; same as --log-handler-terminal-color=always",
 	)
 }
 
@@ -52,8 +75,13 @@ func GetLogger(writer io.Writer) *slog.Logger {
 		LogHandlerValueOptions{
 			Level:              logLevelValue.Level(),
 			AddSource:          logHandlerAddSource,
-			TerminalTime:       logHandlerTerminalTime,
+			TerminalTime:       logHandlerTerminalTimeValue.Enabled(),
+			TerminalTimeMode:   logHandlerTerminalTimeValue.TimeMode(),
+			TerminalColorMode:  logHandlerTerminalColorValue.ColorMode(),
 			TerminalForceColor: logHandlerTerminalForceColor,
+			TerminalTheme:      logHandlerTerminalThemeValue.ColorScheme(),
+			TerminalBackground: logHandlerTerminalBackgroundValue.Background(),
+			TerminalLevels:     Levels,
 		},
 	)
 	return slog.New(handler)
@@ -64,6 +92,9 @@ func Reset() {
 	logLevelValue.Reset()
 	logHandlerValue.Reset()
 	logHandlerAddSource = defaultLogHandlerAddSource
-	logHandlerTerminalTime = defaultLogHandlerTerminlTime
+	logHandlerTerminalTimeValue.Reset()
+	logHandlerTerminalColorValue.Reset()
+	logHandlerTerminalThemeValue.Reset()
+	logHandlerTerminalBackgroundValue.Reset()
 	logHandlerTerminalForceColor = defaultLogHandlerTerminalForceColor
 }
@@ -17,36 +17,53 @@ type LogHandlerValueOptions struct {
 	Level              slog.Level
 	AddSource          bool
 	TerminalTime       bool
+	TerminalTimeMode   log.TimeMode
+	TerminalColorMode  log.ColorMode
 	TerminalForceColor bool
+	TerminalTheme      *log.TerminalHandlerColorScheme
+	TerminalBackground log.Background
+	TerminalLevels     log.TerminalLevels
+}
+
+// terminalTime returns the time layout and mode for terminal handlers.
+func terminalTime(options LogHandlerValueOptions) (string, log.TimeMode) {
+	if !options.TerminalTime {
+		return "", log.TimeModeAbsolute
+	}
+	return time.DateTime, options.TerminalTimeMode
 }
 
 var logHandlerNameFnMap = map[string]func(io.Writer, LogHandlerValueOptions) slog.Handler{
 	"terminal-tree": func(writer io.Writer, options LogHandlerValueOptions) slog.Handler {
-		var timeLayout string
-		if options.TerminalTime {
-			timeLayout = time.DateTime
-		}
+		timeLayout, timeMode := terminalTime(options)
 		return log.NewTerminalTreeHandler(writer, &log.TerminalHandlerOptions{
 			HandlerOptions: slog.HandlerOptions{
 				Level:     options.Level,
 				AddSource: options.AddSource,
 			},
-			TimeLayout: timeLayout,
-			ForceColor: options.TerminalForceColor,
+			TimeLayout:  timeLayout,
+			TimeMode:    timeMode,
+			ColorMode:   options.TerminalColorMode,
+			ForceColor:  options.TerminalForceColor,
+			ColorScheme: options.TerminalTheme,
+			Background:  options.TerminalBackground,
+			Levels:      options.TerminalLevels,
 		})
 	},
 	"terminal-line": func(writer io.Writer, options LogHandlerValueOptions) slog.Handler {
-		var timeLayout string
-		if options.TerminalTime {
-			timeLayout = time.DateTime
-		}
+		timeLayout, timeMode := terminalTime(options)
 		return log.NewTerminalLineHandler(writer, &log.TerminalHandlerOptions{
 			HandlerOptions: slog.HandlerOptions{
 				Level:     options.Level,
 				AddSource: options.AddSource,
 			},
-			TimeLayout: timeLayout,
-			ForceColor: options.TerminalForceColor,
+			TimeLayout:  timeLayout,
+			TimeMode:    timeMode,
+			ColorMode:   options.TerminalColorMode,
+			ForceColor:  options.TerminalForceColor,
+			ColorScheme: options.TerminalTheme,
+			Background:  options.TerminalBackground,
+			Levels:      options.TerminalLevels,
 		})
 	},
 	"json": func(writer io.Writer, options LogHandlerValueOptions) slog.Handler {
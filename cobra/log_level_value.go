@@ -3,11 +3,18 @@ package cobra
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"strings"
+
+	"github.com/fornellas/slogxt/log"
 )
 
 var DefaultLevel = slog.LevelInfo
 
+// Levels registers the level names accepted by [LogLevelValue], and displayed by terminal handlers.
+// It is a copy of [log.DefaultTerminalLevels], so changing it does not change that.
+var Levels = maps.Clone(log.DefaultTerminalLevels)
+
 // LogLevelValue implements [pflag.Value] interface for [slog.Level].
 type LogLevelValue slog.Level
 
@@ -17,11 +24,16 @@ func NewLogLevelValue() *LogLevelValue {
 }
 
 func (l LogLevelValue) String() string {
-	return strings.ToLower(slog.Level(l).String())
+	return strings.ToLower(Levels.Name(slog.Level(l)))
 }
 
 func (l *LogLevelValue) Set(value string) error {
-	return (*slog.Level)(l).UnmarshalText([]byte(value))
+	level, err := Levels.Parse(value)
+	if err != nil {
+		return err
+	}
+	*l = LogLevelValue(level)
+	return nil
 }
 
 func (l *LogLevelValue) Reset() {
@@ -31,12 +43,7 @@ func (l *LogLevelValue) Reset() {
 }
 
 func (l LogLevelValue) Type() string {
-	return fmt.Sprintf("[%s]", strings.Join([]string{
-		strings.ToLower(slog.LevelDebug.String()),
-		strings.ToLower(slog.LevelInfo.String()),
-		strings.ToLower(slog.LevelWarn.String()),
-		strings.ToLower(slog.LevelError.String()),
-	}, "|"))
+	return fmt.Sprintf("[%s]", strings.ToLower(strings.Join(Levels.Names(), "|")))
 }
 
 func (l LogLevelValue) Level() slog.Level {
@@ -0,0 +1,67 @@
+package cobra
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fornellas/slogxt/log"
+)
+
+// DefaultTheme is empty, for the default color scheme of terminal handlers.
+var DefaultTheme = ""
+
+// ThemeValue implements [pflag.Value] interface for a [log.TerminalHandlerColorScheme]. It accepts
+// the names of [log.TerminalThemeNames], the path to a TOML (.toml) or JSON (.json) theme file
+// loaded with [log.LoadTerminalTheme] or a compact spec parsed with [log.ParseTerminalTheme].
+type ThemeValue struct {
+	value       string
+	colorScheme *log.TerminalHandlerColorScheme
+}
+
+func NewThemeValue() *ThemeValue {
+	themeValue := &ThemeValue{}
+	themeValue.Reset()
+	return themeValue
+}
+
+func (t ThemeValue) String() string {
+	return t.value
+}
+
+func (t *ThemeValue) Set(value string) error {
+	if value == "" {
+		t.value = value
+		t.colorScheme = nil
+		return nil
+	}
+	var colorScheme *log.TerminalHandlerColorScheme
+	var err error
+	switch strings.ToLower(filepath.Ext(value)) {
+	case ".toml", ".json":
+		colorScheme, err = log.LoadTerminalTheme(value)
+	default:
+		colorScheme, err = log.ParseTerminalTheme(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid theme '%s': %w", value, err)
+	}
+	t.value = value
+	t.colorScheme = colorScheme
+	return nil
+}
+
+func (t *ThemeValue) Reset() {
+	if err := t.Set(DefaultTheme); err != nil {
+		panic(err)
+	}
+}
+
+func (t ThemeValue) Type() string {
+	return fmt.Sprintf("[%s|file|spec]", strings.Join(log.TerminalThemeNames(), "|"))
+}
+
+// ColorScheme returns the color scheme of the theme, or nil for the default one.
+func (t ThemeValue) ColorScheme() *log.TerminalHandlerColorScheme {
+	return t.colorScheme
+}
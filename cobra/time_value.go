@@ -0,0 +1,74 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fornellas/slogxt/log"
+)
+
+// TimeValueNone disables time for terminal handlers.
+const TimeValueNone = "none"
+
+var DefaultTime = TimeValueNone
+
+// TimeValue implements [pflag.Value] interface for whether and how terminal handlers display
+// time. Besides [TimeValueNone] and the names of [log.TimeMode], "true" and "false" are accepted
+// for compatibility with the former boolean flag.
+type TimeValue struct {
+	enabled bool
+	mode    log.TimeMode
+}
+
+func NewTimeValue() *TimeValue {
+	timeValue := &TimeValue{}
+	timeValue.Reset()
+	return timeValue
+}
+
+func (t TimeValue) String() string {
+	if !t.enabled {
+		return TimeValueNone
+	}
+	return t.mode.String()
+}
+
+func (t *TimeValue) Set(value string) error {
+	switch strings.ToLower(value) {
+	case TimeValueNone, "false":
+		t.enabled = false
+		t.mode = log.TimeModeAbsolute
+		return nil
+	case "true":
+		t.enabled = true
+		t.mode = log.TimeModeAbsolute
+		return nil
+	}
+	var mode log.TimeMode
+	if err := mode.UnmarshalText([]byte(value)); err != nil {
+		return fmt.Errorf("invalid time '%s', valid options are %s", value, t.Type())
+	}
+	t.enabled = true
+	t.mode = mode
+	return nil
+}
+
+func (t *TimeValue) Reset() {
+	if err := t.Set(DefaultTime); err != nil {
+		panic(err)
+	}
+}
+
+func (t TimeValue) Type() string {
+	return fmt.Sprintf("[%s]", strings.Join(append([]string{TimeValueNone}, log.TimeModeNames()...), "|"))
+}
+
+// Enabled returns whether time is displayed.
+func (t TimeValue) Enabled() bool {
+	return t.enabled
+}
+
+// TimeMode returns how time is displayed, when enabled.
+func (t TimeValue) TimeMode() log.TimeMode {
+	return t.mode
+}
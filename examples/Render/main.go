@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/fornellas/slogxt/ansi"
+	"github.com/fornellas/slogxt/log"
+)
+
+func main() {
+	html := flag.Bool("html", false, "render HTML instead of SVG")
+	flag.Parse()
+
+	// Log colored output to a buffer, instead of the terminal
+	buf := &bytes.Buffer{}
+	handler := log.NewTerminalTreeHandler(buf, &log.TerminalHandlerOptions{
+		ForceColor: true,
+	})
+	logger := slog.New(handler)
+
+	logger.Info("Application started")
+	logger.Warn("Disk almost full", "path", "/var", "usage", 0.93)
+	logger.WithGroup("request").Error("Request failed", "method", "GET", "status", 500)
+
+	// Render it to a standalone SVG image, or to a HTML document
+	var err error
+	if *html {
+		fmt.Printf("<!DOCTYPE html>\n<html>\n<head>\n<style>\n%s</style>\n</head>\n<body>\n", ansi.HTMLStyleSheet(nil))
+		err = ansi.RenderHTML(os.Stdout, buf.String(), nil)
+		fmt.Printf("</body>\n</html>\n")
+	} else {
+		err = ansi.RenderSVG(os.Stdout, buf.String(), nil)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
@@ -10,21 +10,21 @@ import (
 
 func main() {
 	customColors := &log.TerminalHandlerColorScheme{
-		GroupName:    ansi.SGRs{ansi.FgMagenta, ansi.Bold},
-		AttrKey:      ansi.SGRs{ansi.FgBlue},
-		AttrValue:    ansi.SGRs{ansi.FgWhite},
-		Time:         ansi.SGRs{ansi.FgYellow},
-		LevelDebug:   ansi.SGRs{ansi.FgCyan},
-		MessageDebug: ansi.SGRs{ansi.FgCyan},
-		LevelInfo:    ansi.SGRs{ansi.FgGreen, ansi.Bold},
-		MessageInfo:  ansi.SGRs{ansi.FgWhite, ansi.Bold},
-		LevelWarn:    ansi.SGRs{ansi.FgYellow, ansi.Bold},
-		MessageWarn:  ansi.SGRs{ansi.FgYellow},
-		LevelError:   ansi.SGRs{ansi.FgRed, ansi.Bold, ansi.Blink},
-		MessageError: ansi.SGRs{ansi.FgRed, ansi.Bold},
-		File:         ansi.SGRs{ansi.FgBlue, ansi.Italic},
-		Line:         ansi.SGRs{ansi.FgBlue, ansi.Bold},
-		Function:     ansi.SGRs{ansi.FgBlue, ansi.Dim},
+		GroupName:    ansi.NewStyle().Fg(ansi.Magenta).Bold(),
+		AttrKey:      ansi.NewStyle().Fg(ansi.Blue),
+		AttrValue:    ansi.NewStyle().Fg(ansi.White),
+		Time:         ansi.NewStyle().Fg(ansi.Yellow),
+		LevelDebug:   ansi.NewStyle().Fg(ansi.Cyan),
+		MessageDebug: ansi.NewStyle().Fg(ansi.Cyan),
+		LevelInfo:    ansi.NewStyle().Fg(ansi.Green).Bold(),
+		MessageInfo:  ansi.NewStyle().Fg(ansi.White).Bold(),
+		LevelWarn:    ansi.NewStyle().Fg(ansi.Yellow).Bold(),
+		MessageWarn:  ansi.NewStyle().Fg(ansi.Yellow),
+		LevelError:   ansi.NewStyle().Fg(ansi.Red).Bold().Blink(),
+		MessageError: ansi.NewStyle().Fg(ansi.Red).Bold(),
+		File:         ansi.NewStyle().Fg(ansi.Blue).Italic(),
+		Line:         ansi.NewStyle().Fg(ansi.Blue).Bold(),
+		Function:     ansi.NewStyle().Fg(ansi.Blue).Dim(),
 	}
 
 	handler := log.NewTerminalTreeHandler(os.Stdout, &log.TerminalHandlerOptions{
@@ -3,48 +3,400 @@ package log
 import (
 	"context"
 	"errors"
+	"fmt"
+	"iter"
 	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"time"
+	"unsafe"
 )
 
+// OverflowPolicy defines what a BufferedHandler does with a record that does not fit in its
+// buffer.
+type OverflowPolicy int
+
+const (
+	// Drop the oldest buffered records, until the new one fits.
+	OverflowDropOldest OverflowPolicy = iota
+	// Drop the new record.
+	OverflowDropNewest
+	// Flush all buffered records to the wrapped handler, and buffer the new one.
+	OverflowFlush
+	// Block Handle until Flush makes room for the new record, or its context is done, in which
+	// case the record is dropped.
+	OverflowBlock
+)
+
+var overflowPolicyNames = map[OverflowPolicy]string{
+	OverflowDropOldest: "drop-oldest",
+	OverflowDropNewest: "drop-newest",
+	OverflowFlush:      "flush",
+	OverflowBlock:      "block",
+}
+
+// OverflowPolicyNames returns the names of all overflow policies.
+func OverflowPolicyNames() []string {
+	return []string{
+		overflowPolicyNames[OverflowDropOldest],
+		overflowPolicyNames[OverflowDropNewest],
+		overflowPolicyNames[OverflowFlush],
+		overflowPolicyNames[OverflowBlock],
+	}
+}
+
+// String returns the name of the overflow policy.
+func (p OverflowPolicy) String() string {
+	if name, ok := overflowPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p OverflowPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [OverflowPolicyNames], case insensitively.
+func (p *OverflowPolicy) UnmarshalText(data []byte) error {
+	for policy, name := range overflowPolicyNames {
+		if strings.EqualFold(string(data), name) {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid overflow policy %#v, valid options are %s",
+		string(data), strings.Join(OverflowPolicyNames(), ", "),
+	)
+}
+
+// BufferedHandlerOptions are options for a BufferedHandler.
+type BufferedHandlerOptions struct {
+	// Maximum number of buffered records. If 0, there's no limit.
+	MaxRecords int
+	// Maximum estimated memory used by buffered records, in bytes, from the size of records, their
+	// messages, attribute keys and values. Values of kind Any count the length of strings, the
+	// length of slices and maps times the size of their elements, the size of the value a pointer
+	// points to, or else the size of the value itself; their methods, such as String or Error, are
+	// not called. Numbers, booleans, durations and times count only the size of the attribute. If
+	// 0, there's no limit.
+	MaxBytes int
+	// What to do with records that do not fit in the buffer. Defaults to OverflowDropOldest.
+	// Records larger than MaxBytes are dropped, or with OverflowFlush, handled right away.
+	Overflow OverflowPolicy
+	// If set, a record at or above this level triggers a flush of all buffered records, followed
+	// by the record itself. Along with MaxRecords, this keeps a ring buffer of recent records,
+	// that are only handled when something goes wrong.
+	TriggerLevel slog.Leveler
+	// How long records pass through to the wrapped handler without being buffered, after a record
+	// at TriggerLevel, as measured by record times. If 0, buffering resumes right away.
+	PassThrough time.Duration
+	// If set, buffered records are discarded when the context is done, and records handled after
+	// that are dropped.
+	Context context.Context
+}
+
+// bufferedGroupOrAttrs is a group or attributes, added to a BufferedHandler by WithGroup or
+// WithAttrs.
+type bufferedGroupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
 type handleCall struct {
-	handler slog.Handler
-	context context.Context
-	record  slog.Record
+	handler      slog.Handler
+	groupOrAttrs []bufferedGroupOrAttrs
+	context      context.Context
+	record       slog.Record
+	size         int
 }
 
 func (h *handleCall) flush() error {
 	return h.handler.Handle(h.context, h.record)
 }
 
+// resolvedRecord returns a copy of the record, with the groups and attributes of the handler
+// that buffered it applied to its attributes.
+func (h *handleCall) resolvedRecord() slog.Record {
+	var attrs []slog.Attr
+	h.record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	for i := len(h.groupOrAttrs) - 1; i >= 0; i-- {
+		groupOrAttrs := h.groupOrAttrs[i]
+		if groupOrAttrs.group == "" {
+			attrs = append(slices.Clone(groupOrAttrs.attrs), attrs...)
+		} else if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: groupOrAttrs.group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+	record := slog.NewRecord(h.record.Time, h.record.Level, h.record.Message, h.record.PC)
+	record.AddAttrs(attrs...)
+	return record
+}
+
+// valueSize returns the estimated memory used by value, besides the slog.Value itself.
+func valueSize(value slog.Value) int {
+	switch value.Kind() {
+	case slog.KindString:
+		return len(value.String())
+	case slog.KindGroup:
+		size := 0
+		for _, attr := range value.Group() {
+			size += attrSize(attr)
+		}
+		return size
+	case slog.KindAny, slog.KindLogValuer:
+		return anySize(value.Any())
+	default:
+		return 0
+	}
+}
+
+// anySize returns a cheap estimate of the memory used by v, from its kind and type, without calling
+// any of its methods.
+func anySize(v any) int {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return 0
+	case reflect.String:
+		return rv.Len()
+	case reflect.Slice:
+		return rv.Len() * int(rv.Type().Elem().Size())
+	case reflect.Map:
+		return rv.Len() * int(rv.Type().Key().Size()+rv.Type().Elem().Size())
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return 0
+		}
+		return int(rv.Type().Elem().Size())
+	default:
+		return int(rv.Type().Size())
+	}
+}
+
+func attrSize(attr slog.Attr) int {
+	return int(unsafe.Sizeof(attr)) + len(attr.Key) + valueSize(attr.Value)
+}
+
+// recordSize returns the estimated memory used by record.
+func recordSize(record slog.Record) int {
+	size := int(unsafe.Sizeof(record)) + len(record.Message)
+	record.Attrs(func(attr slog.Attr) bool {
+		size += attrSize(attr)
+		return true
+	})
+	return size
+}
+
 type sharedBuffer struct {
 	mu          sync.Mutex
+	opts        BufferedHandlerOptions
 	handleCalls []handleCall
+	bytes       int
+	dropped     int
+	// records before this time pass through, after a record at TriggerLevel
+	passThroughUntil time.Time
+	// closed by flush, to wake up Handle calls blocked by OverflowBlock
+	flushed chan struct{}
+	// number of Handle calls blocked by OverflowBlock
+	blocked int
+	// set when Context is done, after which records are dropped
+	canceled bool
 }
 
-func (s *sharedBuffer) appendHandleCall(handler slog.Handler, ctx context.Context, record slog.Record) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.handleCalls = append(s.handleCalls, handleCall{
-		handler: handler,
-		context: ctx,
-		record:  record.Clone(),
-	})
+func newSharedBuffer(opts *BufferedHandlerOptions) *sharedBuffer {
+	var optsValue BufferedHandlerOptions
+	if opts != nil {
+		optsValue = *opts
+	}
+	s := &sharedBuffer{
+		opts:    optsValue,
+		flushed: make(chan struct{}),
+	}
+	if optsValue.Context != nil {
+		context.AfterFunc(optsValue.Context, s.cancel)
+	}
+	return s
 }
 
-func (s *sharedBuffer) flush() error {
+// fits returns whether a record of the given size fits in the buffer, after the given number of
+// records and bytes are removed from it.
+func (s *sharedBuffer) fits(size, records, bytes int) bool {
+	if s.opts.MaxRecords > 0 && len(s.handleCalls)-records+1 > s.opts.MaxRecords {
+		return false
+	}
+	if s.opts.MaxBytes > 0 && s.bytes-bytes+size > s.opts.MaxBytes {
+		return false
+	}
+	return true
+}
+
+// fitsAlone returns whether a record of the given size fits in an empty buffer.
+func (s *sharedBuffer) fitsAlone(size int) bool {
+	return s.fits(size, len(s.handleCalls), s.bytes)
+}
+
+func (s *sharedBuffer) appendHandleCall(
+	handler slog.Handler, groupOrAttrs []bufferedGroupOrAttrs, ctx context.Context, record slog.Record,
+) error {
+	call := handleCall{
+		handler:      handler,
+		groupOrAttrs: groupOrAttrs,
+		context:      ctx,
+		record:       record.Clone(),
+	}
+	if s.opts.MaxBytes > 0 {
+		call.size = recordSize(record)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.canceled {
+		return nil
+	}
+	recordTime := record.Time
+	if recordTime.IsZero() {
+		recordTime = time.Now()
+	}
+	if s.opts.TriggerLevel != nil && record.Level >= s.opts.TriggerLevel.Level() {
+		s.passThroughUntil = recordTime.Add(s.opts.PassThrough)
+		return errors.Join(s.flushLocked(), call.flush())
+	}
+	if recordTime.Before(s.passThroughUntil) {
+		return call.flush()
+	}
+	for !s.fits(call.size, 0, 0) {
+		if !s.fitsAlone(call.size) {
+			if s.opts.Overflow == OverflowFlush {
+				if err := s.flushLocked(); err != nil {
+					return err
+				}
+				return call.flush()
+			}
+			s.dropped++
+			return nil
+		}
+		switch s.opts.Overflow {
+		case OverflowDropNewest:
+			s.dropped++
+			return nil
+		case OverflowFlush:
+			err := s.flushLocked()
+			s.push(call)
+			return err
+		case OverflowBlock:
+			flushed := s.flushed
+			s.blocked++
+			s.mu.Unlock()
+			select {
+			case <-flushed:
+				s.mu.Lock()
+				s.blocked--
+				if s.canceled {
+					return nil
+				}
+			case <-ctx.Done():
+				s.mu.Lock()
+				s.blocked--
+				s.dropped++
+				return ctx.Err()
+			}
+		default:
+			s.bytes -= s.handleCalls[0].size
+			s.handleCalls[0] = handleCall{}
+			s.handleCalls = s.handleCalls[1:]
+			s.dropped++
+		}
+	}
+	s.push(call)
+	return nil
+}
+
+func (s *sharedBuffer) push(call handleCall) {
+	s.handleCalls = append(s.handleCalls, call)
+	s.bytes += call.size
+}
+
+func (s *sharedBuffer) flushLocked() error {
 	var errs error
 	for _, handleCall := range s.handleCalls {
 		if err := handleCall.flush(); err != nil {
 			errs = errors.Join(errs, err)
 		}
 	}
-	s.handleCalls = nil
+	s.clearLocked()
 	return errs
 }
 
+// clearLocked empties the buffer, waking up Handle calls blocked by OverflowBlock.
+func (s *sharedBuffer) clearLocked() {
+	s.handleCalls = nil
+	s.bytes = 0
+	close(s.flushed)
+	s.flushed = make(chan struct{})
+}
+
+func (s *sharedBuffer) discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped = 0
+	s.clearLocked()
+}
+
+// cancel discards buffered records, and drops records handled from then on, once Context is done.
+func (s *sharedBuffer) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.canceled = true
+	s.dropped = 0
+	s.clearLocked()
+}
+
+func (s *sharedBuffer) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped = 0
+	return s.flushLocked()
+}
+
+func (s *sharedBuffer) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.handleCalls)
+}
+
+func (s *sharedBuffer) records() iter.Seq[slog.Record] {
+	s.mu.Lock()
+	handleCalls := slices.Clone(s.handleCalls)
+	s.mu.Unlock()
+	return func(yield func(slog.Record) bool) {
+		for _, handleCall := range handleCalls {
+			if !yield(handleCall.resolvedRecord()) {
+				return
+			}
+		}
+	}
+}
+
+func (s *sharedBuffer) blockedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blocked
+}
+
+func (s *sharedBuffer) droppedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
 // BufferedHandler is a slog.Handler that buffers log records in memory
 // until Dispatch() is called. This allows for batching log operations
 // which can be useful for performance or to ensure logs from related
@@ -58,15 +410,23 @@ func (s *sharedBuffer) flush() error {
 // The buffer is not automatically flushed - you must call Dispatch() explicitly
 // to process the buffered log records. If Dispatch() is not called, logs will
 // remain in memory and not be processed by the underlying handler.
+//
+// The buffer grows without limit, unless bounded with BufferedHandlerOptions.
 type BufferedHandler struct {
 	handler      slog.Handler
+	groupOrAttrs []bufferedGroupOrAttrs
 	sharedBuffer *sharedBuffer
 }
 
 func NewBufferedHandler(handler slog.Handler) *BufferedHandler {
+	return NewBufferedHandlerWithOptions(handler, nil)
+}
+
+// NewBufferedHandlerWithOptions creates a BufferedHandler, with its buffer bounded by opts.
+func NewBufferedHandlerWithOptions(handler slog.Handler, opts *BufferedHandlerOptions) *BufferedHandler {
 	return &BufferedHandler{
 		handler:      handler,
-		sharedBuffer: &sharedBuffer{},
+		sharedBuffer: newSharedBuffer(opts),
 	}
 }
 
@@ -74,14 +434,17 @@ func (h *BufferedHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return h.handler.Enabled(ctx, level)
 }
 
+// Handle buffers the record, or with TriggerLevel, handles it right away along with all buffered
+// records. It returns errors from handling records right away, or with OverflowBlock, when ctx is
+// done before there's room for the record.
 func (h *BufferedHandler) Handle(ctx context.Context, record slog.Record) error {
-	h.sharedBuffer.appendHandleCall(h.handler, ctx, record)
-	return nil
+	return h.sharedBuffer.appendHandleCall(h.handler, h.groupOrAttrs, ctx, record)
 }
 
 func (h *BufferedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &BufferedHandler{
 		handler:      h.handler.WithAttrs(attrs),
+		groupOrAttrs: append(slices.Clip(h.groupOrAttrs), bufferedGroupOrAttrs{attrs: slices.Clone(attrs)}),
 		sharedBuffer: h.sharedBuffer,
 	}
 }
@@ -89,6 +452,7 @@ func (h *BufferedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 func (h *BufferedHandler) WithGroup(name string) slog.Handler {
 	return &BufferedHandler{
 		handler:      h.handler.WithGroup(name),
+		groupOrAttrs: append(slices.Clip(h.groupOrAttrs), bufferedGroupOrAttrs{group: name}),
 		sharedBuffer: h.sharedBuffer,
 	}
 }
@@ -102,3 +466,28 @@ func (h *BufferedHandler) WithGroup(name string) slog.Handler {
 func (h *BufferedHandler) Flush() error {
 	return h.sharedBuffer.flush()
 }
+
+// Discard drops all buffered log records, without sending them to the underlying handler, eg:
+// to throw away the debug output of a successful task.
+func (h *BufferedHandler) Discard() {
+	h.sharedBuffer.discard()
+}
+
+// Len returns the number of buffered log records.
+func (h *BufferedHandler) Len() int {
+	return h.sharedBuffer.len()
+}
+
+// Records returns an iterator over the buffered log records, in the order they were handled,
+// eg: for tests or task runners to decide what to emit. Groups and attributes added with
+// WithGroup and WithAttrs are applied to the attributes of each record, as the wrapped handler
+// would. Records buffered after Records is called are not included.
+func (h *BufferedHandler) Records() iter.Seq[slog.Record] {
+	return h.sharedBuffer.records()
+}
+
+// Dropped returns the number of records dropped since the last Flush or Discard, because they did not fit
+// in the buffer. It can be called before Flush to log a summary of dropped records.
+func (h *BufferedHandler) Dropped() int {
+	return h.sharedBuffer.droppedCount()
+}
@@ -2,9 +2,19 @@ package log
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,3 +56,340 @@ func TestBufferedHandler(t *testing.T) {
 	require.NoError(t, bufferedHandler.Flush())
 	require.Equal(t, expectedWritten, buff.String())
 }
+
+// newMessageHandler returns a handler that writes only record messages to w, one per line.
+func newMessageHandler(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key != slog.MessageKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropOldest, OverflowDropNewest, OverflowFlush, OverflowBlock} {
+		text, err := policy.MarshalText()
+		require.NoError(t, err)
+		var parsedPolicy OverflowPolicy
+		require.NoError(t, parsedPolicy.UnmarshalText(text))
+		assert.Equal(t, policy, parsedPolicy)
+	}
+
+	var policy OverflowPolicy
+	require.NoError(t, policy.UnmarshalText([]byte("Drop-Newest")))
+	assert.Equal(t, OverflowDropNewest, policy)
+	require.EqualError(
+		t, policy.UnmarshalText([]byte("grow")),
+		`invalid overflow policy "grow", valid options are drop-oldest, drop-newest, flush, block`,
+	)
+}
+
+func TestBufferedHandlerOverflow(t *testing.T) {
+	// fits a single record with a 1 or 2 characters message
+	maxBytes := recordSize(slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)) + 2
+
+	tests := []struct {
+		name            string
+		opts            *BufferedHandlerOptions
+		messages        []string
+		expectedEarly   string
+		expectedFlushed string
+		expectedDropped int
+	}{
+		{
+			name:            "unbounded",
+			opts:            nil,
+			messages:        []string{"1", "2", "3"},
+			expectedFlushed: "msg=1\nmsg=2\nmsg=3\n",
+		},
+		{
+			name:            "drop oldest",
+			opts:            &BufferedHandlerOptions{MaxRecords: 2},
+			messages:        []string{"1", "2", "3"},
+			expectedFlushed: "msg=2\nmsg=3\n",
+			expectedDropped: 1,
+		},
+		{
+			name:            "drop newest",
+			opts:            &BufferedHandlerOptions{MaxRecords: 2, Overflow: OverflowDropNewest},
+			messages:        []string{"1", "2", "3"},
+			expectedFlushed: "msg=1\nmsg=2\n",
+			expectedDropped: 1,
+		},
+		{
+			name:            "flush",
+			opts:            &BufferedHandlerOptions{MaxRecords: 2, Overflow: OverflowFlush},
+			messages:        []string{"1", "2", "3"},
+			expectedEarly:   "msg=1\nmsg=2\n",
+			expectedFlushed: "msg=1\nmsg=2\nmsg=3\n",
+		},
+		{
+			name:            "bytes",
+			opts:            &BufferedHandlerOptions{MaxBytes: maxBytes},
+			messages:        []string{"1", "2", "3"},
+			expectedFlushed: "msg=3\n",
+			expectedDropped: 2,
+		},
+		{
+			name:            "larger than MaxBytes dropped",
+			opts:            &BufferedHandlerOptions{MaxBytes: maxBytes, Overflow: OverflowBlock},
+			messages:        []string{"1", "xxx"},
+			expectedFlushed: "msg=1\n",
+			expectedDropped: 1,
+		},
+		{
+			name:            "larger than MaxBytes flushed",
+			opts:            &BufferedHandlerOptions{MaxBytes: maxBytes, Overflow: OverflowFlush},
+			messages:        []string{"1", "xxx"},
+			expectedEarly:   "msg=1\nmsg=xxx\n",
+			expectedFlushed: "msg=1\nmsg=xxx\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buff bytes.Buffer
+			bufferedHandler := NewBufferedHandlerWithOptions(newMessageHandler(&buff), tt.opts)
+			logger := slog.New(bufferedHandler)
+			for _, message := range tt.messages {
+				logger.Info(message)
+			}
+			require.Equal(t, tt.expectedEarly, buff.String())
+			require.Equal(t, tt.expectedDropped, bufferedHandler.Dropped())
+			require.NoError(t, bufferedHandler.Flush())
+			require.Equal(t, tt.expectedFlushed, buff.String())
+			require.Equal(t, 0, bufferedHandler.Dropped())
+		})
+	}
+}
+
+type sizeString string
+
+type sizeBytes []byte
+
+type sizeStringer struct{}
+
+func (sizeStringer) String() string {
+	return "stringer"
+}
+
+func TestValueSize(t *testing.T) {
+	attr := slog.Any("k", []byte("xyz"))
+	tests := []struct {
+		name     string
+		value    slog.Value
+		expected int
+	}{
+		{"string", slog.StringValue("abc"), 3},
+		{"int", slog.IntValue(12345), 0},
+		{"bytes", slog.AnyValue(make([]byte, 1024)), 1024},
+		{"named string", slog.AnyValue(sizeString("abcd")), 4},
+		{"named bytes", slog.AnyValue(sizeBytes("abcde")), 5},
+		{"ints", slog.AnyValue([]int64{1, 2, 3}), 3 * 8},
+		{"map", slog.AnyValue(map[int32]int32{1: 2}), 4 + 4},
+		{"error", slog.AnyValue(errors.New("failed")), int(unsafe.Sizeof(""))},
+		{"stringer", slog.AnyValue(sizeStringer{}), 0},
+		{"struct", slog.AnyValue(struct{ A, B int64 }{1, 23}), 16},
+		{"nil pointer", slog.AnyValue((*int)(nil)), 0},
+		{"nil", slog.AnyValue(nil), 0},
+		{"group", slog.GroupValue(attr), attrSize(attr)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, valueSize(tt.value))
+		})
+	}
+	require.Equal(t, int(unsafe.Sizeof(attr))+1+3, attrSize(attr))
+}
+
+// selfLoggingStringer logs through logger when formatted.
+type selfLoggingStringer struct {
+	logger *slog.Logger
+	called *atomic.Bool
+}
+
+func (s selfLoggingStringer) String() string {
+	s.called.Store(true)
+	s.logger.Info("from String")
+	return "stringer"
+}
+
+func TestBufferedHandlerSelfLoggingStringer(t *testing.T) {
+	for _, opts := range []*BufferedHandlerOptions{{}, {MaxBytes: 1 << 20}} {
+		t.Run(fmt.Sprintf("MaxBytes=%d", opts.MaxBytes), func(t *testing.T) {
+			var buff bytes.Buffer
+			logger := slog.New(NewBufferedHandlerWithOptions(newMessageHandler(&buff), opts))
+			stringer := selfLoggingStringer{logger: logger, called: &atomic.Bool{}}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				logger.Info("msg", "stringer", stringer)
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				require.FailNow(t, "deadlock buffering a self-logging fmt.Stringer")
+			}
+			require.False(t, stringer.called.Load())
+		})
+	}
+}
+
+func TestBufferedHandlerOverflowBlock(t *testing.T) {
+	var buff bytes.Buffer
+	bufferedHandler := NewBufferedHandlerWithOptions(
+		newMessageHandler(&buff), &BufferedHandlerOptions{MaxRecords: 1, Overflow: OverflowBlock},
+	)
+	logger := slog.New(bufferedHandler)
+	logger.Info("1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "canceled", 0)
+	require.ErrorIs(t, bufferedHandler.Handle(ctx, record), context.Canceled)
+	require.Equal(t, 1, bufferedHandler.Dropped())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("2")
+	}()
+	require.Eventually(t, func() bool {
+		return bufferedHandler.sharedBuffer.blockedCount() == 1
+	}, time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("Handle did not block")
+	default:
+	}
+	require.NoError(t, bufferedHandler.Flush())
+	<-done
+	require.Equal(t, "msg=1\n", buff.String())
+	require.NoError(t, bufferedHandler.Flush())
+	require.Equal(t, "msg=1\nmsg=2\n", buff.String())
+}
+
+func TestBufferedHandlerTriggerLevel(t *testing.T) {
+	var buff bytes.Buffer
+	bufferedHandler := NewBufferedHandlerWithOptions(newMessageHandler(&buff), &BufferedHandlerOptions{
+		MaxRecords:   2,
+		TriggerLevel: slog.LevelError,
+		PassThrough:  time.Second,
+	})
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	handle := func(offset time.Duration, level slog.Level, message string) {
+		require.NoError(t, bufferedHandler.Handle(
+			context.Background(), slog.NewRecord(start.Add(offset), level, message, 0),
+		))
+	}
+
+	handle(0, slog.LevelDebug, "1")
+	handle(1*time.Second, slog.LevelInfo, "2")
+	handle(2*time.Second, slog.LevelWarn, "3")
+	require.Equal(t, "", buff.String())
+
+	handle(3*time.Second, slog.LevelError, "error")
+	require.Equal(t, "msg=2\nmsg=3\nmsg=error\n", buff.String())
+	require.Equal(t, 1, bufferedHandler.Dropped())
+
+	handle(3500*time.Millisecond, slog.LevelDebug, "passthrough")
+	require.Equal(t, "msg=2\nmsg=3\nmsg=error\nmsg=passthrough\n", buff.String())
+
+	handle(5*time.Second, slog.LevelDebug, "buffered")
+	require.Equal(t, "msg=2\nmsg=3\nmsg=error\nmsg=passthrough\n", buff.String())
+
+	bufferedHandler.Discard()
+	require.Equal(t, 0, bufferedHandler.Dropped())
+	require.NoError(t, bufferedHandler.Flush())
+	require.Equal(t, "msg=2\nmsg=3\nmsg=error\nmsg=passthrough\n", buff.String())
+}
+
+func TestBufferedHandlerContext(t *testing.T) {
+	var buff bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	bufferedHandler := NewBufferedHandlerWithOptions(
+		newMessageHandler(&buff), &BufferedHandlerOptions{Context: ctx},
+	)
+	logger := slog.New(bufferedHandler)
+	logger.Info("discarded")
+	cancel()
+	require.Eventually(t, func() bool {
+		return bufferedHandler.Len() == 0
+	}, time.Second, time.Millisecond)
+	logger.Info("after cancel")
+	require.Equal(t, 0, bufferedHandler.Len())
+	require.NoError(t, bufferedHandler.Flush())
+	require.Equal(t, "", buff.String())
+}
+
+func TestBufferedHandlerContextBlocked(t *testing.T) {
+	var buff bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	bufferedHandler := NewBufferedHandlerWithOptions(
+		newMessageHandler(&buff), &BufferedHandlerOptions{MaxRecords: 1, Overflow: OverflowBlock, Context: ctx},
+	)
+	logger := slog.New(bufferedHandler)
+	logger.Info("1")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("2")
+	}()
+	require.Eventually(t, func() bool {
+		return bufferedHandler.sharedBuffer.blockedCount() == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	require.Equal(t, 0, bufferedHandler.Len())
+	require.NoError(t, bufferedHandler.Flush())
+	require.Equal(t, "", buff.String())
+}
+
+func TestBufferedHandlerInspection(t *testing.T) {
+	var buff bytes.Buffer
+	bufferedHandler := NewBufferedHandler(newMessageHandler(&buff))
+	logger := slog.New(bufferedHandler)
+	require.Equal(t, 0, bufferedHandler.Len())
+
+	logger.Info("plain", "a", 1)
+	logger.With("with", "attr").WithGroup("group").Info("grouped", "b", 2)
+	logger.WithGroup("empty").Info("empty group")
+	logger.WithGroup("outer").With("c", 3).WithGroup("inner").Warn("nested", "d", 4)
+	require.Equal(t, 4, bufferedHandler.Len())
+
+	type resolvedRecord struct {
+		level   slog.Level
+		message string
+		attrs   string
+	}
+	var records []resolvedRecord
+	for record := range bufferedHandler.Records() {
+		var attrs []string
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs = append(attrs, attr.String())
+			return true
+		})
+		records = append(records, resolvedRecord{record.Level, record.Message, strings.Join(attrs, " ")})
+	}
+	require.Equal(t, []resolvedRecord{
+		{slog.LevelInfo, "plain", "a=1"},
+		{slog.LevelInfo, "grouped", "with=attr group=[b=2]"},
+		{slog.LevelInfo, "empty group", ""},
+		{slog.LevelWarn, "nested", "outer=[c=3 inner=[d=4]]"},
+	}, records)
+
+	for range bufferedHandler.Records() {
+		break
+	}
+	require.Equal(t, 4, bufferedHandler.Len())
+
+	bufferedHandler.Discard()
+	require.Equal(t, 0, bufferedHandler.Len())
+	require.Empty(t, slices.Collect(bufferedHandler.Records()))
+	require.NoError(t, bufferedHandler.Flush())
+	require.Equal(t, "", buff.String())
+}
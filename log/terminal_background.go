@@ -0,0 +1,111 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+// Background defines the terminal background that terminal handlers pick their default color
+// scheme for.
+type Background int
+
+const (
+	// Detect the background when writing to a terminal, from the COLORFGBG environment variable,
+	// and assume a dark background when it is unknown.
+	BackgroundAuto Background = iota
+	// Dark background, with DefaultTerminalHandlerColorScheme.
+	BackgroundDark
+	// Light background, with LightTerminalHandlerColorScheme.
+	BackgroundLight
+	// As BackgroundAuto, but when COLORFGBG is not set, query the controlling terminal for its
+	// background color with ansi.QueryTerminalBackgroundColor, once per process. The terminal is
+	// put in raw mode during the query, which may take up to 100ms, and discards input typed
+	// meanwhile.
+	BackgroundQuery
+)
+
+var backgroundNames = map[Background]string{
+	BackgroundAuto:  "auto",
+	BackgroundDark:  "dark",
+	BackgroundLight: "light",
+	BackgroundQuery: "query",
+}
+
+// BackgroundNames returns the names of all backgrounds.
+func BackgroundNames() []string {
+	return []string{
+		backgroundNames[BackgroundAuto],
+		backgroundNames[BackgroundDark],
+		backgroundNames[BackgroundLight],
+		backgroundNames[BackgroundQuery],
+	}
+}
+
+// String returns the name of the background.
+func (b Background) String() string {
+	if name, ok := backgroundNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("Background(%d)", int(b))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Background) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [BackgroundNames], case insensitively.
+func (b *Background) UnmarshalText(data []byte) error {
+	for background, name := range backgroundNames {
+		if strings.EqualFold(string(data), name) {
+			*b = background
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid background %#v, valid options are %s", string(data), strings.Join(BackgroundNames(), ", "),
+	)
+}
+
+// How long to wait for the terminal to reply to the background color query.
+const backgroundQueryTimeout = 100 * time.Millisecond
+
+// queryLightBackground returns whether the terminal background is light, from COLORFGBG or by
+// querying the terminal. It is detected once, as querying the terminal may take up to
+// backgroundQueryTimeout.
+var queryLightBackground = sync.OnceValue(func() bool {
+	color, ok := ansi.DetectBackgroundColor(backgroundQueryTimeout)
+	return ok && color.IsLight()
+})
+
+// detectLight returns whether the terminal background is detected as light.
+func (b Background) detectLight() bool {
+	if b == BackgroundQuery {
+		return queryLightBackground()
+	}
+	color, ok := ansi.EnvBackgroundColor()
+	return ok && color.IsLight()
+}
+
+// colorScheme returns the default color scheme for the background, when writing to w.
+func (b Background) colorScheme(w io.Writer) *TerminalHandlerColorScheme {
+	switch b {
+	case BackgroundDark:
+		return DefaultTerminalHandlerColorScheme
+	case BackgroundLight:
+		return LightTerminalHandlerColorScheme
+	}
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) && b.detectLight() {
+		return LightTerminalHandlerColorScheme
+	}
+	return DefaultTerminalHandlerColorScheme
+}
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+func TestBackground(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		for _, background := range []Background{BackgroundAuto, BackgroundDark, BackgroundLight, BackgroundQuery} {
+			text, err := background.MarshalText()
+			require.NoError(t, err)
+			var parsedBackground Background
+			require.NoError(t, parsedBackground.UnmarshalText(text))
+			assert.Equal(t, background, parsedBackground)
+		}
+
+		var background Background
+		require.NoError(t, background.UnmarshalText([]byte("LIGHT")))
+		assert.Equal(t, BackgroundLight, background)
+		require.EqualError(
+			t, background.UnmarshalText([]byte("gray")),
+			`invalid background "gray", valid options are auto, dark, light, query`,
+		)
+	})
+
+	t.Run("colorScheme", func(t *testing.T) {
+		t.Setenv("COLORFGBG", "0;15")
+		var buf bytes.Buffer
+		assert.Same(t, DefaultTerminalHandlerColorScheme, BackgroundDark.colorScheme(&buf))
+		assert.Same(t, LightTerminalHandlerColorScheme, BackgroundLight.colorScheme(&buf))
+		// not a terminal
+		assert.Same(t, DefaultTerminalHandlerColorScheme, BackgroundAuto.colorScheme(&buf))
+		assert.Same(t, DefaultTerminalHandlerColorScheme, BackgroundQuery.colorScheme(&buf))
+	})
+
+	t.Run("detectLight", func(t *testing.T) {
+		t.Setenv("COLORFGBG", "0;15")
+		assert.True(t, BackgroundAuto.detectLight())
+		t.Setenv("COLORFGBG", "15;0")
+		assert.False(t, BackgroundAuto.detectLight())
+		// BackgroundAuto does not query the terminal
+		t.Setenv("COLORFGBG", "")
+		assert.False(t, BackgroundAuto.detectLight())
+	})
+
+	t.Run("newTerminalHandlerOptions", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			opts     *TerminalHandlerOptions
+			expected *TerminalHandlerColorScheme
+		}{
+			{
+				name:     "default",
+				opts:     &TerminalHandlerOptions{ColorMode: ColorModeAlways, ColorLevel: ansi.ColorLevelTrueColor},
+				expected: DefaultTerminalHandlerColorScheme,
+			},
+			{
+				name: "light",
+				opts: &TerminalHandlerOptions{
+					ColorMode: ColorModeAlways, ColorLevel: ansi.ColorLevelTrueColor, Background: BackgroundLight,
+				},
+				expected: LightTerminalHandlerColorScheme,
+			},
+			{
+				name: "color scheme takes precedence",
+				opts: &TerminalHandlerOptions{
+					ColorMode:   ColorModeAlways,
+					ColorLevel:  ansi.ColorLevelTrueColor,
+					ColorScheme: MonochromeTerminalHandlerColorScheme,
+					Background:  BackgroundLight,
+				},
+				expected: MonochromeTerminalHandlerColorScheme,
+			},
+			{
+				name:     "no color",
+				opts:     &TerminalHandlerOptions{ColorMode: ColorModeNever, Background: BackgroundLight},
+				expected: &TerminalHandlerColorScheme{},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				opts := newTerminalHandlerOptions(&bytes.Buffer{}, tt.opts)
+				assert.Equal(t, tt.expected, opts.ColorScheme)
+			})
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorMode defines when terminal handlers use ANSI escape sequences for colors.
+type ColorMode int
+
+const (
+	// Use colors only when writing to a terminal. The de-facto environment variables conventions
+	// NO_COLOR, FORCE_COLOR, CLICOLOR_FORCE, CLICOLOR and TERM=dumb are honored, and take
+	// precedence over terminal detection.
+	ColorModeAuto ColorMode = iota
+	// Always use colors.
+	ColorModeAlways
+	// Never use colors.
+	ColorModeNever
+)
+
+var colorModeNames = map[ColorMode]string{
+	ColorModeAuto:   "auto",
+	ColorModeAlways: "always",
+	ColorModeNever:  "never",
+}
+
+// ColorModeNames returns the names of all color modes.
+func ColorModeNames() []string {
+	return []string{
+		colorModeNames[ColorModeAuto],
+		colorModeNames[ColorModeAlways],
+		colorModeNames[ColorModeNever],
+	}
+}
+
+// String returns the name of the color mode.
+func (m ColorMode) String() string {
+	if name, ok := colorModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("ColorMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m ColorMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [ColorModeNames], case insensitively.
+func (m *ColorMode) UnmarshalText(data []byte) error {
+	for mode, name := range colorModeNames {
+		if strings.EqualFold(string(data), name) {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid color mode %#v, valid options are %s", string(data), strings.Join(ColorModeNames(), ", "),
+	)
+}
+
+// useColor resolves whether to use colors when writing to w with the given mode.
+func useColor(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	}
+
+	// https://no-color.org/
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if value, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return value != "0" && value != "false"
+	}
+	// https://bixense.com/clicolors/
+	if value := os.Getenv("CLICOLOR_FORCE"); value != "" && value != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	if f, ok := w.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+	return false
+}
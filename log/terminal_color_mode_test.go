@@ -0,0 +1,71 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unsetEnv unsets an environment variable, restoring it when the test ends.
+func unsetEnv(t *testing.T, key string) {
+	t.Setenv(key, "")
+	require.NoError(t, os.Unsetenv(key))
+}
+
+func TestColorMode(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		for _, mode := range []ColorMode{ColorModeAuto, ColorModeAlways, ColorModeNever} {
+			text, err := mode.MarshalText()
+			require.NoError(t, err)
+			var parsedMode ColorMode
+			require.NoError(t, parsedMode.UnmarshalText(text))
+			assert.Equal(t, mode, parsedMode)
+		}
+
+		var mode ColorMode
+		require.NoError(t, mode.UnmarshalText([]byte("ALWAYS")))
+		assert.Equal(t, ColorModeAlways, mode)
+		require.Error(t, mode.UnmarshalText([]byte("sometimes")))
+	})
+
+	t.Run("useColor", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			mode     ColorMode
+			env      map[string]string
+			expected bool
+		}{
+			{"auto_no_tty", ColorModeAuto, nil, false},
+			{"always", ColorModeAlways, nil, true},
+			{"always_ignores_env", ColorModeAlways, map[string]string{"NO_COLOR": "1"}, true},
+			{"never", ColorModeNever, nil, false},
+			{"never_ignores_env", ColorModeNever, map[string]string{"FORCE_COLOR": "1"}, false},
+			{"no_color", ColorModeAuto, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, false},
+			{"no_color_empty", ColorModeAuto, map[string]string{"NO_COLOR": "", "CLICOLOR_FORCE": "1"}, true},
+			{"force_color", ColorModeAuto, map[string]string{"FORCE_COLOR": "1"}, true},
+			{"force_color_empty", ColorModeAuto, map[string]string{"FORCE_COLOR": ""}, true},
+			{"force_color_0", ColorModeAuto, map[string]string{"FORCE_COLOR": "0", "CLICOLOR_FORCE": "1"}, false},
+			{"force_color_false", ColorModeAuto, map[string]string{"FORCE_COLOR": "false"}, false},
+			{"clicolor_force", ColorModeAuto, map[string]string{"CLICOLOR_FORCE": "1", "TERM": "dumb"}, true},
+			{"clicolor_force_0", ColorModeAuto, map[string]string{"CLICOLOR_FORCE": "0"}, false},
+			{"clicolor_0", ColorModeAuto, map[string]string{"CLICOLOR": "0"}, false},
+			{"term_dumb", ColorModeAuto, map[string]string{"TERM": "dumb"}, false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for _, key := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "TERM"} {
+					t.Setenv(key, "")
+				}
+				unsetEnv(t, "FORCE_COLOR")
+				for key, value := range tt.env {
+					t.Setenv(key, value)
+				}
+				assert.Equal(t, tt.expected, useColor(&bytes.Buffer{}, tt.mode))
+			})
+		}
+	})
+}
@@ -4,77 +4,412 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"reflect"
 	"runtime"
 	"strconv"
-	"time"
+	"strings"
 	"unicode/utf8"
 
+	"golang.org/x/term"
+
 	"github.com/fornellas/slogxt/ansi"
 	"github.com/fornellas/slogxt/unicode"
 )
 
 // ANSI color scheme
 type TerminalHandlerColorScheme struct {
-	GroupName    ansi.SGRs
-	AttrKey      ansi.SGRs
-	AttrValue    ansi.SGRs
-	Time         ansi.SGRs
-	LevelDebug   ansi.SGRs
-	MessageDebug ansi.SGRs
-	LevelInfo    ansi.SGRs
-	MessageInfo  ansi.SGRs
-	LevelWarn    ansi.SGRs
-	MessageWarn  ansi.SGRs
-	LevelError   ansi.SGRs
-	MessageError ansi.SGRs
-	File         ansi.SGRs
-	Line         ansi.SGRs
-	Function     ansi.SGRs
+	GroupName ansi.Style
+	AttrKey   ansi.Style
+	AttrValue ansi.Style
+	// Attribute values styles per kind; when zero, AttrValue is used.
+	AttrValueString   ansi.Style
+	AttrValueNumber   ansi.Style
+	AttrValueBool     ansi.Style
+	AttrValueDuration ansi.Style
+	AttrValueTime     ansi.Style
+	// Nil values and empty strings.
+	AttrValueNil   ansi.Style
+	AttrValueError ansi.Style
+
+	Time         ansi.Style
+	LevelDebug   ansi.Style
+	MessageDebug ansi.Style
+	LevelInfo    ansi.Style
+	MessageInfo  ansi.Style
+	LevelWarn    ansi.Style
+	MessageWarn  ansi.Style
+	LevelError   ansi.Style
+	MessageError ansi.Style
+	File         ansi.Style
+	Line         ansi.Style
+	Function     ansi.Style
+	Elision      ansi.Style
+	// Tree guides drawn by TerminalTreeHandler.
+	TreeGuide ansi.Style
+	// Syntax coloring for pretty printed JSON and YAML values.
+	SyntaxKey         ansi.Style
+	SyntaxString      ansi.Style
+	SyntaxNumber      ansi.Style
+	SyntaxBool        ansi.Style
+	SyntaxNull        ansi.Style
+	SyntaxPunctuation ansi.Style
 }
 
 var DefaultTerminalHandlerColorScheme = &TerminalHandlerColorScheme{
-	GroupName:    ansi.SGRs{},
-	AttrKey:      ansi.SGRs{ansi.FgCyan, ansi.Dim},
-	AttrValue:    ansi.SGRs{ansi.Dim},
-	Time:         ansi.SGRs{ansi.Dim},
-	LevelDebug:   ansi.SGRs{ansi.FgCyan, ansi.Bold},
-	MessageDebug: ansi.SGRs{ansi.Bold},
-	LevelInfo:    ansi.SGRs{ansi.FgGreen, ansi.Bold},
-	MessageInfo:  ansi.SGRs{ansi.Bold},
-	LevelWarn:    ansi.SGRs{ansi.FgYellow, ansi.Bold},
-	MessageWarn:  ansi.SGRs{ansi.Bold},
-	LevelError:   ansi.SGRs{ansi.FgRed, ansi.Bold},
-	MessageError: ansi.SGRs{ansi.Bold},
-	File:         ansi.SGRs{ansi.Dim, ansi.FgBlue},
-	Line:         ansi.SGRs{ansi.Dim, ansi.FgBlue},
-	Function:     ansi.SGRs{ansi.Dim, ansi.FgBlue},
+	GroupName: ansi.Style{},
+	AttrKey:   ansi.NewStyle(ansi.FgCyan, ansi.Dim),
+	AttrValue: ansi.NewStyle(ansi.Dim),
+
+	AttrValueString:   ansi.NewStyle(ansi.Dim),
+	AttrValueNumber:   ansi.NewStyle(ansi.FgMagenta),
+	AttrValueBool:     ansi.NewStyle(ansi.FgYellow),
+	AttrValueDuration: ansi.NewStyle(ansi.FgBlue),
+	AttrValueTime:     ansi.NewStyle(ansi.FgBlue, ansi.Dim),
+	AttrValueNil:      ansi.NewStyle(ansi.Dim, ansi.Italic),
+	AttrValueError:    ansi.NewStyle(ansi.FgRed),
+
+	Time:         ansi.NewStyle(ansi.Dim),
+	LevelDebug:   ansi.NewStyle(ansi.FgCyan, ansi.Bold),
+	MessageDebug: ansi.NewStyle(ansi.Bold),
+	LevelInfo:    ansi.NewStyle(ansi.FgGreen, ansi.Bold),
+	MessageInfo:  ansi.NewStyle(ansi.Bold),
+	LevelWarn:    ansi.NewStyle(ansi.FgYellow, ansi.Bold),
+	MessageWarn:  ansi.NewStyle(ansi.Bold),
+	LevelError:   ansi.NewStyle(ansi.FgRed, ansi.Bold),
+	MessageError: ansi.NewStyle(ansi.Bold),
+	File:         ansi.NewStyle(ansi.Dim, ansi.FgBlue),
+	Line:         ansi.NewStyle(ansi.Dim, ansi.FgBlue),
+	Function:     ansi.NewStyle(ansi.Dim, ansi.FgBlue),
+	Elision:      ansi.NewStyle(ansi.Dim, ansi.Italic),
+	TreeGuide:    ansi.NewStyle(ansi.Dim),
+
+	SyntaxKey:         ansi.NewStyle(ansi.FgBlue),
+	SyntaxString:      ansi.NewStyle(ansi.FgGreen),
+	SyntaxNumber:      ansi.NewStyle(ansi.FgMagenta),
+	SyntaxBool:        ansi.NewStyle(ansi.FgYellow),
+	SyntaxNull:        ansi.NewStyle(ansi.Dim),
+	SyntaxPunctuation: ansi.NewStyle(ansi.Dim),
+}
+
+// attrValue returns the style for the attribute value, as a function of its kind.
+func (cs *TerminalHandlerColorScheme) attrValue(value slog.Value) ansi.Style {
+	switch value.Kind() {
+	case slog.KindString:
+		if len(value.String()) == 0 {
+			return cs.attrValueOr(cs.AttrValueNil)
+		}
+		return cs.attrValueOr(cs.AttrValueString)
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64:
+		return cs.attrValueOr(cs.AttrValueNumber)
+	case slog.KindBool:
+		return cs.attrValueOr(cs.AttrValueBool)
+	case slog.KindDuration:
+		return cs.attrValueOr(cs.AttrValueDuration)
+	case slog.KindTime:
+		return cs.attrValueOr(cs.AttrValueTime)
+	case slog.KindAny:
+		switch value.Any().(type) {
+		case nil:
+			return cs.attrValueOr(cs.AttrValueNil)
+		case TerminalValuer:
+			// may implement error, but carries its own colors
+		case error:
+			return cs.attrValueOr(cs.AttrValueError)
+		}
+	}
+	return cs.AttrValue
+}
+
+// attrValueOr returns style, or AttrValue if style is zero.
+func (cs *TerminalHandlerColorScheme) attrValueOr(style ansi.Style) ansi.Style {
+	if style.IsZero() {
+		return cs.AttrValue
+	}
+	return style
+}
+
+// downsample returns a copy of the color scheme, with all colors downsampled to the given
+// color level.
+func (cs *TerminalHandlerColorScheme) downsample(level ansi.ColorLevel) *TerminalHandlerColorScheme {
+	downsampled := *cs
+	v := reflect.ValueOf(&downsampled).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if style, ok := v.Field(i).Interface().(ansi.Style); ok {
+			v.Field(i).Set(reflect.ValueOf(style.Downsample(level)))
+		}
+	}
+	return &downsampled
 }
 
 // TerminalHandlerOptions extends HandlerOptions with specific options.
 type TerminalHandlerOptions struct {
 	slog.HandlerOptions
-	// Time layout for timestamps; if empty, time is not included in output.
+	// Time layout for timestamps with TimeModeAbsolute; if empty, time is not included in output.
 	TimeLayout string
-	// If true, force ANSI escape sequences for color, even when no TTY detected.
+	// How record times are displayed. Defaults to TimeModeAbsolute.
+	TimeMode TimeMode
+	// When to use ANSI escape sequences for color. Defaults to ColorModeAuto.
+	ColorMode ColorMode
+	// If true, force ANSI escape sequences for color, even when no TTY detected; same as
+	// ColorModeAlways, and takes precedence over ColorMode.
 	ForceColor bool
-	// If true, disable color, even when TTY detected; takes precedence over ForceColor.
+	// If true, disable color, even when TTY detected; same as ColorModeNever, and takes precedence
+	// over ColorMode and ForceColor.
 	NoColor bool
-	// ANSI color scheme. Default to DefaultColorScheme if unset.
+	// ANSI color scheme. If unset, defaults to DefaultTerminalHandlerColorScheme, or to
+	// LightTerminalHandlerColorScheme for a light Background.
 	ColorScheme *TerminalHandlerColorScheme
+	// Terminal background, that the default ColorScheme is picked for. Defaults to BackgroundAuto.
+	Background Background
+	// How levels are displayed. If nil, levels are displayed with the slog names, eg: "DEBUG-4".
+	// Set to DefaultTerminalLevels to name levels -8, 2 and 12 as "TRACE", "NOTICE" and "FATAL".
+	Levels TerminalLevels
+	// How level names are displayed. Defaults to LevelFormatFull. LevelFormatPadded and
+	// LevelFormatShort display levels in a fixed width column.
+	LevelFormat LevelFormat
+	// If true, TerminalLineHandler pads the start of messages and of attribute lists to align them
+	// across consecutive lines, at the widest column of the last lines.
+	AlignColumns bool
+	// Color level supported by the terminal, to which ColorScheme colors are downsampled. If 0,
+	// it is detected with ansi.DetectColorLevel.
+	ColorLevel ansi.ColorLevel
+	// How source file paths are displayed, with AddSource and error stack traces. Defaults to
+	// SourcePathFull.
+	SourcePath SourcePath
+	// Prefixes removed from source file paths, taking precedence over SourcePath.
+	SourceTrimPrefixes []string
+	// If true, source function names are displayed without their package path, eg:
+	// "log.(*TerminalTreeHandler).Handle".
+	SourceShortFunction bool
+	// If true, source locations and URL attribute values are written as OSC 8 hyperlinks, for
+	// terminals that support them. Ignored when color is not used.
+	Hyperlinks bool
+	// URL template for source location hyperlinks, where {path} and {line} are replaced by the
+	// absolute file path and line number, eg: "vscode://file/{path}:{line}". Defaults to
+	// DefaultHyperlinkTemplate.
+	HyperlinkTemplate string
+	// How TerminalTreeHandler draws the nesting of groups and attributes. Defaults to
+	// TreeGuidesNone.
+	TreeGuides TreeGuides
+	// Returns the icon written before each group name, or no icon if empty. Defaults to
+	// DefaultGroupIcon, or to NoGroupIcon with ASCII.
+	GroupIcon func(name string) string
+	// If true, only ASCII characters are used for decorations: no group icon by default, no level
+	// emoji, "..." for elisions, and ASCII tree guides.
+	ASCII bool
+	// Maximum width of output lines, used to soft-wrap long attribute values. If 0, values are
+	// not wrapped. If TerminalWidthAuto, the terminal width is detected, and values are not
+	// wrapped when no terminal is detected.
+	Width int
+	// Maximum number of characters of each attribute value, with the excess elided. If 0, there's
+	// no limit.
+	MaxValueLength int
+	// Maximum number of lines of each multiline attribute value, with the excess elided. If 0,
+	// there's no limit.
+	MaxValueLines int
+	// Maximum number of attributes of each group, with the excess elided. If 0, there's no limit.
+	MaxGroupAttrs int
+	// If true, error attribute values include stack traces from errors that expose them with a
+	// StackTrace or Frames method.
+	ErrorStackTrace bool
+	// Maximum depth to expand map, slice, array and struct attribute values into nested keys,
+	// indexes and field names. If 0, they are not expanded.
+	CompositeValueDepth int
+	// If true, string attribute values holding JSON are pretty printed and syntax colored.
+	PrettyJSON bool
+	// If true, multiline string attribute values holding YAML mappings or sequences are pretty
+	// printed and syntax colored.
+	PrettyYAML bool
+
+	// Level names, as displayed with LevelFormat.
+	levelNames levelNames
 }
 
-func writeLevel(
-	w io.Writer, colorScheme *TerminalHandlerColorScheme, level slog.Level,
-) (int, error) {
-	if level >= slog.LevelError {
-		return colorScheme.LevelError.Fprintf(w, "%s", level.String())
-	} else if level >= slog.LevelWarn {
-		return colorScheme.LevelWarn.Fprintf(w, "%s", level.String())
-	} else if level >= slog.LevelInfo {
-		return colorScheme.LevelInfo.Fprintf(w, "%s", level.String())
+// newTerminalHandlerOptions returns a copy of opts with defaults set, and with color and width
+// resolved for writing to w.
+func newTerminalHandlerOptions(w io.Writer, opts *TerminalHandlerOptions) *TerminalHandlerOptions {
+	var optsValue TerminalHandlerOptions
+	if opts != nil {
+		optsValue = *opts
+	}
+
+	if optsValue.GroupIcon == nil {
+		if optsValue.ASCII {
+			optsValue.GroupIcon = NoGroupIcon
+		} else {
+			optsValue.GroupIcon = DefaultGroupIcon
+		}
+	}
+	if optsValue.ASCII {
+		optsValue.Levels = optsValue.Levels.withoutEmoji()
+		if optsValue.TreeGuides == TreeGuidesUnicode {
+			optsValue.TreeGuides = TreeGuidesASCII
+		}
+	}
+
+	colorMode := optsValue.ColorMode
+	if optsValue.NoColor {
+		colorMode = ColorModeNever
+	} else if optsValue.ForceColor {
+		colorMode = ColorModeAlways
+	}
+	if useColor(w, colorMode) {
+		if optsValue.ColorLevel == 0 {
+			optsValue.ColorLevel = ansi.DetectColorLevel()
+		}
+		if optsValue.ColorScheme == nil {
+			optsValue.ColorScheme = optsValue.Background.colorScheme(w)
+		}
+		optsValue.ColorScheme = optsValue.ColorScheme.downsample(optsValue.ColorLevel)
+		optsValue.Levels = optsValue.Levels.resolve(true, optsValue.ColorLevel)
 	} else {
-		return colorScheme.LevelDebug.Fprintf(w, "%s", level.String())
+		optsValue.ColorScheme = &TerminalHandlerColorScheme{}
+		optsValue.Levels = optsValue.Levels.resolve(false, 0)
+		optsValue.Hyperlinks = false
+	}
+
+	optsValue.Width = resolveWidth(w, optsValue.Width)
+	optsValue.levelNames = optsValue.Levels.names(optsValue.LevelFormat == LevelFormatShort)
+
+	return &optsValue
+}
+
+// TerminalWidthAuto can be set at TerminalHandlerOptions.Width to detect the terminal width.
+const TerminalWidthAuto = -1
+
+func resolveWidth(w io.Writer, width int) int {
+	if width != TerminalWidthAuto {
+		return width
 	}
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			return width
+		}
+	}
+	return 0
+}
+
+// textWidth returns the number of columns s takes when displayed, not counting ANSI escape
+// sequences.
+func textWidth(s string) int {
+	return unicode.StringWidth(s)
+}
+
+// wrapLine splits s into chunks of at most width columns, not counting ANSI escape sequences,
+// which are kept together with the text preceding them. Lines are broken after the last space
+// that fits, and only split within a word when it does not fit a chunk of its own. Grapheme
+// clusters are never split, and a cluster wider than width takes a chunk of its own. If width is
+// not positive, s is returned as is.
+func wrapLine(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+	lines := []string{}
+	start := 0
+	columns := 0
+	offset := 0
+	// offset after the last space of the current chunk, and its columns up to it
+	breakOffset, breakColumns := -1, 0
+	for _, token := range ansi.Tokenize(s) {
+		if token.Kind != ansi.TokenText {
+			offset += len(token.Text)
+			continue
+		}
+		for i := 0; i < len(token.Text); {
+			grapheme, graphemeWidth := unicode.FirstGrapheme(token.Text[i:])
+			if columns > 0 && columns+graphemeWidth > width {
+				if breakOffset > start {
+					lines = append(lines, s[start:breakOffset])
+					start = breakOffset
+					columns -= breakColumns
+				}
+				breakOffset = -1
+				if columns > 0 && columns+graphemeWidth > width {
+					lines = append(lines, s[start:offset+i])
+					start = offset + i
+					columns = 0
+				}
+			}
+			columns += graphemeWidth
+			i += len(grapheme)
+			if grapheme == " " {
+				breakOffset, breakColumns = offset+i, columns
+			}
+		}
+		offset += len(token.Text)
+	}
+	return append(lines, s[start:])
+}
+
+// elideValue limits value to maxLines lines and then to maxLength characters, not counting ANSI
+// escape sequences. A limit of 0 means no limit. It returns the limited value, and the number and
+// units of what was elided: "lines" when lines were elided, or else "bytes".
+func elideValue(value string, maxLength, maxLines int) (string, int, string) {
+	limitedValue := value
+	var elidedLines int
+	if maxLines > 0 {
+		lines := 0
+		for i := 0; i < len(limitedValue); i++ {
+			if limitedValue[i] == '\n' {
+				lines++
+				if lines == maxLines {
+					elidedLines = strings.Count(limitedValue[i:], "\n")
+					limitedValue = limitedValue[:i]
+					break
+				}
+			}
+		}
+	}
+	if maxLength > 0 {
+		length := 0
+		offset := 0
+	tokens:
+		for _, token := range ansi.Tokenize(limitedValue) {
+			if token.Kind != ansi.TokenText {
+				offset += len(token.Text)
+				continue
+			}
+			for i := 0; i < len(token.Text); {
+				if length == maxLength {
+					limitedValue = limitedValue[:offset+i]
+					break tokens
+				}
+				_, size := utf8.DecodeRuneInString(token.Text[i:])
+				length++
+				i += size
+			}
+			offset += len(token.Text)
+		}
+	}
+	if len(limitedValue) == len(value) {
+		return value, 0, ""
+	}
+	elided, units := len(stripANSI(value))-len(stripANSI(limitedValue)), "bytes"
+	if elidedLines > 0 {
+		elided, units = elidedLines, "lines"
+	}
+	if len(stripANSI(limitedValue)) != len(limitedValue) {
+		limitedValue += ansi.Reset.String()
+		if strings.Contains(limitedValue, ansi.OSC+"8;") {
+			limitedValue += ansi.HyperlinkEnd
+		}
+	}
+	return limitedValue, elided, units
+}
+
+// writeElision writes a marker informing that n units were elided from the output.
+func writeElision(
+	w io.Writer, opts *TerminalHandlerOptions, n int, units string,
+) (int, error) {
+	if n == 1 {
+		units = strings.TrimSuffix(units, "s")
+	}
+	ellipsis := "…"
+	if opts.ASCII {
+		ellipsis = "..."
+	}
+	return opts.ColorScheme.Elision.Fprintf(w, "%s %d more %s", ellipsis, n, units)
 }
 
 func escape(s string) string {
@@ -91,36 +426,20 @@ func escape(s string) string {
 	return string(rs)
 }
 
-func writeMessage(
-	w io.Writer, colorScheme *TerminalHandlerColorScheme, level slog.Level, message string,
-) (int, error) {
-	message = escape(message)
-	if level >= slog.LevelError {
-		return colorScheme.MessageError.Fprintf(w, "%s", message)
-	} else if level >= slog.LevelWarn {
-		return colorScheme.MessageWarn.Fprintf(w, "%s", message)
-	} else if level >= slog.LevelInfo {
-		return colorScheme.MessageInfo.Fprintf(w, "%s", message)
-	} else {
-		return colorScheme.MessageDebug.Fprintf(w, "%s", message)
-	}
-}
-
 func writeTime(
 	w io.Writer,
-	timeLayout string,
-	t time.Time,
+	timeText string,
 	colorScheme *TerminalHandlerColorScheme,
 ) (int, error) {
-	if timeLayout != "" && !t.IsZero() {
-		return colorScheme.Time.Fprintf(w, "%s", t.Round(0).Format(timeLayout))
+	if timeText != "" {
+		return colorScheme.Time.Fprintf(w, "%s", timeText)
 	}
 	return 0, nil
 }
 
 func writePC(
 	w io.Writer,
-	colorScheme *TerminalHandlerColorScheme,
+	opts *TerminalHandlerOptions,
 	pc uintptr,
 ) error {
 	if pc == 0 {
@@ -128,20 +447,39 @@ func writePC(
 	}
 	frames := runtime.CallersFrames([]uintptr{pc})
 	frame, _ := frames.Next()
-	if _, err := colorScheme.File.Fprintf(w, "%s", frame.File); err != nil {
+	return writeFrame(w, opts, frame)
+}
+
+func writeFrame(
+	w io.Writer,
+	opts *TerminalHandlerOptions,
+	frame runtime.Frame,
+) error {
+	link := sourceHyperlink(opts, frame.File, frame.Line)
+	if link != "" {
+		if _, err := io.WriteString(w, ansi.HyperlinkStart(link)); err != nil {
+			return err
+		}
+	}
+	if _, err := opts.ColorScheme.File.Fprintf(w, "%s", sourceFile(opts, frame.File, frame.Function)); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintf(w, ":"); err != nil {
 		return err
 	}
-	if _, err := colorScheme.Line.Fprintf(w, "%d", frame.Line); err != nil {
+	if _, err := opts.ColorScheme.Line.Fprintf(w, "%d", frame.Line); err != nil {
 		return err
 	}
+	if link != "" {
+		if _, err := io.WriteString(w, ansi.HyperlinkEnd); err != nil {
+			return err
+		}
+	}
 	if len(frame.Function) > 0 {
 		if _, err := fmt.Fprintf(w, " ("); err != nil {
 			return err
 		}
-		if _, err := colorScheme.Function.Fprintf(w, "%s", frame.Function); err != nil {
+		if _, err := opts.ColorScheme.Function.Fprintf(w, "%s", sourceFunction(opts, frame.Function)); err != nil {
 			return err
 		}
 		if _, err := fmt.Fprintf(w, ")"); err != nil {
@@ -151,26 +489,35 @@ func writePC(
 	return nil
 }
 
+// DefaultGroupIcon returns "🏷️" for group names not starting with an emoji, and no icon otherwise.
+func DefaultGroupIcon(name string) string {
+	if unicode.HasEmojiPrefix(name) {
+		return ""
+	}
+	return "🏷️"
+}
+
+// NoGroupIcon returns no icon for all group names.
+func NoGroupIcon(string) string {
+	return ""
+}
+
 func writeGroup(
 	w io.Writer,
-	colorScheme *TerminalHandlerColorScheme,
+	opts *TerminalHandlerOptions,
 	name string,
 ) (int, error) {
-	emoji := ""
-	r, _ := utf8.DecodeRuneInString(name)
-	if !unicode.IsEmojiStartCodePoint(r) {
-		emoji = "🏷️ "
-	}
-
 	var n, nt int
 	var err error
 
-	if n, err = fmt.Fprintf(w, "%s", emoji); err != nil {
-		return n, err
+	if icon := opts.GroupIcon(name); icon != "" {
+		if n, err = fmt.Fprintf(w, "%s ", icon); err != nil {
+			return n, err
+		}
+		nt += n
 	}
-	nt += n
 
-	if n, err = colorScheme.GroupName.Fprintf(w, "%s", escape(name)); err != nil {
+	if n, err = opts.ColorScheme.GroupName.Fprintf(w, "%s", escape(name)); err != nil {
 		return nt + n, err
 	}
 	nt += n
@@ -0,0 +1,139 @@
+package log
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// compositeValue is a map, slice, array or struct value expanded into attributes, keyed by map
+// keys, indexes or struct field names. Attribute values that are themselves expanded composite
+// values hold a compositeValue.
+type compositeValue struct {
+	// True for slices and arrays.
+	list  bool
+	attrs []slog.Attr
+}
+
+// cycleValue replaces values that reference one of their parents.
+var cycleValue = slog.StringValue("<cycle>")
+
+// compositeParent identifies a pointer, map or slice being expanded, for cycle detection. The type
+// is part of it, as a struct and its first field, or a slice and its first element, share the
+// same address.
+type compositeParent struct {
+	pointer uintptr
+	typ     reflect.Type
+}
+
+// expandComposite expands values holding maps, slices, arrays or structs into a compositeValue, up
+// to depth levels. Values that know how to represent themselves (error, fmt.Stringer,
+// encoding.TextMarshaler, TerminalValuer) are not expanded.
+func expandComposite(value slog.Value, depth int) (compositeValue, bool) {
+	if value.Kind() != slog.KindAny || value.Any() == nil {
+		return compositeValue{}, false
+	}
+	cv, ok := compositeChildValue(reflect.ValueOf(value.Any()), depth+1, map[compositeParent]bool{}).Any().(compositeValue)
+	return cv, ok
+}
+
+func isSelfRepresented(value any) bool {
+	switch value.(type) {
+	case error, fmt.Stringer, encoding.TextMarshaler, TerminalValuer, slog.LogValuer, []byte, url.URL:
+		return true
+	}
+	return false
+}
+
+func expandCompositeAny(value any, depth int, parents map[compositeParent]bool) (compositeValue, bool) {
+	if depth <= 0 || value == nil || isSelfRepresented(value) {
+		return compositeValue{}, false
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return compositeValue{}, false
+		}
+		v = v.Elem()
+		if v.CanInterface() && isSelfRepresented(v.Interface()) {
+			return compositeValue{}, false
+		}
+	}
+
+	var cv compositeValue
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		keyStrs := make([]string, len(keys))
+		for i, key := range keys {
+			keyStrs[i] = fmt.Sprint(key.Interface())
+		}
+		indexes := make([]int, len(keys))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool { return keyStrs[indexes[i]] < keyStrs[indexes[j]] })
+		for _, i := range indexes {
+			cv.attrs = append(cv.attrs, slog.Attr{
+				Key:   keyStrs[i],
+				Value: compositeChildValue(v.MapIndex(keys[i]), depth, parents),
+			})
+		}
+	case reflect.Slice, reflect.Array:
+		cv.list = true
+		for i := 0; i < v.Len(); i++ {
+			cv.attrs = append(cv.attrs, slog.Attr{
+				Key:   strconv.Itoa(i),
+				Value: compositeChildValue(v.Index(i), depth, parents),
+			})
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			cv.attrs = append(cv.attrs, slog.Attr{
+				Key:   field.Name,
+				Value: compositeChildValue(v.Field(i), depth, parents),
+			})
+		}
+	default:
+		return compositeValue{}, false
+	}
+	return cv, true
+}
+
+// compositeChildValue returns the value for a child of a composite value at the given depth,
+// tracking its parents for cycle detection.
+func compositeChildValue(v reflect.Value, depth int, parents map[compositeParent]bool) slog.Value {
+	if !v.CanInterface() {
+		return slog.StringValue(v.String())
+	}
+	child := v.Interface()
+
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			parent := compositeParent{pointer: v.Pointer(), typ: v.Type()}
+			if parents[parent] {
+				return cycleValue
+			}
+			parents[parent] = true
+			defer delete(parents, parent)
+		}
+	}
+
+	if cv, ok := expandCompositeAny(child, depth-1, parents); ok {
+		return slog.AnyValue(cv)
+	}
+	return slog.AnyValue(child)
+}
@@ -0,0 +1,118 @@
+package log
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compositeTestStruct struct {
+	Name       string
+	Tags       []string
+	unexported int
+}
+
+type compositeTestNode struct {
+	Name string
+	Next *compositeTestNode
+}
+
+type compositeTestInner struct {
+	Value int
+}
+
+type compositeTestOuter struct {
+	Inner compositeTestInner
+	First *compositeTestInner
+}
+
+func TestExpandComposite(t *testing.T) {
+	t.Run("not_composite", func(t *testing.T) {
+		for _, value := range []slog.Value{
+			slog.StringValue("string"),
+			slog.IntValue(1),
+			slog.AnyValue(nil),
+			slog.AnyValue(time.Second),
+			slog.AnyValue(errors.New("error")),
+			slog.AnyValue([]byte("bytes")),
+			slog.AnyValue(&time.Time{}),
+		} {
+			_, ok := expandComposite(value, 10)
+			assert.False(t, ok, value.String())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, ok := expandComposite(slog.AnyValue([]int{1}), 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		cv, ok := expandComposite(slog.AnyValue(map[string]int{"b": 2, "a": 1}), 1)
+		assert.True(t, ok)
+		assert.Equal(t, compositeValue{
+			attrs: []slog.Attr{slog.Int("a", 1), slog.Int("b", 2)},
+		}, cv)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		cv, ok := expandComposite(slog.AnyValue(&compositeTestStruct{
+			Name:       "name",
+			Tags:       []string{"x"},
+			unexported: 1,
+		}), 2)
+		assert.True(t, ok)
+		assert.Equal(t, compositeValue{
+			attrs: []slog.Attr{
+				slog.String("Name", "name"),
+				slog.Any("Tags", compositeValue{
+					list:  true,
+					attrs: []slog.Attr{slog.String("0", "x")},
+				}),
+			},
+		}, cv)
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		cv, ok := expandComposite(slog.AnyValue([][]int{{1}}), 1)
+		assert.True(t, ok)
+		assert.Equal(t, compositeValue{
+			list:  true,
+			attrs: []slog.Attr{slog.Any("0", []int{1})},
+		}, cv)
+	})
+
+	t.Run("first_field_struct", func(t *testing.T) {
+		outer := &compositeTestOuter{Inner: compositeTestInner{Value: 1}}
+		outer.First = &outer.Inner
+		cv, ok := expandComposite(slog.AnyValue(outer), 10)
+		assert.True(t, ok)
+		assert.Equal(t, compositeValue{
+			attrs: []slog.Attr{
+				slog.Any("Inner", compositeValue{attrs: []slog.Attr{slog.Int("Value", 1)}}),
+				slog.Any("First", compositeValue{attrs: []slog.Attr{slog.Int("Value", 1)}}),
+			},
+		}, cv)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		node := &compositeTestNode{Name: "a"}
+		node.Next = &compositeTestNode{Name: "b", Next: node}
+		cv, ok := expandComposite(slog.AnyValue(node), 10)
+		assert.True(t, ok)
+		assert.Equal(t, compositeValue{
+			attrs: []slog.Attr{
+				slog.String("Name", "a"),
+				slog.Any("Next", compositeValue{
+					attrs: []slog.Attr{
+						slog.String("Name", "b"),
+						{Key: "Next", Value: cycleValue},
+					},
+				}),
+			},
+		}, cv)
+	})
+}
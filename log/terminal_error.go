@@ -0,0 +1,159 @@
+package log
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// errorValue returns the error held by v, unless it is a TerminalValuer, which takes precedence.
+func errorValue(v slog.Value) (error, bool) {
+	if v.Kind() != slog.KindAny {
+		return nil, false
+	}
+	if _, ok := v.Any().(TerminalValuer); ok {
+		return nil, false
+	}
+	err, ok := v.Any().(error)
+	return err, ok
+}
+
+// errorNode is a node from the tree of errors wrapped and joined by an error.
+type errorNode struct {
+	// Message of the error itself, excluding the messages of the wrapped errors. It is empty for
+	// errors that only join other errors.
+	message  string
+	frames   []runtime.Frame
+	children []errorNode
+}
+
+// errorMessage returns the message of err, as fmt would: "<nil>" for nil pointers, and a
+// "%!v(PANIC=Error method: ...)" message when its Error method panics.
+func errorMessage(err error) (message string) {
+	if isNilPointer(err) {
+		return "<nil>"
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			message = fmt.Sprintf("%%!v(PANIC=Error method: %v)", r)
+		}
+	}()
+	return err.Error()
+}
+
+// isNilPointer returns whether err holds a nil pointer, whose methods may panic.
+func isNilPointer(err error) bool {
+	v := reflect.ValueOf(err)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// newErrorNode builds the tree of errors wrapped and joined by err. If stackTrace is true, frames
+// from errors exposing stack traces are included.
+func newErrorNode(err error, stackTrace bool) errorNode {
+	node := errorNode{
+		message: errorMessage(err),
+	}
+	if isNilPointer(err) {
+		return node
+	}
+	if stackTrace {
+		node.frames = errorFrames(err)
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		messages := []string{}
+		for _, child := range e.Unwrap() {
+			if child == nil {
+				continue
+			}
+			node.children = append(node.children, newErrorNode(child, stackTrace))
+			messages = append(messages, errorMessage(child))
+		}
+		if node.message == strings.Join(messages, "\n") {
+			node.message = ""
+		}
+	case interface{ Unwrap() error }:
+		child := e.Unwrap()
+		if child == nil {
+			break
+		}
+		childNode := newErrorNode(child, stackTrace)
+		childMessage := errorMessage(child)
+		if message, ok := strings.CutSuffix(node.message, ": "+childMessage); ok {
+			node.message = message
+		} else if node.message == childMessage {
+			// Errors that only wrap to add context, such as a stack trace, are merged with the
+			// wrapped error.
+			if len(childNode.frames) == 0 {
+				childNode.frames = node.frames
+			}
+			return childNode
+		} else if strings.Contains(node.message, childMessage) {
+			// The message already includes the wrapped error in some other format, which is not
+			// repeated.
+			if len(node.frames) == 0 {
+				node.frames = childNode.frames
+			}
+			break
+		}
+		node.children = []errorNode{childNode}
+	}
+
+	return node
+}
+
+// errorFrames returns the stack trace of err, when it has a StackTrace or Frames method, returning
+// either *runtime.Frames, []runtime.Frame or a slice of program counters (eg:
+// github.com/pkg/errors StackTrace). It returns no frames for nil pointers, or when the method
+// panics.
+func errorFrames(err error) (frames []runtime.Frame) {
+	if isNilPointer(err) {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			frames = nil
+		}
+	}()
+	v := reflect.ValueOf(err)
+	for _, name := range []string{"StackTrace", "Frames"} {
+		method := v.MethodByName(name)
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		result := method.Call(nil)[0]
+		switch r := result.Interface().(type) {
+		case []runtime.Frame:
+			return r
+		case *runtime.Frames:
+			if r != nil {
+				return collectFrames(r)
+			}
+			continue
+		}
+		if result.Kind() == reflect.Slice && result.Type().Elem().Kind() == reflect.Uintptr {
+			pcs := make([]uintptr, result.Len())
+			for i := range pcs {
+				pcs[i] = uintptr(result.Index(i).Uint())
+			}
+			return collectFrames(runtime.CallersFrames(pcs))
+		}
+	}
+	return nil
+}
+
+func collectFrames(frames *runtime.Frames) []runtime.Frame {
+	collected := []runtime.Frame{}
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 || frame.File != "" {
+			collected = append(collected, frame)
+		}
+		if !more {
+			return collected
+		}
+	}
+}
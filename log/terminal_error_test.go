@@ -0,0 +1,191 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stackError exposes its stack trace as program counters, similar to github.com/pkg/errors.
+type stackError struct {
+	message string
+	pcs     []uintptr
+}
+
+type programCounter uintptr
+
+func newStackError(message string) *stackError {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{message: message, pcs: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.message
+}
+
+func (e *stackError) StackTrace() []programCounter {
+	stackTrace := make([]programCounter, len(e.pcs))
+	for i, pc := range e.pcs {
+		stackTrace[i] = programCounter(pc)
+	}
+	return stackTrace
+}
+
+// framesError exposes a fixed stack trace as frames.
+type framesError struct {
+	message string
+}
+
+func (e framesError) Error() string {
+	return e.message
+}
+
+func (e framesError) Frames() []runtime.Frame {
+	return []runtime.Frame{
+		{File: "/src/main.go", Line: 10, Function: "main.run"},
+		{File: "/src/main.go", Line: 3, Function: "main.main"},
+	}
+}
+
+// contextError wraps an error without adding to its message.
+type contextError struct {
+	err error
+}
+
+func (e contextError) Error() string {
+	return e.err.Error()
+}
+
+func (e contextError) Unwrap() error {
+	return e.err
+}
+
+func (e contextError) Frames() []runtime.Frame {
+	return []runtime.Frame{
+		{File: "/src/context.go", Line: 1, Function: "context"},
+	}
+}
+
+// messageError wraps an error with a message that does not follow the "message: wrapped" format.
+type messageError struct {
+	message string
+	err     error
+}
+
+func (e messageError) Error() string {
+	return e.message
+}
+
+func (e messageError) Unwrap() error {
+	return e.err
+}
+
+// panicError has an Error method that panics.
+type panicError struct{}
+
+func (e panicError) Error() string {
+	panic("boom")
+}
+
+// panicFramesError has a Frames method that panics.
+type panicFramesError struct{}
+
+func (e panicFramesError) Error() string {
+	return "frames panic"
+}
+
+func (e panicFramesError) Frames() []runtime.Frame {
+	panic("boom")
+}
+
+func TestErrorNode(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		assert.Equal(t, errorNode{message: "boom"}, newErrorNode(errors.New("boom"), true))
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		err := fmt.Errorf("open config: %w", fmt.Errorf("read file: %w", errors.New("not found")))
+		assert.Equal(t, errorNode{
+			message: "open config",
+			children: []errorNode{{
+				message: "read file",
+				children: []errorNode{{
+					message: "not found",
+				}},
+			}},
+		}, newErrorNode(err, false))
+	})
+
+	t.Run("joined", func(t *testing.T) {
+		err := errors.Join(errors.New("a"), fmt.Errorf("b: %w", errors.New("c")))
+		assert.Equal(t, errorNode{
+			children: []errorNode{
+				{message: "a"},
+				{message: "b", children: []errorNode{{message: "c"}}},
+			},
+		}, newErrorNode(err, false))
+	})
+
+	t.Run("wrapped_without_message", func(t *testing.T) {
+		err := contextError{err: errors.New("boom")}
+		assert.Equal(t, errorNode{message: "boom"}, newErrorNode(err, false))
+		assert.Equal(t, errorNode{
+			message: "boom",
+			frames:  contextError{}.Frames(),
+		}, newErrorNode(err, true))
+	})
+
+	t.Run("wrapped_with_message_not_ending_in_child", func(t *testing.T) {
+		cause := errors.New("EOF")
+		assert.Equal(t, errorNode{message: "read failed (EOF)"}, newErrorNode(messageError{"read failed (EOF)", cause}, false))
+		assert.Equal(t, errorNode{
+			message:  "read failed",
+			children: []errorNode{{message: "EOF"}},
+		}, newErrorNode(messageError{"read failed", cause}, false))
+	})
+
+	t.Run("nil_pointer", func(t *testing.T) {
+		var err *stackError
+		assert.Equal(t, errorNode{message: "<nil>"}, newErrorNode(err, true))
+		assert.Equal(t, errorNode{
+			message:  "wrapped",
+			children: []errorNode{{message: "<nil>"}},
+		}, newErrorNode(fmt.Errorf("wrapped: %w", err), true))
+	})
+
+	t.Run("panicking_error_method", func(t *testing.T) {
+		assert.Equal(
+			t, errorNode{message: "%!v(PANIC=Error method: boom)"}, newErrorNode(panicError{}, false),
+		)
+	})
+
+	t.Run("panicking_frames_method", func(t *testing.T) {
+		assert.Equal(t, errorNode{message: "frames panic"}, newErrorNode(panicFramesError{}, true))
+		assert.Nil(t, errorFrames(panicFramesError{}))
+	})
+
+	t.Run("nil_pointer_frames", func(t *testing.T) {
+		var err *stackError
+		assert.Nil(t, errorFrames(err))
+	})
+
+	t.Run("frames", func(t *testing.T) {
+		assert.Equal(t, framesError{}.Frames(), errorFrames(framesError{}))
+	})
+
+	t.Run("program_counters", func(t *testing.T) {
+		frames := errorFrames(newStackError("boom"))
+		require.NotEmpty(t, frames)
+		assert.Regexp(t, `terminal_error_test\.go$`, frames[0].File)
+		assert.Regexp(t, `TestErrorNode`, frames[0].Function)
+	})
+
+	t.Run("no_stack_trace", func(t *testing.T) {
+		assert.Nil(t, errorFrames(errors.New("boom")))
+	})
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+// DefaultHyperlinkTemplate links source locations to local files.
+const DefaultHyperlinkTemplate = "file://{path}"
+
+// sourceHyperlink returns the URL to link a source location to, from opts.HyperlinkTemplate, or
+// an empty string if hyperlinks are disabled or the file path is not absolute.
+func sourceHyperlink(opts *TerminalHandlerOptions, file string, line int) string {
+	if !opts.Hyperlinks || !filepath.IsAbs(filepath.FromSlash(file)) {
+		return ""
+	}
+	template := opts.HyperlinkTemplate
+	if template == "" {
+		template = DefaultHyperlinkTemplate
+	}
+	return strings.NewReplacer(
+		"{path}", (&url.URL{Path: file}).EscapedPath(),
+		"{line}", strconv.Itoa(line),
+	).Replace(template)
+}
+
+// urlValue returns whether value holds a URL, and its string representation.
+func urlValue(value slog.Value) (string, bool) {
+	if value.Kind() != slog.KindAny {
+		return "", false
+	}
+	switch u := value.Any().(type) {
+	case *url.URL:
+		if u == nil {
+			return "", false
+		}
+		return u.String(), true
+	case url.URL:
+		return u.String(), true
+	default:
+		return "", false
+	}
+}
+
+// hyperlinkValue returns value as an escaped hyperlink to itself, if it holds a URL and
+// hyperlinks are enabled.
+func hyperlinkValue(value slog.Value, opts *TerminalHandlerOptions) (string, bool) {
+	if !opts.Hyperlinks {
+		return "", false
+	}
+	u, ok := urlValue(value)
+	if !ok {
+		return "", false
+	}
+	return ansi.Hyperlink(u, escape(u)), true
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+func TestSourceHyperlink(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     TerminalHandlerOptions
+		file     string
+		expected string
+	}{
+		{"disabled", TerminalHandlerOptions{}, "/src/main.go", ""},
+		{"default_template", TerminalHandlerOptions{Hyperlinks: true}, "/src/my app/main.go", "file:///src/my%20app/main.go"},
+		{
+			"template",
+			TerminalHandlerOptions{Hyperlinks: true, HyperlinkTemplate: "vscode://file/{path}:{line}"},
+			"/src/main.go",
+			"vscode://file//src/main.go:10",
+		},
+		{"relative_path", TerminalHandlerOptions{Hyperlinks: true}, "example.com/m/main.go", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sourceHyperlink(&tt.opts, tt.file, 10))
+		})
+	}
+}
+
+func TestHyperlinkValue(t *testing.T) {
+	u := &url.URL{Scheme: "https", Host: "example.com", Path: "/"}
+	opts := &TerminalHandlerOptions{Hyperlinks: true}
+
+	link, ok := hyperlinkValue(slog.AnyValue(u), opts)
+	assert.True(t, ok)
+	assert.Equal(t, ansi.Hyperlink("https://example.com/", "https://example.com/"), link)
+
+	link, ok = hyperlinkValue(slog.AnyValue(*u), opts)
+	assert.True(t, ok)
+	assert.Equal(t, ansi.Hyperlink("https://example.com/", "https://example.com/"), link)
+
+	injection := &url.URL{Scheme: "x", Opaque: "\033\\\033[2J"}
+	link, ok = hyperlinkValue(slog.AnyValue(injection), opts)
+	assert.True(t, ok)
+	assert.Equal(t, ansi.OSC+"8;;x:%1B\\%1B[2J"+ansi.ST+`x:\x1b\\x1b[2J`+ansi.HyperlinkEnd, link)
+
+	_, ok = hyperlinkValue(slog.AnyValue((*url.URL)(nil)), opts)
+	assert.False(t, ok)
+	_, ok = hyperlinkValue(slog.StringValue("https://example.com/"), opts)
+	assert.False(t, ok)
+	_, ok = hyperlinkValue(slog.AnyValue(u), &TerminalHandlerOptions{})
+	assert.False(t, ok)
+
+	elided, n, _ := elideValue(ansi.Hyperlink("https://example.com/", "https://example.com/"), 8, 0)
+	assert.Equal(t, 12, n)
+	assert.Equal(t, "https://", stripANSI(elided))
+	assert.Contains(t, elided, ansi.HyperlinkEnd)
+}
@@ -0,0 +1,356 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+// Non-standard levels, with names registered at DefaultTerminalLevels.
+const (
+	LevelTrace  slog.Level = -8
+	LevelNotice slog.Level = 2
+	LevelFatal  slog.Level = 12
+)
+
+// TerminalLevel describes how a level is displayed by terminal handlers.
+type TerminalLevel struct {
+	// Display name of the level.
+	Name string
+	// Abbreviated display name of the level, used with LevelFormatShort. If empty, the first 3
+	// characters of Name are used.
+	ShortName string
+	// Optional emoji displayed before the level name.
+	Emoji string
+	// Level name style. If zero, the color scheme level style for the level is used.
+	Level ansi.Style
+	// Message style. If zero, the color scheme message style for the level is used.
+	Message ansi.Style
+}
+
+// TerminalLevels is a registry of how levels are displayed by terminal handlers. Levels not
+// registered are displayed relative to the nearest registered or standard level below them, as
+// slog.Level.String does, eg: "NOTICE+1".
+type TerminalLevels map[slog.Level]TerminalLevel
+
+// DefaultTerminalLevels registers names and colors for LevelTrace, LevelNotice and LevelFatal.
+var DefaultTerminalLevels = TerminalLevels{
+	LevelTrace: {
+		Name:      "TRACE",
+		ShortName: "TRC",
+		Level:     ansi.NewStyle(ansi.FgCyan, ansi.Dim),
+	},
+	LevelNotice: {
+		Name:      "NOTICE",
+		ShortName: "NTC",
+		Level:     ansi.NewStyle(ansi.FgBlue, ansi.Bold),
+	},
+	LevelFatal: {
+		Name:      "FATAL",
+		ShortName: "FTL",
+		Level:     ansi.NewStyle(ansi.FgWhite, ansi.BgRed, ansi.Bold),
+		Message:   ansi.NewStyle(ansi.FgRed, ansi.Bold),
+	},
+}
+
+var standardLevels = []slog.Level{
+	slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError,
+}
+
+var standardLevelShortNames = map[slog.Level]string{
+	slog.LevelDebug: "DBG",
+	slog.LevelInfo:  "INF",
+	slog.LevelWarn:  "WRN",
+	slog.LevelError: "ERR",
+}
+
+// LevelFormat defines how terminal handlers display level names.
+type LevelFormat int
+
+const (
+	// Display level names as they are, eg: "INFO", "ERROR".
+	LevelFormatFull LevelFormat = iota
+	// Display level names padded to the width of the widest standard or registered level name,
+	// eg: "INFO ", "ERROR".
+	LevelFormatPadded
+	// Display abbreviated level names, eg: "INF", "ERR".
+	LevelFormatShort
+)
+
+var levelFormatNames = map[LevelFormat]string{
+	LevelFormatFull:   "full",
+	LevelFormatPadded: "padded",
+	LevelFormatShort:  "short",
+}
+
+// LevelFormatNames returns the names of all level formats.
+func LevelFormatNames() []string {
+	return []string{
+		levelFormatNames[LevelFormatFull],
+		levelFormatNames[LevelFormatPadded],
+		levelFormatNames[LevelFormatShort],
+	}
+}
+
+// String returns the name of the level format.
+func (f LevelFormat) String() string {
+	if name, ok := levelFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("LevelFormat(%d)", int(f))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f LevelFormat) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [LevelFormatNames], case insensitively.
+func (f *LevelFormat) UnmarshalText(data []byte) error {
+	for format, name := range levelFormatNames {
+		if strings.EqualFold(string(data), name) {
+			*f = format
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid level format %#v, valid options are %s", string(data), strings.Join(LevelFormatNames(), ", "),
+	)
+}
+
+// Name returns the display name for level.
+func (ls TerminalLevels) Name(level slog.Level) string {
+	return ls.name(level, false)
+}
+
+// ShortName returns the abbreviated display name for level.
+func (ls TerminalLevels) ShortName(level slog.Level) string {
+	return ls.name(level, true)
+}
+
+func (ls TerminalLevels) name(level slog.Level, short bool) string {
+	return ls.names(short).name(level)
+}
+
+// levelNames holds the names of standard and registered levels, as displayed with a LevelFormat.
+type levelNames struct {
+	// Standard and registered levels, sorted.
+	levels []slog.Level
+	// Names of levels.
+	names []string
+	// Width of the widest name.
+	width int
+}
+
+// names returns the names of standard and registered levels, or their abbreviated names if short.
+func (ls TerminalLevels) names(short bool) levelNames {
+	var n levelNames
+	n.levels = ls.levels()
+	n.names = make([]string, len(n.levels))
+	for i, l := range n.levels {
+		terminalLevel, registered := ls[l]
+		switch {
+		case registered && !short:
+			n.names[i] = terminalLevel.Name
+		case registered && terminalLevel.ShortName != "":
+			n.names[i] = terminalLevel.ShortName
+		case registered:
+			name := []rune(terminalLevel.Name)
+			n.names[i] = strings.ToUpper(string(name[:min(3, len(name))]))
+		case short:
+			n.names[i] = standardLevelShortNames[l]
+		default:
+			n.names[i] = l.String()
+		}
+		n.width = max(n.width, textWidth(n.names[i]))
+	}
+	return n
+}
+
+// name returns the name of level, relative to the nearest level at or below it, or to the lowest
+// level if it is below all of them, eg: "NOTICE+1".
+func (n levelNames) name(level slog.Level) string {
+	i := 0
+	for j, l := range n.levels {
+		if l <= level {
+			i = j
+		}
+	}
+	if level == n.levels[i] {
+		return n.names[i]
+	}
+	return fmt.Sprintf("%s%+d", n.names[i], level-n.levels[i])
+}
+
+// Parse parses a level name, case-insensitively, with an optional offset, eg: "trace", "NOTICE+1"
+// or "info-2". Registered names may contain "+" or "-", as only a trailing numeric offset is split
+// from the name. Names not registered are parsed with slog.Level.UnmarshalText.
+func (ls TerminalLevels) Parse(s string) (slog.Level, error) {
+	if level, ok := ls.lookup(s); ok {
+		return level, nil
+	}
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		if offset, err := strconv.Atoi(s[i:]); err == nil {
+			if level, ok := ls.lookup(s[:i]); ok {
+				return level + slog.Level(offset), nil
+			}
+		}
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// lookup returns the level registered with name, case-insensitively.
+func (ls TerminalLevels) lookup(name string) (slog.Level, bool) {
+	for level, terminalLevel := range ls {
+		if strings.EqualFold(name, terminalLevel.Name) {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// Names returns the names of standard and registered levels, sorted by level.
+func (ls TerminalLevels) Names() []string {
+	return ls.names(false).names
+}
+
+// levels returns standard and registered levels, sorted.
+func (ls TerminalLevels) levels() []slog.Level {
+	levels := append([]slog.Level{}, standardLevels...)
+	for level := range ls {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+	return slices.Compact(levels)
+}
+
+// width returns the width of the widest standard or registered level name, as displayed with
+// format.
+func (ls TerminalLevels) width(format LevelFormat) int {
+	return ls.names(format == LevelFormatShort).width
+}
+
+// resolve returns a copy of ls with styles downsampled to level, or removed if color is false.
+func (ls TerminalLevels) resolve(color bool, level ansi.ColorLevel) TerminalLevels {
+	resolved := make(TerminalLevels, len(ls))
+	for l, terminalLevel := range ls {
+		if color {
+			terminalLevel.Level = terminalLevel.Level.Downsample(level)
+			terminalLevel.Message = terminalLevel.Message.Downsample(level)
+		} else {
+			terminalLevel.Level = ansi.Style{}
+			terminalLevel.Message = ansi.Style{}
+		}
+		resolved[l] = terminalLevel
+	}
+	return resolved
+}
+
+// withoutEmoji returns a copy of ls without emoji.
+func (ls TerminalLevels) withoutEmoji() TerminalLevels {
+	levels := make(TerminalLevels, len(ls))
+	for l, terminalLevel := range ls {
+		terminalLevel.Emoji = ""
+		levels[l] = terminalLevel
+	}
+	return levels
+}
+
+// styles returns the level name and message styles for level. Levels not registered use the
+// styles of the nearest registered level below them, if it is not below the nearest standard
+// level, so that eg "FATAL+1" is styled as "FATAL", and "NOTICE+2" as "WARN".
+func (ls TerminalLevels) styles(
+	colorScheme *TerminalHandlerColorScheme, level slog.Level,
+) (ansi.Style, ansi.Style) {
+	var levelStyle, messageStyle ansi.Style
+	standardLevel := slog.Level(math.MinInt)
+	if level >= slog.LevelError {
+		levelStyle, messageStyle = colorScheme.LevelError, colorScheme.MessageError
+		standardLevel = slog.LevelError
+	} else if level >= slog.LevelWarn {
+		levelStyle, messageStyle = colorScheme.LevelWarn, colorScheme.MessageWarn
+		standardLevel = slog.LevelWarn
+	} else if level >= slog.LevelInfo {
+		levelStyle, messageStyle = colorScheme.LevelInfo, colorScheme.MessageInfo
+		standardLevel = slog.LevelInfo
+	} else {
+		levelStyle, messageStyle = colorScheme.LevelDebug, colorScheme.MessageDebug
+		if level >= slog.LevelDebug {
+			standardLevel = slog.LevelDebug
+		}
+	}
+
+	var nearest slog.Level
+	var found bool
+	for l := range ls {
+		if standardLevel <= l && l <= level && (!found || l > nearest) {
+			nearest, found = l, true
+		}
+	}
+	if found {
+		if terminalLevel := ls[nearest]; !terminalLevel.Level.IsZero() {
+			levelStyle = terminalLevel.Level
+		}
+		if terminalLevel := ls[nearest]; !terminalLevel.Message.IsZero() {
+			messageStyle = terminalLevel.Message
+		}
+	}
+	return levelStyle, messageStyle
+}
+
+func writeLevel(
+	w io.Writer,
+	colorScheme *TerminalHandlerColorScheme,
+	levels TerminalLevels,
+	format LevelFormat,
+	names levelNames,
+	level slog.Level,
+) (int, error) {
+	var n int
+	if terminalLevel, ok := levels[level]; ok && terminalLevel.Emoji != "" {
+		np, err := fmt.Fprintf(w, "%s ", terminalLevel.Emoji)
+		n += np
+		if err != nil {
+			return n, err
+		}
+	}
+	levelStyle, _ := levels.styles(colorScheme, level)
+	name := names.name(level)
+	np, err := levelStyle.Fprintf(w, "%s", name)
+	n += np
+	if err != nil {
+		return n, err
+	}
+	if format != LevelFormatFull {
+		if padding := names.width - textWidth(name); padding > 0 {
+			np, err := io.WriteString(w, strings.Repeat(" ", padding))
+			n += np
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func writeMessage(
+	w io.Writer,
+	colorScheme *TerminalHandlerColorScheme,
+	levels TerminalLevels,
+	level slog.Level,
+	message string,
+) (int, error) {
+	_, messageStyle := levels.styles(colorScheme, level)
+	return messageStyle.Fprintf(w, "%s", escape(message))
+}
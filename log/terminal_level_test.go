@@ -0,0 +1,168 @@
+package log
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+func TestLevelFormat(t *testing.T) {
+	for _, format := range []LevelFormat{LevelFormatFull, LevelFormatPadded, LevelFormatShort} {
+		text, err := format.MarshalText()
+		require.NoError(t, err)
+		var parsedFormat LevelFormat
+		require.NoError(t, parsedFormat.UnmarshalText(text))
+		assert.Equal(t, format, parsedFormat)
+	}
+
+	var format LevelFormat
+	require.NoError(t, format.UnmarshalText([]byte("SHORT")))
+	assert.Equal(t, LevelFormatShort, format)
+	require.Error(t, format.UnmarshalText([]byte("long")))
+}
+
+func TestTerminalLevels(t *testing.T) {
+	t.Run("Name", func(t *testing.T) {
+		tests := []struct {
+			level    slog.Level
+			expected string
+		}{
+			{LevelTrace - 1, "TRACE-1"},
+			{LevelTrace, "TRACE"},
+			{slog.LevelDebug, "DEBUG"},
+			{slog.LevelInfo, "INFO"},
+			{slog.LevelInfo + 1, "INFO+1"},
+			{LevelNotice, "NOTICE"},
+			{LevelNotice + 1, "NOTICE+1"},
+			{slog.LevelWarn, "WARN"},
+			{slog.LevelError, "ERROR"},
+			{LevelFatal, "FATAL"},
+			{LevelFatal + 4, "FATAL+4"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.expected, func(t *testing.T) {
+				assert.Equal(t, tt.expected, DefaultTerminalLevels.Name(tt.level))
+			})
+		}
+
+		assert.Equal(t, "DEBUG-4", TerminalLevels{}.Name(LevelTrace))
+	})
+
+	t.Run("ShortName", func(t *testing.T) {
+		tests := []struct {
+			level    slog.Level
+			expected string
+		}{
+			{LevelTrace, "TRC"},
+			{slog.LevelDebug, "DBG"},
+			{slog.LevelInfo, "INF"},
+			{LevelNotice + 1, "NTC+1"},
+			{slog.LevelWarn, "WRN"},
+			{slog.LevelError, "ERR"},
+			{LevelFatal, "FTL"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.expected, func(t *testing.T) {
+				assert.Equal(t, tt.expected, DefaultTerminalLevels.ShortName(tt.level))
+			})
+		}
+
+		assert.Equal(t, "AUD", TerminalLevels{16: {Name: "audit"}}.ShortName(16))
+	})
+
+	t.Run("width", func(t *testing.T) {
+		assert.Equal(t, 6, DefaultTerminalLevels.width(LevelFormatPadded))
+		assert.Equal(t, 3, DefaultTerminalLevels.width(LevelFormatShort))
+		assert.Equal(t, 5, TerminalLevels{}.width(LevelFormatPadded))
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		tests := []struct {
+			text     string
+			expected slog.Level
+		}{
+			{"trace", LevelTrace},
+			{"TRACE-1", LevelTrace - 1},
+			{"debug", slog.LevelDebug},
+			{"Info", slog.LevelInfo},
+			{"notice", LevelNotice},
+			{"notice+1", LevelNotice + 1},
+			{"warn", slog.LevelWarn},
+			{"error+2", slog.LevelError + 2},
+			{"fatal", LevelFatal},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.text, func(t *testing.T) {
+				level, err := DefaultTerminalLevels.Parse(tt.text)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, level)
+			})
+		}
+
+		t.Run("hyphenated_name", func(t *testing.T) {
+			levels := TerminalLevels{10: {Name: "SEC-AUDIT"}}
+			for text, expected := range map[string]slog.Level{
+				"SEC-AUDIT":   10,
+				"sec-audit+1": 11,
+				"sec-audit-2": 8,
+				"info-2":      slog.LevelInfo - 2,
+			} {
+				level, err := levels.Parse(text)
+				require.NoError(t, err)
+				assert.Equal(t, expected, level, text)
+			}
+		})
+
+		for _, text := range []string{"verbose", "trace+x", ""} {
+			t.Run("invalid_"+text, func(t *testing.T) {
+				_, err := DefaultTerminalLevels.Parse(text)
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("Names", func(t *testing.T) {
+		assert.Equal(
+			t,
+			[]string{"TRACE", "DEBUG", "INFO", "NOTICE", "WARN", "ERROR", "FATAL"},
+			DefaultTerminalLevels.Names(),
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		levels := TerminalLevels{}
+		assert.Equal(t, "DEBUG-4", levels.Name(LevelTrace))
+		assert.Equal(t, "INFO+2", levels.Name(LevelNotice))
+		assert.Equal(t, "ERROR+4", levels.Name(LevelFatal))
+	})
+
+	t.Run("styles", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			level         slog.Level
+			expectedLevel ansi.Style
+		}{
+			{"trace", LevelTrace, DefaultTerminalLevels[LevelTrace].Level},
+			{"debug", slog.LevelDebug, DefaultTerminalHandlerColorScheme.LevelDebug},
+			{"notice", LevelNotice, DefaultTerminalLevels[LevelNotice].Level},
+			{"notice_offset", LevelNotice + 1, DefaultTerminalLevels[LevelNotice].Level},
+			{"warn", slog.LevelWarn, DefaultTerminalHandlerColorScheme.LevelWarn},
+			{"error", slog.LevelError, DefaultTerminalHandlerColorScheme.LevelError},
+			{"fatal_offset", LevelFatal + 1, DefaultTerminalLevels[LevelFatal].Level},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				levelStyle, _ := DefaultTerminalLevels.styles(DefaultTerminalHandlerColorScheme, tt.level)
+				assert.Equal(t, tt.expectedLevel, levelStyle)
+			})
+		}
+	})
+}
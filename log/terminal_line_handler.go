@@ -5,16 +5,15 @@ import (
 	"context"
 	"io"
 	"log/slog"
-	"os"
 	"slices"
+	"strings"
 	"sync"
 
-	"golang.org/x/term"
+	"github.com/fornellas/slogxt/ansi"
 )
 
 type terminalLineHandlerAttrWriter struct {
-	colorScheme *TerminalHandlerColorScheme
-	replaceAttr func(groups []string, a slog.Attr) slog.Attr
+	opts *TerminalHandlerOptions
 }
 
 func (aw *terminalLineHandlerAttrWriter) writeAttrGroupValue(
@@ -39,11 +38,10 @@ func (aw *terminalLineHandlerAttrWriter) writeAttrGroupValue(
 		}
 	} else {
 		ga := &groupAttrs{
-			ColorScheme: aw.colorScheme,
-			ReplaceAttr: aw.replaceAttr,
-			Group:       attr.Key,
-			Groups:      append(groups, attr.Key),
-			Attrs:       attrs,
+			Options: aw.opts,
+			Group:   attr.Key,
+			Groups:  append(groups, attr.Key),
+			Attrs:   attrs,
 		}
 
 		if n, err = ga.write(w); err != nil {
@@ -55,14 +53,217 @@ func (aw *terminalLineHandlerAttrWriter) writeAttrGroupValue(
 	return nt, nil
 }
 
+// writeErrorNode writes the error node in a compact chain form, such as
+// "message: wrapped message: [joined message; another joined message]".
+func (aw *terminalLineHandlerAttrWriter) writeErrorNode(w io.Writer, node errorNode) (int, error) {
+	var n, nt int
+	var err error
+
+	if len(node.message) > 0 {
+		message, elided, units := elideValue(node.message, aw.opts.MaxValueLength, 0)
+		valueStyle := aw.opts.ColorScheme.attrValueOr(aw.opts.ColorScheme.AttrValueError)
+		if n, err = valueStyle.Fprintf(w, "%s", escape(message)); err != nil {
+			return nt + n, err
+		}
+		nt += n
+
+		if elided > 0 {
+			if n, err = w.Write([]byte(" ")); err != nil {
+				return nt + n, err
+			}
+			nt += n
+
+			if n, err = writeElision(w, aw.opts, elided, units); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if len(node.frames) > 0 {
+			var buff bytes.Buffer
+			buff.WriteString(" (at ")
+			if err = writeFrame(&buff, aw.opts, node.frames[0]); err != nil {
+				return nt, err
+			}
+			buff.WriteString(")")
+			if n, err = w.Write(buff.Bytes()); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if len(node.children) > 0 {
+			if n, err = w.Write([]byte(": ")); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+	}
+
+	if len(node.children) == 1 {
+		if n, err = aw.writeErrorNode(w, node.children[0]); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	} else if len(node.children) > 1 {
+		if n, err = w.Write([]byte("[")); err != nil {
+			return nt + n, err
+		}
+		nt += n
+
+		for i, child := range node.children {
+			if i > 0 {
+				if n, err = w.Write([]byte("; ")); err != nil {
+					return nt + n, err
+				}
+				nt += n
+			}
+			if n, err = aw.writeErrorNode(w, child); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if n, err = w.Write([]byte("]")); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	}
+
+	return nt, nil
+}
+
+func (aw *terminalLineHandlerAttrWriter) writeValue(w io.Writer, value slog.Value) (int, error) {
+	if cv, ok := value.Any().(compositeValue); ok {
+		return aw.writeCompositeValue(w, cv)
+	}
+
+	if errValue, ok := errorValue(value); ok {
+		return aw.writeErrorNode(w, newErrorNode(errValue, aw.opts.ErrorStackTrace))
+	}
+
+	var n, nt int
+	var err error
+
+	var valueStr, units string
+	var elided int
+	valueStyle := aw.opts.ColorScheme.attrValue(value)
+	if tv, ok := value.Any().(TerminalValuer); ok {
+		terminalValue := tv.TerminalValue()
+		valueStr, elided, units = elideValue(
+			ansi.Sanitize(terminalValue.String()), aw.opts.MaxValueLength, aw.opts.MaxValueLines,
+		)
+	} else if link, ok := hyperlinkValue(value, aw.opts); ok {
+		valueStr, elided, units = elideValue(link, aw.opts.MaxValueLength, 0)
+	} else if pretty, ok := prettyValue(value, aw.opts, false); ok {
+		valueStr, elided, units = elideValue(pretty, aw.opts.MaxValueLength, 0)
+		valueStyle = ansi.Style{}
+	} else {
+		valueStr, elided, units = elideValue(
+			value.String(), aw.opts.MaxValueLength, aw.opts.MaxValueLines,
+		)
+		valueStr = escape(valueStr)
+	}
+
+	if n, err = valueStyle.Fprintf(w, "%s", valueStr); err != nil {
+		return nt + n, err
+	}
+	nt += n
+
+	if elided > 0 {
+		if n, err = w.Write([]byte(" ")); err != nil {
+			return nt + n, err
+		}
+		nt += n
+
+		if n, err = writeElision(w, aw.opts, elided, units); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	}
+
+	return nt, nil
+}
+
+// writeCompositeValue writes an expanded map, slice, array or struct value inline, such as
+// "{key: value, list: [1, 2]}".
+func (aw *terminalLineHandlerAttrWriter) writeCompositeValue(w io.Writer, cv compositeValue) (int, error) {
+	var n, nt int
+	var err error
+
+	opening, closing := "{", "}"
+	if cv.list {
+		opening, closing = "[", "]"
+	}
+
+	attrs := cv.attrs
+	var elided int
+	if aw.opts.MaxGroupAttrs > 0 && len(attrs) > aw.opts.MaxGroupAttrs {
+		elided = len(attrs) - aw.opts.MaxGroupAttrs
+		attrs = attrs[:aw.opts.MaxGroupAttrs]
+	}
+
+	if n, err = w.Write([]byte(opening)); err != nil {
+		return nt + n, err
+	}
+	nt += n
+
+	for i, attr := range attrs {
+		if i > 0 {
+			if n, err = w.Write([]byte(", ")); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if !cv.list {
+			if n, err = aw.opts.ColorScheme.AttrKey.Fprintf(w, "%s", escape(attr.Key)); err != nil {
+				return nt + n, err
+			}
+			nt += n
+
+			if n, err = w.Write([]byte(": ")); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if n, err = aw.writeValue(w, attr.Value); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	}
+
+	if elided > 0 {
+		if len(attrs) > 0 {
+			if n, err = w.Write([]byte(", ")); err != nil {
+				return nt + n, err
+			}
+			nt += n
+		}
+
+		if n, err = writeElision(w, aw.opts, elided, "attributes"); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	}
+
+	if n, err = w.Write([]byte(closing)); err != nil {
+		return nt + n, err
+	}
+	nt += n
+
+	return nt, nil
+}
+
 func (aw *terminalLineHandlerAttrWriter) writeAttr(
 	w io.Writer,
 	groups []string,
 	attr slog.Attr,
 ) (int, error) {
 	attr.Value = attr.Value.Resolve()
-	if aw.replaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
-		attr = aw.replaceAttr(groups, attr)
+	if aw.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = aw.opts.ReplaceAttr(groups, attr)
 		attr.Value = attr.Value.Resolve()
 	}
 
@@ -83,7 +284,7 @@ func (aw *terminalLineHandlerAttrWriter) writeAttr(
 		}
 		nt += n
 	} else {
-		if n, err = aw.colorScheme.AttrKey.Fprintf(w, "%s", escape(attr.Key)); err != nil {
+		if n, err = aw.opts.ColorScheme.AttrKey.Fprintf(w, "%s", escape(attr.Key)); err != nil {
 			return nt + n, err
 		}
 		nt += n
@@ -92,15 +293,11 @@ func (aw *terminalLineHandlerAttrWriter) writeAttr(
 			return nt + n, err
 		}
 
-		var valueStr string
-		if tv, ok := attr.Value.Any().(TerminalValuer); ok {
-			terminalValue := tv.TerminalValue()
-			valueStr = terminalValue.String()
-		} else {
-			valueStr = escape(attr.Value.String())
+		if cv, ok := expandComposite(attr.Value, aw.opts.CompositeValueDepth); ok {
+			attr.Value = slog.AnyValue(cv)
 		}
 
-		if n, err = aw.colorScheme.AttrValue.Fprintf(w, "%s", valueStr); err != nil {
+		if n, err = aw.writeValue(w, attr.Value); err != nil {
 			return nt + n, err
 		}
 		nt += n
@@ -121,6 +318,12 @@ func (aw *terminalLineHandlerAttrWriter) writeAttrs(
 	var n, nt int
 	var err error
 
+	var elided int
+	if aw.opts.MaxGroupAttrs > 0 && len(attrs) > aw.opts.MaxGroupAttrs {
+		elided = len(attrs) - aw.opts.MaxGroupAttrs
+		attrs = attrs[:aw.opts.MaxGroupAttrs]
+	}
+
 	if n, err = w.Write([]byte("[")); err != nil {
 		return nt + n, err
 	}
@@ -140,6 +343,18 @@ func (aw *terminalLineHandlerAttrWriter) writeAttrs(
 		nt += n
 	}
 
+	if elided > 0 {
+		if n, err = w.Write([]byte(", ")); err != nil {
+			return nt + n, err
+		}
+		nt += n
+
+		if n, err = writeElision(w, aw.opts, elided, "attributes"); err != nil {
+			return nt + n, err
+		}
+		nt += n
+	}
+
 	if n, err = w.Write([]byte("]")); err != nil {
 		return nt + n, err
 	}
@@ -149,11 +364,10 @@ func (aw *terminalLineHandlerAttrWriter) writeAttrs(
 }
 
 type groupAttrs struct {
-	ColorScheme *TerminalHandlerColorScheme
-	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
-	Group       string
-	Groups      []string
-	Attrs       []slog.Attr
+	Options *TerminalHandlerOptions
+	Group   string
+	Groups  []string
+	Attrs   []slog.Attr
 }
 
 func (ga *groupAttrs) write(w io.Writer) (int, error) {
@@ -161,7 +375,7 @@ func (ga *groupAttrs) write(w io.Writer) (int, error) {
 	var err error
 
 	if len(ga.Group) > 0 {
-		if n, err = writeGroup(w, ga.ColorScheme, ga.Group); err != nil {
+		if n, err = writeGroup(w, ga.Options, ga.Group); err != nil {
 			return n, err
 		}
 		nt += n
@@ -176,8 +390,7 @@ func (ga *groupAttrs) write(w io.Writer) (int, error) {
 		}
 
 		attrWriter := &terminalLineHandlerAttrWriter{
-			colorScheme: ga.ColorScheme,
-			replaceAttr: ga.ReplaceAttr,
+			opts: ga.Options,
 		}
 		if n, err = attrWriter.writeAttrs(
 			w,
@@ -192,6 +405,39 @@ func (ga *groupAttrs) write(w io.Writer) (int, error) {
 	return nt, nil
 }
 
+// Number of lines a column keeps its width for, after the line that widened it, before it shrinks
+// to fit the following lines.
+const terminalLineColumnLines = 32
+
+// terminalLineColumn is a column at which TerminalLineHandler aligns text.
+type terminalLineColumn struct {
+	width int
+	lines int
+}
+
+// terminalLineColumns holds the columns at which TerminalLineHandler aligns messages and
+// attribute lists, shared by all handlers derived from the same NewTerminalLineHandler call.
+type terminalLineColumns struct {
+	mutex   sync.Mutex
+	message terminalLineColumn
+	attrs   terminalLineColumn
+}
+
+// pad returns the padding to add at column width to align it with column. The column is widened
+// when needed, and shrinks back after terminalLineColumnLines lines, so a single wide line does not
+// widen all following lines.
+func (c *terminalLineColumns) pad(column *terminalLineColumn, width int) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if width >= column.width || column.lines <= 0 {
+		column.width = width
+		column.lines = terminalLineColumnLines
+	} else {
+		column.lines--
+	}
+	return strings.Repeat(" ", column.width-width)
+}
+
 // TerminalLineHandler is a slog.Handler implementation that formats log records
 // as lines of text suitable for terminal output, with optional colorization.
 // It provides a structured, human-readable output format with customizable styling
@@ -211,37 +457,24 @@ type TerminalLineHandler struct {
 	opts        *TerminalHandlerOptions
 	writer      io.Writer
 	writerMutex *sync.Mutex
+	clock       *terminalClock
+	columns     *terminalLineColumns
 	groupAttrs  []groupAttrs
 }
 
 // NewTerminalLineHandler creates a new TerminalTextHandler
 func NewTerminalLineHandler(w io.Writer, opts *TerminalHandlerOptions) *TerminalLineHandler {
-	var optsValue TerminalHandlerOptions
-	if opts != nil {
-		optsValue = *opts
-	}
-
-	if optsValue.ColorScheme == nil {
-		optsValue.ColorScheme = DefaultTerminalHandlerColorScheme
-	}
-
-	isTTY := false
-	if f, ok := w.(*os.File); ok {
-		isTTY = term.IsTerminal(int(f.Fd()))
-	}
-
-	if !(!optsValue.NoColor && (optsValue.ForceColor || isTTY)) {
-		optsValue.ColorScheme = &TerminalHandlerColorScheme{}
-	}
+	optsValue := newTerminalHandlerOptions(w, opts)
 
 	return &TerminalLineHandler{
-		opts:        &optsValue,
+		opts:        optsValue,
 		writer:      w,
 		writerMutex: &sync.Mutex{},
+		clock:       &terminalClock{},
+		columns:     &terminalLineColumns{},
 		groupAttrs: []groupAttrs{
 			groupAttrs{
-				ColorScheme: optsValue.ColorScheme,
-				ReplaceAttr: optsValue.ReplaceAttr,
+				Options: optsValue,
 			},
 		},
 	}
@@ -270,7 +503,9 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 	var err error
 
 	// Record: Time
-	if n, err = writeTime(&buff, h.opts.TimeLayout, record.Time, h.opts.ColorScheme); err != nil {
+	if n, err = writeTime(
+		&buff, h.clock.format(h.opts.TimeMode, h.opts.TimeLayout, record.Time), h.opts.ColorScheme,
+	); err != nil {
 		return err
 	} else if n > 0 {
 		if _, err = buff.WriteString(" "); err != nil {
@@ -279,7 +514,9 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 	}
 
 	// Record: Level
-	if n, err = writeLevel(&buff, h.opts.ColorScheme, record.Level); err != nil {
+	if n, err = writeLevel(
+		&buff, h.opts.ColorScheme, h.opts.Levels, h.opts.LevelFormat, h.opts.levelNames, record.Level,
+	); err != nil {
 		return err
 	} else if n > 0 {
 		if _, err = buff.WriteString(" "); err != nil {
@@ -309,7 +546,12 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 	}
 
 	// Record: Message
-	if _, err = writeMessage(&buff, h.opts.ColorScheme, record.Level, record.Message); err != nil {
+	if h.opts.AlignColumns {
+		if _, err = buff.WriteString(h.columns.pad(&h.columns.message, textWidth(buff.String()))); err != nil {
+			return err
+		}
+	}
+	if _, err = writeMessage(&buff, h.opts.ColorScheme, h.opts.Levels, record.Level, record.Message); err != nil {
 		return err
 	}
 
@@ -318,6 +560,11 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 		if _, err = buff.WriteString(" "); err != nil {
 			return err
 		}
+		if h.opts.AlignColumns {
+			if _, err = buff.WriteString(h.columns.pad(&h.columns.attrs, textWidth(buff.String()))); err != nil {
+				return err
+			}
+		}
 
 		attrs := []slog.Attr{}
 		record.Attrs(func(attr slog.Attr) bool {
@@ -326,8 +573,7 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 		})
 
 		attrWriter := &terminalLineHandlerAttrWriter{
-			colorScheme: h.opts.ColorScheme,
-			replaceAttr: h.opts.ReplaceAttr,
+			opts: h.opts,
 		}
 		if _, err := attrWriter.writeAttrs(
 			&buff,
@@ -343,7 +589,7 @@ func (h *TerminalLineHandler) Handle(ctx context.Context, record slog.Record) er
 		if _, err := buff.WriteString(" "); err != nil {
 			return err
 		}
-		writePC(&buff, h.opts.ColorScheme, record.PC)
+		writePC(&buff, h.opts, record.PC)
 	}
 
 	// New line
@@ -384,10 +630,9 @@ func (h *TerminalLineHandler) WithGroup(name string) slog.Handler {
 		lastGroupAttrs.Group = name
 	} else {
 		h2.groupAttrs = append(h2.groupAttrs, groupAttrs{
-			ColorScheme: h2.opts.ColorScheme,
-			ReplaceAttr: h2.opts.ReplaceAttr,
-			Group:       name,
-			Groups:      append(h2.groups(), name),
+			Options: h2.opts,
+			Group:   name,
+			Groups:  append(h2.groups(), name),
 		})
 	}
 
@@ -3,13 +3,18 @@ package log
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/fornellas/slogxt/ansi"
 )
 
 func TestTerminalLineHandler(t *testing.T) {
@@ -331,6 +336,425 @@ func TestTerminalLineHandler(t *testing.T) {
 					)
 				},
 			},
+			{
+				name: "max_value_length",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxValueLength: 5})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "short", "12345", "long", "1234567890")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO message [short: 12345, long: 12345 … 5 more bytes]\n", output)
+				},
+			},
+			{
+				name: "max_value_lines",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxValueLines: 2})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "lines", "line1\nline2\nline3\nline4")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO message [lines: line1\\nline2 … 2 more lines]\n", output)
+				},
+			},
+			{
+				name: "max_value_lines_and_length",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor: true, MaxValueLines: 2, MaxValueLength: 20,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "lines", "line1\nline2\nline3", "long", "1234567890123456789012")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t, "INFO message [lines: line1\\nline2 … 1 more line, long: 12345678901234567890 … 2 more bytes]\n", output,
+					)
+				},
+			},
+			{
+				name: "max_group_attrs",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxGroupAttrs: 2})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message",
+						slog.Group("group", "a", 1, "b", 2, "c", 3),
+						"d", 4,
+						"e", 5,
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message [🏷️ group [a: 1, b: 2, … 1 more attribute], d: 4, … 1 more attribute]\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "error_chain",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Error("failed",
+						"err", fmt.Errorf("load: %w", errors.Join(
+							fmt.Errorf("open config: %w", errors.New("not found")),
+							errors.New("invalid flag"),
+						)),
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "ERROR failed [err: load: [open config: not found; invalid flag]]\n", output)
+				},
+			},
+			{
+				name: "error_nil_pointer",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true, ErrorStackTrace: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					var err *stackError
+					logger.Error("failed", "err", err)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "ERROR failed [err: <nil>]\n", output)
+				},
+			},
+			{
+				name: "error_stack_trace",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:         true,
+						ErrorStackTrace: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Error("failed", "err", fmt.Errorf("run: %w", framesError{message: "boom"}))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "ERROR failed [err: run: boom (at /src/main.go:10 (main.run))]\n", output)
+				},
+			},
+			{
+				name: "composite_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:             true,
+						CompositeValueDepth: 2,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("config",
+						"server", map[string]any{
+							"host":  "localhost",
+							"ports": []int{80, 443},
+							"tls":   map[string]any{"cert": "a.pem"},
+							"empty": []int{},
+						},
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO config [server: {empty: [], host: localhost, ports: [80, 443], tls: {cert: a.pem}}]\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "pretty_json",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{NoColor: true, PrettyJSON: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("response", "body", "{\"id\":1,\n\"tags\":[\"a\"]}", "invalid", `{"id":`)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, `INFO response [body: {"id": 1, "tags": ["a"]}, invalid: {"id":]`+"\n", output)
+				},
+			},
+			{
+				name: "custom_levels",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						HandlerOptions: slog.HandlerOptions{Level: LevelTrace},
+						NoColor:        true,
+						Levels:         DefaultTerminalLevels,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					ctx := context.Background()
+					logger.Log(ctx, LevelTrace, "trace message")
+					logger.Log(ctx, LevelNotice, "notice message")
+					logger.Log(ctx, LevelNotice+1, "notice offset message")
+					logger.Log(ctx, LevelFatal, "fatal message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"TRACE trace message\n"+
+							"NOTICE notice message\n"+
+							"NOTICE+1 notice offset message\n"+
+							"FATAL fatal message\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "custom_levels_emoji",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						Levels: TerminalLevels{
+							slog.LevelWarn: {Name: "WARNING", Emoji: "⚠️"},
+						},
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Warn("warn message")
+					logger.Error("error message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "⚠️ WARNING warn message\nERROR error message\n", output)
+				},
+			},
+			{
+				name: "default_levels",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						HandlerOptions: slog.HandlerOptions{Level: LevelTrace},
+						NoColor:        true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					ctx := context.Background()
+					logger.Log(ctx, LevelTrace, "trace message")
+					logger.Log(ctx, LevelNotice, "notice message")
+					logger.Log(ctx, LevelFatal, "fatal message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "DEBUG-4 trace message\nINFO+2 notice message\nERROR+4 fatal message\n", output)
+				},
+			},
+			{
+				name: "level_format_padded",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:     true,
+						LevelFormat: LevelFormatPadded,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("info message")
+					logger.Error("error message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO  info message\nERROR error message\n", output)
+				},
+			},
+			{
+				name: "level_format_short",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:     true,
+						LevelFormat: LevelFormatShort,
+						Levels:      DefaultTerminalLevels,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("info message")
+					logger.Warn("warn message")
+					logger.Log(context.Background(), LevelFatal, "fatal message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INF info message\nWRN warn message\nFTL fatal message\n", output)
+				},
+			},
+			{
+				name: "align_columns",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:      true,
+						LevelFormat:  LevelFormatShort,
+						AlignColumns: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.WithGroup("server").With("port", 80).Info("started", "ok", true)
+					logger.Info("short", "key", "value")
+					logger.Info("a longer message", "key", "value")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INF 🏷️ server [port: 80]: started [ok: true]\n"+
+							"INF                       short   [key: value]\n"+
+							"INF                       a longer message [key: value]\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "align_columns_shrink",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:      true,
+						LevelFormat:  LevelFormatShort,
+						AlignColumns: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.WithGroup("server").With("port", 80).Info("started", "ok", true)
+					for range terminalLineColumnLines + 1 {
+						logger.Info("short", "key", "value")
+					}
+				},
+				check: func(t *testing.T, output string) {
+					lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+					require.Len(t, lines, terminalLineColumnLines+2)
+					assert.Equal(t, "INF                       short   [key: value]", lines[terminalLineColumnLines])
+					assert.Equal(t, "INF short [key: value]", lines[terminalLineColumnLines+1])
+				},
+			},
+			{
+				name: "with_source_relative",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						HandlerOptions: slog.HandlerOptions{
+							AddSource: true,
+						},
+						SourcePath:          SourcePathRelative,
+						SourceShortFunction: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message with source")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Regexp(t, `^INFO message with source log/terminal_line_handler_test\.go:\d+ \(log\.TestTerminalLineHandler\.func\d+\.\d+\)\n$`, output)
+				},
+			},
+			{
+				name: "hyperlinks_url_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						ForceColor:  true,
+						ColorScheme: &TerminalHandlerColorScheme{},
+						Hyperlinks:  true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("link", "url", &url.URL{Scheme: "https", Host: "example.com", Path: "/a b"})
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO link [url: \033]8;;https://example.com/a%20b\033\\https://example.com/a%20b\033]8;;\033\\]\n", output)
+				},
+			},
+			{
+				name: "hyperlinks_no_color",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:    true,
+						Hyperlinks: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("link", "url", &url.URL{Scheme: "https", Host: "example.com", Path: "/a b"})
+				},
+				check: func(t *testing.T, output string) {
+					assert.NotContains(t, output, "\033")
+				},
+			},
+			{
+				name: "hyperlinks_source",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						HandlerOptions: slog.HandlerOptions{
+							AddSource: true,
+						},
+						ForceColor:        true,
+						ColorScheme:       &TerminalHandlerColorScheme{},
+						Hyperlinks:        true,
+						HyperlinkTemplate: "editor://open?file={path}&line={line}",
+						SourcePath:        SourcePathRelative,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message with source")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Regexp(t, `^INFO message with source \033\]8;;editor://open\?file=/.+/log/terminal_line_handler_test\.go&line=\d+\033\\log/terminal_line_handler_test\.go:\d+\033\]8;;\033\\ \(.+\)\n$`, output)
+				},
+			},
+			{
+				name: "group_icon",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						GroupIcon: func(name string) string {
+							if name == "database" {
+								return "[db]"
+							}
+							return ""
+						},
+					})
+					return slog.New(h).WithGroup("database").With("name", "users").WithGroup("server")
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO [db] database [name: users] > server: message\n", output)
+				},
+			},
+			{
+				name: "ascii",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+						NoColor:       true,
+						ASCII:         true,
+						TreeGuides:    TreeGuidesUnicode,
+						MaxGroupAttrs: 1,
+						Levels: TerminalLevels{
+							slog.LevelInfo: {Name: "INFO", Emoji: "ℹ️"},
+						},
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", slog.Group("server", "a", 1, "b", 2))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO message [server [a: 1, ... 1 more attribute]]\n", output)
+				},
+			},
 		}
 
 		for _, tt := range tests {
@@ -387,6 +811,21 @@ func TestTerminalLineHandler(t *testing.T) {
 				opts:       &TerminalHandlerOptions{ForceColor: true, NoColor: true},
 				wantColors: false,
 			},
+			{
+				name:       "color_mode_always",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeAlways},
+				wantColors: true,
+			},
+			{
+				name:       "no_color_overrides_color_mode",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeAlways, NoColor: true},
+				wantColors: false,
+			},
+			{
+				name:       "force_color_overrides_color_mode",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeNever, ForceColor: true},
+				wantColors: true,
+			},
 		}
 
 		for _, tt := range tests {
@@ -422,8 +861,8 @@ func TestTerminalLineHandler(t *testing.T) {
 				},
 			},
 			{
-				name:     "terminal_valuer_with_all_sequences",
-				expected: "[value: \033[2J\033[31mred\033[H\033[0m]",
+				name:     "terminal_valuer_non_sgr_sequences_removed",
+				expected: "[value: \033[31mred\033[0m]",
 				logFn: func(logger *slog.Logger) {
 					cv := TestColoredValue{
 						plainText:    "plain text",
@@ -449,4 +888,59 @@ func TestTerminalLineHandler(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("ValueColor", func(t *testing.T) {
+		colorScheme := &TerminalHandlerColorScheme{
+			AttrValue:       ansi.NewStyle(ansi.FgWhite),
+			AttrValueNumber: ansi.NewStyle(ansi.FgMagenta),
+			AttrValueBool:   ansi.NewStyle(ansi.FgYellow),
+			AttrValueNil:    ansi.NewStyle(ansi.Dim),
+		}
+		tests := []struct {
+			name     string
+			value    any
+			expected string
+		}{
+			{name: "number", value: 42, expected: "\033[35m42\033[39m"},
+			{name: "bool", value: true, expected: "\033[33mtrue\033[39m"},
+			{name: "nil", value: nil, expected: "\033[2m<nil>\033[22m"},
+			{name: "empty_string", value: "", expected: "\033[2m\033[22m"},
+			{name: "fallback", value: "text", expected: "\033[37mtext\033[39m"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+					ForceColor:  true,
+					ColorScheme: colorScheme,
+				})
+
+				logger := slog.New(h)
+				logger.Info("test message", "key", tt.value)
+
+				assert.Contains(t, buf.String(), tt.expected)
+			})
+		}
+	})
+
+	t.Run("TimeMode", func(t *testing.T) {
+		start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		setProcessStart(t, start)
+
+		buf := &bytes.Buffer{}
+		h := NewTerminalLineHandler(buf, &TerminalHandlerOptions{
+			NoColor:  true,
+			TimeMode: TimeModeDelta,
+		})
+		logger := slog.New(h.WithAttrs([]slog.Attr{}))
+		require.NoError(t, logger.Handler().Handle(
+			context.Background(), slog.NewRecord(start.Add(time.Second), slog.LevelInfo, "first", 0),
+		))
+		require.NoError(t, h.Handle(
+			context.Background(), slog.NewRecord(start.Add(1250*time.Millisecond), slog.LevelInfo, "second", 0),
+		))
+
+		assert.Equal(t, "+1.000s INFO first\n+0.250s INFO second\n", buf.String())
+	})
 }
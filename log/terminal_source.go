@@ -0,0 +1,190 @@
+package log
+
+import (
+	"fmt"
+	"go/build"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// SourcePath defines how terminal handlers display source file paths.
+type SourcePath int
+
+const (
+	// Display the full path, as recorded by the compiler.
+	SourcePathFull SourcePath = iota
+	// Display paths relative to the root of the Go module containing the file, or, for
+	// dependencies and the standard library, relative to the module cache, GOPATH or GOROOT, eg:
+	// "log/handler.go", "github.com/spf13/cobra@v1.9.1/command.go" or "fmt/print.go". Module
+	// roots are derived from the function package path and the modules in the binary build
+	// information. Files that are not in any of them are displayed with the full path.
+	SourcePathRelative
+	// Display only the file name, eg: "handler.go".
+	SourcePathBase
+)
+
+var sourcePathNames = map[SourcePath]string{
+	SourcePathFull:     "full",
+	SourcePathRelative: "relative",
+	SourcePathBase:     "base",
+}
+
+// SourcePathNames returns the names of all source path modes.
+func SourcePathNames() []string {
+	return []string{
+		sourcePathNames[SourcePathFull],
+		sourcePathNames[SourcePathRelative],
+		sourcePathNames[SourcePathBase],
+	}
+}
+
+// String returns the name of the source path mode.
+func (p SourcePath) String() string {
+	if name, ok := sourcePathNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("SourcePath(%d)", int(p))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p SourcePath) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [SourcePathNames], case insensitively.
+func (p *SourcePath) UnmarshalText(data []byte) error {
+	for mode, name := range sourcePathNames {
+		if strings.EqualFold(string(data), name) {
+			*p = mode
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid source path %#v, valid options are %s", string(data), strings.Join(SourcePathNames(), ", "),
+	)
+}
+
+// sourceFile returns file, where function is defined, as displayed with opts.
+func sourceFile(opts *TerminalHandlerOptions, file, function string) string {
+	for _, prefix := range opts.SourceTrimPrefixes {
+		if prefix != "" && strings.HasPrefix(file, prefix) {
+			return strings.TrimLeft(strings.TrimPrefix(file, prefix), "/")
+		}
+	}
+	switch opts.SourcePath {
+	case SourcePathRelative:
+		return relativeSourceFile(file, function)
+	case SourcePathBase:
+		return path.Base(file)
+	default:
+		return file
+	}
+}
+
+// sourceRoots returns the directories under which file paths are displayed relative to with
+// SourcePathRelative, other than module roots.
+var sourceRoots = sync.OnceValue(func() []string {
+	roots := []string{}
+	if build.Default.GOROOT != "" {
+		roots = append(roots, filepath.ToSlash(filepath.Join(build.Default.GOROOT, "src")))
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		roots = append(roots,
+			filepath.ToSlash(filepath.Join(gopath, "pkg", "mod")),
+			filepath.ToSlash(filepath.Join(gopath, "src")),
+		)
+	}
+	return roots
+})
+
+// buildModules describes the modules of the binary, read once from its build information.
+type buildModules struct {
+	// Import path of the main package.
+	mainPackage string
+	// Paths of the main module and of its dependencies.
+	paths []string
+}
+
+var readBuildModules = sync.OnceValue(func() buildModules {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return buildModules{}
+	}
+	modules := buildModules{mainPackage: info.Path}
+	if info.Main.Path != "" {
+		modules.paths = append(modules.paths, info.Main.Path)
+	}
+	for _, dep := range info.Deps {
+		modules.paths = append(modules.paths, dep.Path)
+	}
+	return modules
+})
+
+func relativeSourceFile(file, function string) string {
+	for _, root := range sourceRoots() {
+		if rel, ok := strings.CutPrefix(file, root+"/"); ok {
+			return rel
+		}
+	}
+	if root := moduleRoot(readBuildModules(), file, function); root != "" {
+		if rel, ok := strings.CutPrefix(file, root+"/"); ok {
+			return rel
+		}
+	}
+	return file
+}
+
+// functionPackage returns the import path of the package of a fully qualified function name,
+// eg: "github.com/fornellas/slogxt/log" for
+// "github.com/fornellas/slogxt/log.(*TerminalTreeHandler).Handle".
+func functionPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if i := strings.Index(function[slash+1:], "."); i >= 0 {
+		return function[:slash+1+i]
+	}
+	return ""
+}
+
+// moduleRoot returns the root directory of the module of modules that contains file, where
+// function is defined, or an empty string if unknown. The root is the directory of file, without
+// the path of the function package within its module.
+func moduleRoot(modules buildModules, file, function string) string {
+	pkg := functionPackage(function)
+	if pkg == "main" {
+		pkg = modules.mainPackage
+	}
+	dir := path.Dir(file)
+	var root, rootModule string
+	for _, modulePath := range modules.paths {
+		subdir, ok := strings.CutPrefix(pkg, modulePath)
+		if !ok || (subdir != "" && subdir[0] != '/') || len(modulePath) <= len(rootModule) {
+			continue
+		}
+		if moduleDir, ok := strings.CutSuffix(dir, subdir); ok && moduleDir != "" {
+			root, rootModule = moduleDir, modulePath
+		}
+	}
+	return root
+}
+
+// sourceFunction returns function as displayed with opts.
+func sourceFunction(opts *TerminalHandlerOptions, function string) string {
+	if opts.SourceShortFunction {
+		return shortFunction(function)
+	}
+	return function
+}
+
+// shortFunction removes the package path from a fully qualified function name, eg:
+// "github.com/fornellas/slogxt/log.(*TerminalTreeHandler).Handle" becomes
+// "log.(*TerminalTreeHandler).Handle".
+func shortFunction(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		return function[i+1:]
+	}
+	return function
+}
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"go/build"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourcePath(t *testing.T) {
+	for _, mode := range []SourcePath{SourcePathFull, SourcePathRelative, SourcePathBase} {
+		text, err := mode.MarshalText()
+		require.NoError(t, err)
+		var parsedMode SourcePath
+		require.NoError(t, parsedMode.UnmarshalText(text))
+		assert.Equal(t, mode, parsedMode)
+	}
+
+	var mode SourcePath
+	require.NoError(t, mode.UnmarshalText([]byte("BASE")))
+	assert.Equal(t, SourcePathBase, mode)
+	require.Error(t, mode.UnmarshalText([]byte("short")))
+}
+
+func TestSourceFile(t *testing.T) {
+	goroot := filepath.ToSlash(build.Default.GOROOT)
+	gopath := filepath.ToSlash(filepath.SplitList(build.Default.GOPATH)[0])
+
+	tests := []struct {
+		name     string
+		opts     TerminalHandlerOptions
+		file     string
+		function string
+		expected string
+	}{
+		{"full", TerminalHandlerOptions{}, "/src/slogxt/log/file.go", "", "/src/slogxt/log/file.go"},
+		{"base", TerminalHandlerOptions{SourcePath: SourcePathBase}, "/src/slogxt/log/file.go", "", "file.go"},
+		{
+			"relative_module",
+			TerminalHandlerOptions{SourcePath: SourcePathRelative},
+			"/src/slogxt/log/file.go",
+			"github.com/fornellas/slogxt/log.F",
+			"log/file.go",
+		},
+		{
+			"relative_goroot",
+			TerminalHandlerOptions{SourcePath: SourcePathRelative},
+			goroot + "/src/fmt/print.go",
+			"fmt.Println",
+			"fmt/print.go",
+		},
+		{
+			"relative_module_cache",
+			TerminalHandlerOptions{SourcePath: SourcePathRelative},
+			gopath + "/pkg/mod/github.com/spf13/cobra@v1.9.1/command.go",
+			"github.com/spf13/cobra.(*Command).Execute",
+			"github.com/spf13/cobra@v1.9.1/command.go",
+		},
+		{
+			"relative_unknown",
+			TerminalHandlerOptions{SourcePath: SourcePathRelative},
+			"/nonexistent/file.go",
+			"example.com/other.F",
+			"/nonexistent/file.go",
+		},
+		{
+			"trim_prefixes",
+			TerminalHandlerOptions{SourcePath: SourcePathBase, SourceTrimPrefixes: []string{"/other", "/src/app"}},
+			"/src/app/pkg/file.go",
+			"",
+			"pkg/file.go",
+		},
+		{
+			"trim_prefixes_no_match",
+			TerminalHandlerOptions{SourceTrimPrefixes: []string{"/other"}},
+			"/src/app/pkg/file.go",
+			"",
+			"/src/app/pkg/file.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sourceFile(&tt.opts, tt.file, tt.function))
+		})
+	}
+}
+
+func TestModuleRoot(t *testing.T) {
+	modules := buildModules{
+		mainPackage: "example.com/m/cmd/app",
+		paths:       []string{"example.com/m", "example.com/m/nested", "example.com/lib"},
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		function string
+		expected string
+	}{
+		{"package", "/src/m/pkg/sub/file.go", "example.com/m/pkg/sub.F", "/src/m"},
+		{"root_package", "/src/m/file.go", "example.com/m.(*T).F", "/src/m"},
+		{"main_package", "/src/m/cmd/app/main.go", "main.main", "/src/m"},
+		{"nested_module", "/src/nested/pkg/file.go", "example.com/m/nested/pkg.F", "/src/nested"},
+		{"trimpath", "example.com/lib/pkg/file.go", "example.com/lib/pkg.F", "example.com/lib"},
+		{"similar_module_path", "/src/m2/file.go", "example.com/m2.F", ""},
+		{"mismatched_directory", "/src/m/other/file.go", "example.com/m/pkg.F", ""},
+		{"unknown_module", "/src/other/file.go", "example.com/other.F", ""},
+		{"no_function", "/src/m/file.go", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, moduleRoot(modules, tt.file, tt.function))
+		})
+	}
+}
+
+func TestSourceFunction(t *testing.T) {
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/fornellas/slogxt/log.(*TerminalTreeHandler).Handle", "log.(*TerminalTreeHandler).Handle"},
+		{"github.com/fornellas/slogxt/log.TestSourceFunction.func1", "log.TestSourceFunction.func1"},
+		{"main.main", "main.main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.function, sourceFunction(&TerminalHandlerOptions{}, tt.function))
+			assert.Equal(t, tt.expected, sourceFunction(&TerminalHandlerOptions{SourceShortFunction: true}, tt.function))
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+// prettyValue returns the string held by value pretty printed and syntax colored, when it holds
+// JSON or YAML, as enabled by opts. If multiline is true, it is indented over multiple lines,
+// otherwise it is kept in a single line.
+func prettyValue(value slog.Value, opts *TerminalHandlerOptions, multiline bool) (string, bool) {
+	if value.Kind() != slog.KindString {
+		return "", false
+	}
+	s := value.String()
+	sw := &syntaxWriter{
+		colorScheme: opts.ColorScheme,
+		multiline:   multiline,
+	}
+	if opts.PrettyJSON {
+		if pretty, ok := sw.json(s); ok {
+			return pretty, true
+		}
+	}
+	if opts.PrettyYAML {
+		if pretty, ok := sw.yaml(s); ok {
+			return pretty, true
+		}
+	}
+	return "", false
+}
+
+// syntaxWriter pretty prints and syntax colors JSON and YAML.
+type syntaxWriter struct {
+	colorScheme *TerminalHandlerColorScheme
+	multiline   bool
+	buff        strings.Builder
+}
+
+// styled returns s with style, escaping non printable characters, so that keys and values can
+// not inject escape sequences.
+func (sw *syntaxWriter) styled(style ansi.Style, s string) string {
+	return style.Sprintf("%s", escape(s))
+}
+
+func (sw *syntaxWriter) write(style ansi.Style, s string) {
+	sw.buff.WriteString(sw.styled(style, s))
+}
+
+func (sw *syntaxWriter) newLine(indent int) {
+	if sw.multiline {
+		sw.buff.WriteString("\n")
+		sw.buff.WriteString(strings.Repeat("  ", indent))
+	}
+}
+
+func jsonString(s string) string {
+	var buff bytes.Buffer
+	encoder := json.NewEncoder(&buff)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		panic(err)
+	}
+	return strings.TrimSuffix(buff.String(), "\n")
+}
+
+func (sw *syntaxWriter) json(s string) (string, bool) {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+	if !json.Valid([]byte(trimmed)) {
+		return "", false
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	if err := sw.jsonValue(decoder, 0); err != nil {
+		return "", false
+	}
+	return sw.buff.String(), true
+}
+
+func (sw *syntaxWriter) jsonValue(decoder *json.Decoder, indent int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		isObject := t == '{'
+		sw.write(sw.colorScheme.SyntaxPunctuation, t.String())
+		empty := true
+		for decoder.More() {
+			if !empty {
+				sw.write(sw.colorScheme.SyntaxPunctuation, ",")
+				if !sw.multiline {
+					sw.buff.WriteString(" ")
+				}
+			}
+			empty = false
+			sw.newLine(indent + 1)
+			if isObject {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				sw.write(sw.colorScheme.SyntaxKey, jsonString(key.(string)))
+				sw.write(sw.colorScheme.SyntaxPunctuation, ":")
+				sw.buff.WriteString(" ")
+			}
+			if err := sw.jsonValue(decoder, indent+1); err != nil {
+				return err
+			}
+		}
+		closing, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if !empty {
+			sw.newLine(indent)
+		}
+		sw.write(sw.colorScheme.SyntaxPunctuation, closing.(json.Delim).String())
+	case string:
+		sw.write(sw.colorScheme.SyntaxString, jsonString(t))
+	case json.Number:
+		sw.write(sw.colorScheme.SyntaxNumber, t.String())
+	case bool:
+		sw.write(sw.colorScheme.SyntaxBool, strconv.FormatBool(t))
+	case nil:
+		sw.write(sw.colorScheme.SyntaxNull, "null")
+	}
+	return nil
+}
+
+func (sw *syntaxWriter) yaml(s string) (string, bool) {
+	if !strings.Contains(strings.TrimSpace(s), "\n") {
+		return "", false
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &document); err != nil {
+		return "", false
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) != 1 {
+		return "", false
+	}
+	node := document.Content[0]
+	if node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode {
+		return "", false
+	}
+	if sw.multiline {
+		sw.buff.WriteString(strings.Join(sw.yamlBlockLines(node), "\n"))
+	} else {
+		sw.yamlFlow(node)
+	}
+	return sw.buff.String(), true
+}
+
+func (sw *syntaxWriter) yamlScalar(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return sw.styled(sw.colorScheme.SyntaxPunctuation, "*"+node.Value)
+	case yaml.MappingNode:
+		return sw.styled(sw.colorScheme.SyntaxPunctuation, "{}")
+	case yaml.SequenceNode:
+		return sw.styled(sw.colorScheme.SyntaxPunctuation, "[]")
+	}
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		return sw.styled(sw.colorScheme.SyntaxNumber, node.Value)
+	case "!!bool":
+		return sw.styled(sw.colorScheme.SyntaxBool, node.Value)
+	case "!!null":
+		value := node.Value
+		if value == "" {
+			value = "null"
+		}
+		return sw.styled(sw.colorScheme.SyntaxNull, value)
+	default:
+		value := node.Value
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 ||
+			strings.ContainsAny(value, "\n\t") {
+			value = strconv.Quote(value)
+		}
+		return sw.styled(sw.colorScheme.SyntaxString, value)
+	}
+}
+
+func isYAMLCollection(node *yaml.Node) bool {
+	return (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) && len(node.Content) > 0
+}
+
+// yamlBlockLines returns the lines of node, in YAML block style.
+func (sw *syntaxWriter) yamlBlockLines(node *yaml.Node) []string {
+	lines := []string{}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := sw.styled(sw.colorScheme.SyntaxKey, node.Content[i].Value) +
+				sw.styled(sw.colorScheme.SyntaxPunctuation, ":")
+			value := node.Content[i+1]
+			if !isYAMLCollection(value) {
+				lines = append(lines, key+" "+sw.yamlScalar(value))
+				continue
+			}
+			lines = append(lines, key)
+			for _, line := range sw.yamlBlockLines(value) {
+				lines = append(lines, "  "+line)
+			}
+		}
+	case yaml.SequenceNode:
+		dash := sw.styled(sw.colorScheme.SyntaxPunctuation, "-")
+		for _, item := range node.Content {
+			if !isYAMLCollection(item) {
+				lines = append(lines, dash+" "+sw.yamlScalar(item))
+				continue
+			}
+			for i, line := range sw.yamlBlockLines(item) {
+				if i == 0 {
+					lines = append(lines, dash+" "+line)
+				} else {
+					lines = append(lines, "  "+line)
+				}
+			}
+		}
+	default:
+		lines = append(lines, sw.yamlScalar(node))
+	}
+	return lines
+}
+
+// yamlFlow writes node in YAML flow style.
+func (sw *syntaxWriter) yamlFlow(node *yaml.Node) {
+	if !isYAMLCollection(node) {
+		sw.buff.WriteString(sw.yamlScalar(node))
+		return
+	}
+	opening, closing, step := "[", "]", 1
+	if node.Kind == yaml.MappingNode {
+		opening, closing, step = "{", "}", 2
+	}
+	sw.write(sw.colorScheme.SyntaxPunctuation, opening)
+	for i := 0; i+step-1 < len(node.Content); i += step {
+		if i > 0 {
+			sw.write(sw.colorScheme.SyntaxPunctuation, ",")
+			sw.buff.WriteString(" ")
+		}
+		if node.Kind == yaml.MappingNode {
+			sw.write(sw.colorScheme.SyntaxKey, node.Content[i].Value)
+			sw.write(sw.colorScheme.SyntaxPunctuation, ":")
+			sw.buff.WriteString(" ")
+		}
+		sw.yamlFlow(node.Content[i+step-1])
+	}
+	sw.write(sw.colorScheme.SyntaxPunctuation, closing)
+}
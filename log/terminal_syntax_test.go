@@ -0,0 +1,143 @@
+package log
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyValue(t *testing.T) {
+	noColorOpts := &TerminalHandlerOptions{
+		ColorScheme: &TerminalHandlerColorScheme{},
+		PrettyJSON:  true,
+		PrettyYAML:  true,
+	}
+
+	tests := []struct {
+		name      string
+		opts      *TerminalHandlerOptions
+		value     slog.Value
+		multiline bool
+		expected  string
+		ok        bool
+	}{
+		{
+			name:      "json_multiline",
+			opts:      noColorOpts,
+			value:     slog.StringValue(`{"a":1,"b":[true,null,"x"],"c":{},"d":"<é>"}`),
+			multiline: true,
+			expected: "{\n" +
+				"  \"a\": 1,\n" +
+				"  \"b\": [\n" +
+				"    true,\n" +
+				"    null,\n" +
+				"    \"x\"\n" +
+				"  ],\n" +
+				"  \"c\": {},\n" +
+				"  \"d\": \"<é>\"\n" +
+				"}",
+			ok: true,
+		},
+		{
+			name:     "json_single_line",
+			opts:     noColorOpts,
+			value:    slog.StringValue(` {"a": 1, "b": [1.5, 2]}`),
+			expected: `{"a": 1, "b": [1.5, 2]}`,
+			ok:       true,
+		},
+		{
+			name: "json_colors",
+			opts: &TerminalHandlerOptions{
+				ColorScheme: DefaultTerminalHandlerColorScheme,
+				PrettyJSON:  true,
+			},
+			value: slog.StringValue(`{"a":1}`),
+			expected: "\033[2m{\033[22m" +
+				"\033[34m\"a\"\033[39m\033[2m:\033[22m \033[35m1\033[39m" +
+				"\033[2m}\033[22m",
+			ok: true,
+		},
+		{
+			name:  "json_disabled",
+			opts:  &TerminalHandlerOptions{ColorScheme: &TerminalHandlerColorScheme{}},
+			value: slog.StringValue(`{"a":1}`),
+		},
+		{
+			name:  "json_invalid",
+			opts:  noColorOpts,
+			value: slog.StringValue(`{"a":}`),
+		},
+		{
+			name:  "not_string",
+			opts:  noColorOpts,
+			value: slog.IntValue(1),
+		},
+		{
+			name:      "yaml_multiline",
+			opts:      noColorOpts,
+			value:     slog.StringValue("a: 1\nb:\n  - x\n  - c: true\n    d: ~\ne: {}\nf: \"quoted\"\n"),
+			multiline: true,
+			expected: "a: 1\n" +
+				"b:\n" +
+				"  - x\n" +
+				"  - c: true\n" +
+				"    d: ~\n" +
+				"e: {}\n" +
+				"f: \"quoted\"",
+			ok: true,
+		},
+		{
+			name:     "yaml_single_line",
+			opts:     noColorOpts,
+			value:    slog.StringValue("a: 1\nb:\n  - x\n  - y\n"),
+			expected: "{a: 1, b: [x, y]}",
+			ok:       true,
+		},
+		{
+			name:  "yaml_single_line_string",
+			opts:  noColorOpts,
+			value: slog.StringValue("a: 1"),
+		},
+		{
+			name:  "yaml_scalar",
+			opts:  noColorOpts,
+			value: slog.StringValue("line 1\nline 2"),
+		},
+		{
+			name:     "json_control_characters",
+			opts:     noColorOpts,
+			value:    slog.StringValue("{\"\u009b2Jkey\": \"\u202egnp.exe\", \"esc\": \"\\u001b[2J\"}"),
+			expected: `{"\u009b2Jkey": "\u202egnp.exe", "esc": "\u001b[2J"}`,
+			ok:       true,
+		},
+		{
+			name:      "yaml_control_characters",
+			opts:      noColorOpts,
+			value:     slog.StringValue("\"\\e[2Jkey\": 1\n\u202ebidi: \"\\x9b2J\"\n"),
+			multiline: true,
+			expected:  "\\x1b[2Jkey: 1\n\\u202ebidi: \"\\u009b2J\"",
+			ok:        true,
+		},
+		{
+			name:     "yaml_single_line_control_characters",
+			opts:     noColorOpts,
+			value:    slog.StringValue("\"\\e[2Jkey\": 1\n\u202ebidi: \"\\x9b2J\"\n"),
+			expected: "{\\x1b[2Jkey: 1, \\u202ebidi: \"\\u009b2J\"}",
+			ok:       true,
+		},
+		{
+			name:  "yaml_disabled",
+			opts:  &TerminalHandlerOptions{ColorScheme: &TerminalHandlerColorScheme{}, PrettyJSON: true},
+			value: slog.StringValue("a: 1\nb: 2"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pretty, ok := prettyValue(tt.value, tt.opts, tt.multiline)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, pretty)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+var solarizedBase01 = ansi.RGBColor{R: 0x58, G: 0x6e, B: 0x75}
+var solarizedYellow = ansi.RGBColor{R: 0xb5, G: 0x89, B: 0x00}
+var solarizedOrange = ansi.RGBColor{R: 0xcb, G: 0x4b, B: 0x16}
+var solarizedRed = ansi.RGBColor{R: 0xdc, G: 0x32, B: 0x2f}
+var solarizedMagenta = ansi.RGBColor{R: 0xd3, G: 0x36, B: 0x82}
+var solarizedViolet = ansi.RGBColor{R: 0x6c, G: 0x71, B: 0xc4}
+var solarizedBlue = ansi.RGBColor{R: 0x26, G: 0x8b, B: 0xd2}
+var solarizedCyan = ansi.RGBColor{R: 0x2a, G: 0xa1, B: 0x98}
+var solarizedGreen = ansi.RGBColor{R: 0x85, G: 0x99, B: 0x00}
+
+// Color scheme for terminals with a light background: unlike DefaultTerminalHandlerColorScheme,
+// it does not use dim text, cyan or yellow.
+var LightTerminalHandlerColorScheme = &TerminalHandlerColorScheme{
+	GroupName: ansi.NewStyle(ansi.Bold),
+	AttrKey:   ansi.NewStyle().Fg(ansi.Blue),
+	AttrValue: ansi.Style{},
+
+	AttrValueNumber:   ansi.NewStyle().Fg(ansi.Magenta),
+	AttrValueBool:     ansi.NewStyle().Fg(ansi.IndexedColor(130)),
+	AttrValueDuration: ansi.NewStyle().Fg(ansi.Blue),
+	AttrValueTime:     ansi.NewStyle().Fg(ansi.Blue),
+	AttrValueNil:      ansi.NewStyle().Fg(ansi.BrightBlack).Italic(),
+	AttrValueError:    ansi.NewStyle().Fg(ansi.Red),
+
+	Time:         ansi.NewStyle().Fg(ansi.BrightBlack),
+	LevelDebug:   ansi.NewStyle().Fg(ansi.Magenta).Bold(),
+	MessageDebug: ansi.NewStyle(ansi.Bold),
+	LevelInfo:    ansi.NewStyle().Fg(ansi.Green).Bold(),
+	MessageInfo:  ansi.NewStyle(ansi.Bold),
+	LevelWarn:    ansi.NewStyle().Fg(ansi.IndexedColor(130)).Bold(),
+	MessageWarn:  ansi.NewStyle(ansi.Bold),
+	LevelError:   ansi.NewStyle().Fg(ansi.Red).Bold(),
+	MessageError: ansi.NewStyle(ansi.Bold),
+	File:         ansi.NewStyle().Fg(ansi.BrightBlack),
+	Line:         ansi.NewStyle().Fg(ansi.BrightBlack),
+	Function:     ansi.NewStyle().Fg(ansi.BrightBlack),
+	Elision:      ansi.NewStyle().Fg(ansi.BrightBlack).Italic(),
+	TreeGuide:    ansi.NewStyle().Fg(ansi.BrightBlack),
+
+	SyntaxKey:         ansi.NewStyle().Fg(ansi.Blue),
+	SyntaxString:      ansi.NewStyle().Fg(ansi.Green),
+	SyntaxNumber:      ansi.NewStyle().Fg(ansi.Magenta),
+	SyntaxBool:        ansi.NewStyle().Fg(ansi.IndexedColor(130)),
+	SyntaxNull:        ansi.NewStyle().Fg(ansi.BrightBlack),
+	SyntaxPunctuation: ansi.NewStyle().Fg(ansi.BrightBlack),
+}
+
+// Color scheme with the accent colors of the Solarized palette, readable on both its dark and
+// light backgrounds. Colors are downsampled when the terminal does not support truecolor.
+var SolarizedTerminalHandlerColorScheme = &TerminalHandlerColorScheme{
+	GroupName: ansi.NewStyle(ansi.Bold),
+	AttrKey:   ansi.NewStyle().Fg(solarizedCyan),
+	AttrValue: ansi.Style{},
+
+	AttrValueNumber:   ansi.NewStyle().Fg(solarizedMagenta),
+	AttrValueBool:     ansi.NewStyle().Fg(solarizedYellow),
+	AttrValueDuration: ansi.NewStyle().Fg(solarizedViolet),
+	AttrValueTime:     ansi.NewStyle().Fg(solarizedViolet),
+	AttrValueNil:      ansi.NewStyle().Fg(solarizedBase01).Italic(),
+	AttrValueError:    ansi.NewStyle().Fg(solarizedRed),
+
+	Time:         ansi.NewStyle().Fg(solarizedBase01),
+	LevelDebug:   ansi.NewStyle().Fg(solarizedViolet).Bold(),
+	MessageDebug: ansi.NewStyle(ansi.Bold),
+	LevelInfo:    ansi.NewStyle().Fg(solarizedGreen).Bold(),
+	MessageInfo:  ansi.NewStyle(ansi.Bold),
+	LevelWarn:    ansi.NewStyle().Fg(solarizedOrange).Bold(),
+	MessageWarn:  ansi.NewStyle(ansi.Bold),
+	LevelError:   ansi.NewStyle().Fg(solarizedRed).Bold(),
+	MessageError: ansi.NewStyle(ansi.Bold),
+	File:         ansi.NewStyle().Fg(solarizedBlue),
+	Line:         ansi.NewStyle().Fg(solarizedBlue),
+	Function:     ansi.NewStyle().Fg(solarizedBlue),
+	Elision:      ansi.NewStyle().Fg(solarizedBase01).Italic(),
+	TreeGuide:    ansi.NewStyle().Fg(solarizedBase01),
+
+	SyntaxKey:         ansi.NewStyle().Fg(solarizedBlue),
+	SyntaxString:      ansi.NewStyle().Fg(solarizedCyan),
+	SyntaxNumber:      ansi.NewStyle().Fg(solarizedMagenta),
+	SyntaxBool:        ansi.NewStyle().Fg(solarizedYellow),
+	SyntaxNull:        ansi.NewStyle().Fg(solarizedBase01),
+	SyntaxPunctuation: ansi.NewStyle().Fg(solarizedBase01),
+}
+
+// Color scheme without colors, using only display attributes such as bold and dim text.
+var MonochromeTerminalHandlerColorScheme = &TerminalHandlerColorScheme{
+	GroupName: ansi.NewStyle(ansi.Bold),
+	AttrKey:   ansi.NewStyle(ansi.Dim),
+	AttrValue: ansi.Style{},
+
+	AttrValueNil:   ansi.NewStyle(ansi.Italic),
+	AttrValueError: ansi.NewStyle(ansi.Bold),
+
+	Time:         ansi.NewStyle(ansi.Dim),
+	LevelDebug:   ansi.NewStyle(ansi.Dim),
+	MessageDebug: ansi.NewStyle(ansi.Bold),
+	LevelInfo:    ansi.NewStyle(ansi.Bold),
+	MessageInfo:  ansi.NewStyle(ansi.Bold),
+	LevelWarn:    ansi.NewStyle(ansi.Bold, ansi.Underline),
+	MessageWarn:  ansi.NewStyle(ansi.Bold),
+	LevelError:   ansi.NewStyle(ansi.Bold, ansi.Reverse),
+	MessageError: ansi.NewStyle(ansi.Bold),
+	File:         ansi.NewStyle(ansi.Dim),
+	Line:         ansi.NewStyle(ansi.Dim),
+	Function:     ansi.NewStyle(ansi.Dim),
+	Elision:      ansi.NewStyle(ansi.Dim, ansi.Italic),
+	TreeGuide:    ansi.NewStyle(ansi.Dim),
+
+	SyntaxKey:         ansi.NewStyle(ansi.Bold),
+	SyntaxNull:        ansi.NewStyle(ansi.Italic),
+	SyntaxPunctuation: ansi.NewStyle(ansi.Dim),
+}
+
+var terminalThemes = map[string]*TerminalHandlerColorScheme{
+	"dark":       DefaultTerminalHandlerColorScheme,
+	"light":      LightTerminalHandlerColorScheme,
+	"solarized":  SolarizedTerminalHandlerColorScheme,
+	"monochrome": MonochromeTerminalHandlerColorScheme,
+}
+
+// TerminalThemeNames returns the names of all built-in themes.
+func TerminalThemeNames() []string {
+	return []string{"dark", "light", "solarized", "monochrome"}
+}
+
+// TerminalTheme returns the built-in color scheme with the given name, case insensitively, from
+// [TerminalThemeNames]. "dark" is DefaultTerminalHandlerColorScheme.
+func TerminalTheme(name string) (*TerminalHandlerColorScheme, error) {
+	if colorScheme, ok := terminalThemes[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return colorScheme, nil
+	}
+	return nil, fmt.Errorf(
+		"invalid theme %#v, valid options are %s", name, strings.Join(TerminalThemeNames(), ", "),
+	)
+}
+
+// terminalThemeKeys maps theme keys to the color scheme field they set.
+var terminalThemeKeys = map[string]func(*TerminalHandlerColorScheme) *ansi.Style{
+	"group.name":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.GroupName },
+	"attr.key":            func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrKey },
+	"attr.value":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValue },
+	"attr.value.string":   func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueString },
+	"attr.value.number":   func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueNumber },
+	"attr.value.bool":     func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueBool },
+	"attr.value.duration": func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueDuration },
+	"attr.value.time":     func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueTime },
+	"attr.value.nil":      func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueNil },
+	"attr.value.error":    func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.AttrValueError },
+	"time":                func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.Time },
+	"level.debug":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.LevelDebug },
+	"message.debug":       func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.MessageDebug },
+	"level.info":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.LevelInfo },
+	"message.info":        func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.MessageInfo },
+	"level.warn":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.LevelWarn },
+	"message.warn":        func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.MessageWarn },
+	"level.error":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.LevelError },
+	"message.error":       func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.MessageError },
+	"source.file":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.File },
+	"source.line":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.Line },
+	"source.function":     func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.Function },
+	"elision":             func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.Elision },
+	"tree.guide":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.TreeGuide },
+	"syntax.key":          func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxKey },
+	"syntax.string":       func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxString },
+	"syntax.number":       func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxNumber },
+	"syntax.bool":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxBool },
+	"syntax.null":         func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxNull },
+	"syntax.punctuation":  func(cs *TerminalHandlerColorScheme) *ansi.Style { return &cs.SyntaxPunctuation },
+}
+
+// TerminalThemeKeys returns the keys of all color scheme styles that can be set by themes, eg:
+// "level.error" for LevelError.
+func TerminalThemeKeys() []string {
+	keys := make([]string, 0, len(terminalThemeKeys))
+	for key := range terminalThemeKeys {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// set sets the style of the color scheme for key, from a style text parsed by ansi.ParseStyle.
+func (cs *TerminalHandlerColorScheme) set(key, style string) error {
+	field, ok := terminalThemeKeys[strings.ToLower(key)]
+	if !ok {
+		return fmt.Errorf(
+			"invalid theme key %#v, valid options are %s", key, strings.Join(TerminalThemeKeys(), ", "),
+		)
+	}
+	parsedStyle, err := ansi.ParseStyle(style)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	*field(cs) = parsedStyle
+	return nil
+}
+
+// ParseTerminalTheme parses a color scheme from a compact spec, of colon separated key=style
+// entries, eg: "level.error=red,bold:attr.key=cyan". Keys are from [TerminalThemeKeys], and styles
+// are parsed with [ansi.ParseStyle]. Styles not set are from DefaultTerminalHandlerColorScheme,
+// or from the built-in theme named by an optional first entry, eg: "light:time=gray".
+func ParseTerminalTheme(spec string) (*TerminalHandlerColorScheme, error) {
+	colorScheme := *DefaultTerminalHandlerColorScheme
+	for i, entry := range strings.Split(spec, ":") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, style, ok := strings.Cut(entry, "=")
+		if !ok {
+			if i > 0 {
+				return nil, fmt.Errorf("invalid theme entry %#v, expected key=style", entry)
+			}
+			base, err := TerminalTheme(entry)
+			if err != nil {
+				return nil, err
+			}
+			colorScheme = *base
+			continue
+		}
+		if err := colorScheme.set(strings.TrimSpace(key), style); err != nil {
+			return nil, err
+		}
+	}
+	return &colorScheme, nil
+}
+
+// decodeTerminalTheme returns a color scheme from decoded theme file values, where nested tables
+// or objects are flattened with dots to theme keys.
+func decodeTerminalTheme(values map[string]any) (*TerminalHandlerColorScheme, error) {
+	colorScheme := *DefaultTerminalHandlerColorScheme
+	if base, ok := values["base"]; ok {
+		name, ok := base.(string)
+		if !ok {
+			return nil, fmt.Errorf("base: expected a theme name, got %T", base)
+		}
+		baseColorScheme, err := TerminalTheme(name)
+		if err != nil {
+			return nil, err
+		}
+		colorScheme = *baseColorScheme
+	}
+	var decode func(prefix string, values map[string]any) error
+	decode = func(prefix string, values map[string]any) error {
+		for key, value := range values {
+			if prefix == "" && key == "base" {
+				continue
+			}
+			key = prefix + key
+			switch v := value.(type) {
+			case string:
+				if err := colorScheme.set(key, v); err != nil {
+					return err
+				}
+			case map[string]any:
+				if err := decode(key+".", v); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s: expected a style, got %T", key, value)
+			}
+		}
+		return nil
+	}
+	if err := decode("", values); err != nil {
+		return nil, err
+	}
+	return &colorScheme, nil
+}
+
+// LoadTerminalTheme loads a color scheme from a TOML (.toml) or JSON (.json) theme file. Theme
+// files map keys from [TerminalThemeKeys] to styles parsed with [ansi.ParseStyle], either with
+// dotted keys or nested tables, eg:
+//
+//	base = "light"
+//
+//	[level]
+//	error = "red,bold"
+//
+//	[attr]
+//	key = "cyan"
+//
+// Styles not set are from DefaultTerminalHandlerColorScheme, or from the built-in theme named by
+// the optional base key.
+func LoadTerminalTheme(path string) (*TerminalHandlerColorScheme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported theme file extension %#v, valid options are .toml, .json", path, ext)
+	}
+	colorScheme, err := decodeTerminalTheme(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return colorScheme, nil
+}
@@ -0,0 +1,171 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fornellas/slogxt/ansi"
+)
+
+func TestTerminalTheme(t *testing.T) {
+	for _, name := range TerminalThemeNames() {
+		colorScheme, err := TerminalTheme(name)
+		require.NoError(t, err)
+		require.NotNil(t, colorScheme)
+	}
+
+	colorScheme, err := TerminalTheme("Dark")
+	require.NoError(t, err)
+	assert.Same(t, DefaultTerminalHandlerColorScheme, colorScheme)
+
+	_, err = TerminalTheme("neon")
+	require.EqualError(t, err, `invalid theme "neon", valid options are dark, light, solarized, monochrome`)
+}
+
+func TestTerminalThemeKeys(t *testing.T) {
+	// every style field can be set by a theme
+	colorScheme := TerminalHandlerColorScheme{}
+	for _, key := range TerminalThemeKeys() {
+		require.NoError(t, colorScheme.set(key, "bold"))
+	}
+	assert.Equal(t, &colorScheme, colorScheme.downsample(ansi.ColorLevel16))
+	for _, style := range []ansi.Style{colorScheme.GroupName, colorScheme.File, colorScheme.SyntaxPunctuation} {
+		assert.True(t, ansi.NewStyle(ansi.Bold).Equal(style))
+	}
+}
+
+func TestParseTerminalTheme(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		check         func(t *testing.T, colorScheme *TerminalHandlerColorScheme)
+		expectedError string
+	}{
+		{
+			name: "empty",
+			spec: "",
+			check: func(t *testing.T, colorScheme *TerminalHandlerColorScheme) {
+				assert.Equal(t, DefaultTerminalHandlerColorScheme, colorScheme)
+				assert.NotSame(t, DefaultTerminalHandlerColorScheme, colorScheme)
+			},
+		},
+		{
+			name: "overrides",
+			spec: "level.error=red,bold:attr.key=cyan",
+			check: func(t *testing.T, colorScheme *TerminalHandlerColorScheme) {
+				assert.True(t, ansi.NewStyle(ansi.FgRed, ansi.Bold).Equal(colorScheme.LevelError))
+				assert.True(t, ansi.NewStyle(ansi.FgCyan).Equal(colorScheme.AttrKey))
+				assert.True(t, DefaultTerminalHandlerColorScheme.Time.Equal(colorScheme.Time))
+			},
+		},
+		{
+			name: "base theme",
+			spec: "light: time=none",
+			check: func(t *testing.T, colorScheme *TerminalHandlerColorScheme) {
+				assert.True(t, colorScheme.Time.IsZero())
+				assert.True(t, LightTerminalHandlerColorScheme.AttrKey.Equal(colorScheme.AttrKey))
+			},
+		},
+		{
+			name:          "invalid key",
+			spec:          "level.fatal=red",
+			expectedError: `invalid theme key "level.fatal", valid options are `,
+		},
+		{
+			name:          "invalid style",
+			spec:          "time=#12",
+			expectedError: `time: invalid style "#12": invalid color "#12", expected #rrggbb`,
+		},
+		{
+			name:          "base theme not first",
+			spec:          "time=dim:light",
+			expectedError: `invalid theme entry "light", expected key=style`,
+		},
+		{
+			name:          "invalid base theme",
+			spec:          "neon:time=dim",
+			expectedError: `invalid theme "neon", valid options are dark, light, solarized, monochrome`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colorScheme, err := ParseTerminalTheme(tt.spec)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, colorScheme)
+		})
+	}
+}
+
+func TestLoadTerminalTheme(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "toml",
+			file: "theme.toml",
+			content: "base = \"light\"\n" +
+				"\"attr.key\" = \"cyan\"\n" +
+				"\n" +
+				"[level]\n" +
+				"error = \"red,bold\"\n",
+		},
+		{
+			name:    "json",
+			file:    "theme.json",
+			content: `{"base": "light", "attr.key": "cyan", "level": {"error": "red,bold"}}`,
+		},
+		{
+			name:          "invalid extension",
+			file:          "theme.yaml",
+			content:       "",
+			expectedError: `unsupported theme file extension ".yaml", valid options are .toml, .json`,
+		},
+		{
+			name:          "invalid value",
+			file:          "theme.json",
+			content:       `{"level": {"error": 1}}`,
+			expectedError: `level.error: expected a style, got float64`,
+		},
+		{
+			name:          "invalid key",
+			file:          "theme.toml",
+			content:       "[level]\nfatal = \"red\"\n",
+			expectedError: `invalid theme key "level.fatal"`,
+		},
+		{
+			name:          "syntax error",
+			file:          "theme.json",
+			content:       `{`,
+			expectedError: `unexpected end of JSON input`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			colorScheme, err := LoadTerminalTheme(path)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, ansi.NewStyle(ansi.FgCyan).Equal(colorScheme.AttrKey))
+			assert.True(t, ansi.NewStyle(ansi.FgRed, ansi.Bold).Equal(colorScheme.LevelError))
+			assert.True(t, LightTerminalHandlerColorScheme.Time.Equal(colorScheme.Time))
+		})
+	}
+
+	_, err := LoadTerminalTheme(filepath.Join(t.TempDir(), "missing.toml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeMode defines how terminal handlers display record times.
+type TimeMode int
+
+const (
+	// Display the absolute time of records, formatted with TimeLayout. If TimeLayout is empty,
+	// time is not displayed.
+	TimeModeAbsolute TimeMode = iota
+	// Display the time elapsed since the process started, eg: "+1.234s".
+	TimeModeElapsed
+	// Display the time elapsed since the previous record of the same handler tree, or since the
+	// process started for the first record, eg: "+0.012s".
+	TimeModeDelta
+)
+
+var timeModeNames = map[TimeMode]string{
+	TimeModeAbsolute: "absolute",
+	TimeModeElapsed:  "elapsed",
+	TimeModeDelta:    "delta",
+}
+
+// TimeModeNames returns the names of all time modes.
+func TimeModeNames() []string {
+	return []string{
+		timeModeNames[TimeModeAbsolute],
+		timeModeNames[TimeModeElapsed],
+		timeModeNames[TimeModeDelta],
+	}
+}
+
+// String returns the name of the time mode.
+func (m TimeMode) String() string {
+	if name, ok := timeModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("TimeMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m TimeMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [TimeModeNames], case insensitively.
+func (m *TimeMode) UnmarshalText(data []byte) error {
+	for mode, name := range timeModeNames {
+		if strings.EqualFold(string(data), name) {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid time mode %#v, valid options are %s", string(data), strings.Join(TimeModeNames(), ", "),
+	)
+}
+
+// processStart is the reference for TimeModeElapsed.
+var processStart = time.Now()
+
+// terminalClock formats record times, keeping track of the previous record time for
+// TimeModeDelta. It is shared by all handlers derived from the same constructor call.
+type terminalClock struct {
+	mutex sync.Mutex
+	last  time.Time
+}
+
+// format returns t formatted as a function of mode and timeLayout, or an empty string if time is
+// not to be displayed.
+func (c *terminalClock) format(mode TimeMode, timeLayout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch mode {
+	case TimeModeElapsed:
+		return formatElapsed(t.Sub(processStart))
+	case TimeModeDelta:
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		last := c.last
+		if last.IsZero() {
+			last = processStart
+		}
+		c.last = t
+		return formatElapsed(t.Sub(last))
+	default:
+		if timeLayout == "" {
+			return ""
+		}
+		return t.Round(0).Format(timeLayout)
+	}
+}
+
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%+.3fs", d.Seconds())
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setProcessStart sets processStart, restoring it when the test ends.
+func setProcessStart(t *testing.T, start time.Time) {
+	original := processStart
+	processStart = start
+	t.Cleanup(func() { processStart = original })
+}
+
+func TestTimeMode(t *testing.T) {
+	for _, mode := range []TimeMode{TimeModeAbsolute, TimeModeElapsed, TimeModeDelta} {
+		text, err := mode.MarshalText()
+		require.NoError(t, err)
+		var parsedMode TimeMode
+		require.NoError(t, parsedMode.UnmarshalText(text))
+		assert.Equal(t, mode, parsedMode)
+	}
+
+	var mode TimeMode
+	require.NoError(t, mode.UnmarshalText([]byte("Elapsed")))
+	assert.Equal(t, TimeModeElapsed, mode)
+	require.Error(t, mode.UnmarshalText([]byte("relative")))
+}
+
+func TestTerminalClock(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	setProcessStart(t, start)
+
+	t.Run("absolute", func(t *testing.T) {
+		clock := &terminalClock{}
+		assert.Equal(t, "2025-01-02 03:04:06", clock.format(TimeModeAbsolute, time.DateTime, start.Add(time.Second)))
+		assert.Equal(t, "", clock.format(TimeModeAbsolute, "", start.Add(time.Second)))
+		assert.Equal(t, "", clock.format(TimeModeAbsolute, time.DateTime, time.Time{}))
+	})
+
+	t.Run("elapsed", func(t *testing.T) {
+		clock := &terminalClock{}
+		assert.Equal(t, "+1.234s", clock.format(TimeModeElapsed, "", start.Add(1234*time.Millisecond)))
+		assert.Equal(t, "+61.000s", clock.format(TimeModeElapsed, time.DateTime, start.Add(61*time.Second)))
+		assert.Equal(t, "", clock.format(TimeModeElapsed, "", time.Time{}))
+	})
+
+	t.Run("delta", func(t *testing.T) {
+		clock := &terminalClock{}
+		assert.Equal(t, "+1.000s", clock.format(TimeModeDelta, "", start.Add(time.Second)))
+		assert.Equal(t, "+0.012s", clock.format(TimeModeDelta, "", start.Add(1012*time.Millisecond)))
+		assert.Equal(t, "", clock.format(TimeModeDelta, "", time.Time{}))
+		assert.Equal(t, "+0.500s", clock.format(TimeModeDelta, "", start.Add(1512*time.Millisecond)))
+	})
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TreeGuides defines how TerminalTreeHandler draws the nesting of groups and attributes.
+type TreeGuides int
+
+const (
+	// Indent nested lines with spaces only.
+	TreeGuidesNone TreeGuides = iota
+	// Draw guides with Unicode box-drawing characters, eg: "├─", "└─" and "│".
+	TreeGuidesUnicode
+	// Draw guides with ASCII characters, eg: "|-", "`-" and "|".
+	TreeGuidesASCII
+)
+
+var treeGuidesNames = map[TreeGuides]string{
+	TreeGuidesNone:    "none",
+	TreeGuidesUnicode: "unicode",
+	TreeGuidesASCII:   "ascii",
+}
+
+// TreeGuidesNames returns the names of all tree guide styles.
+func TreeGuidesNames() []string {
+	return []string{
+		treeGuidesNames[TreeGuidesNone],
+		treeGuidesNames[TreeGuidesUnicode],
+		treeGuidesNames[TreeGuidesASCII],
+	}
+}
+
+// String returns the name of the tree guide style.
+func (g TreeGuides) String() string {
+	if name, ok := treeGuidesNames[g]; ok {
+		return name
+	}
+	return fmt.Sprintf("TreeGuides(%d)", int(g))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (g TreeGuides) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by
+// [TreeGuidesNames], case insensitively.
+func (g *TreeGuides) UnmarshalText(data []byte) error {
+	for guides, name := range treeGuidesNames {
+		if strings.EqualFold(string(data), name) {
+			*g = guides
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid tree guides %#v, valid options are %s", string(data), strings.Join(TreeGuidesNames(), ", "),
+	)
+}
+
+// treeGuideGlyphs holds the strings drawn at each nesting level: a vertical line through a level
+// with more siblings, and the branches to a node with and without more siblings after it.
+type treeGuideGlyphs struct {
+	vertical, branch, lastBranch string
+}
+
+var treeGuidesGlyphs = map[TreeGuides]treeGuideGlyphs{
+	TreeGuidesUnicode: {vertical: "│  ", branch: "├─ ", lastBranch: "└─ "},
+	TreeGuidesASCII:   {vertical: "|  ", branch: "|- ", lastBranch: "`- "},
+}
+
+// treeIndent is the indentation of a node written by TerminalTreeHandler: one entry per nesting
+// level, telling whether more siblings follow the node, or its ancestor, at that level.
+type treeIndent []bool
+
+// streamIndent returns the indentation of a node at depth, under groups from WithGroup. As more
+// records may always follow, siblings are assumed at every level.
+func streamIndent(depth int) treeIndent {
+	indent := make(treeIndent, depth)
+	for i := range indent {
+		indent[i] = true
+	}
+	return indent
+}
+
+// child returns the indentation of a child node.
+func (t treeIndent) child(more bool) treeIndent {
+	return append(slices.Clone(t), more)
+}
+
+// more returns whether siblings follow the node.
+func (t treeIndent) more() bool {
+	return len(t) > 0 && t[len(t)-1]
+}
+
+// withMore returns the indentation of a sibling node.
+func (t treeIndent) withMore(more bool) treeIndent {
+	if len(t) == 0 {
+		return t
+	}
+	t = slices.Clone(t)
+	t[len(t)-1] = more
+	return t
+}
+
+// prefix returns the guides to write before the first line of a node, or before its continuation
+// lines.
+func (t treeIndent) prefix(guides TreeGuides, continuation bool) string {
+	glyphs, ok := treeGuidesGlyphs[guides]
+	if !ok {
+		return strings.Repeat("  ", len(t))
+	}
+	var b strings.Builder
+	for i, more := range t {
+		switch {
+		case (i < len(t)-1 || continuation) && more:
+			b.WriteString(glyphs.vertical)
+		case i < len(t)-1 || continuation:
+			b.WriteString(strings.Repeat(" ", len([]rune(glyphs.vertical))))
+		case more:
+			b.WriteString(glyphs.branch)
+		default:
+			b.WriteString(glyphs.lastBranch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeGuides(t *testing.T) {
+	for _, guides := range []TreeGuides{TreeGuidesNone, TreeGuidesUnicode, TreeGuidesASCII} {
+		text, err := guides.MarshalText()
+		require.NoError(t, err)
+		var parsedGuides TreeGuides
+		require.NoError(t, parsedGuides.UnmarshalText(text))
+		assert.Equal(t, guides, parsedGuides)
+	}
+
+	var guides TreeGuides
+	require.NoError(t, guides.UnmarshalText([]byte("ASCII")))
+	assert.Equal(t, TreeGuidesASCII, guides)
+	require.Error(t, guides.UnmarshalText([]byte("boxes")))
+}
+
+func TestTreeIndent(t *testing.T) {
+	tests := []struct {
+		name         string
+		guides       TreeGuides
+		indent       treeIndent
+		continuation bool
+		expected     string
+	}{
+		{"root", TreeGuidesUnicode, treeIndent{}, false, ""},
+		{"none", TreeGuidesNone, treeIndent{true, false}, false, "    "},
+		{"none_continuation", TreeGuidesNone, treeIndent{true, false}, true, "    "},
+		{"branch", TreeGuidesUnicode, treeIndent{true, true}, false, "│  ├─ "},
+		{"last_branch", TreeGuidesUnicode, treeIndent{false, false}, false, "   └─ "},
+		{"continuation", TreeGuidesUnicode, treeIndent{true, true}, true, "│  │  "},
+		{"last_continuation", TreeGuidesUnicode, treeIndent{true, false}, true, "│     "},
+		{"ascii", TreeGuidesASCII, treeIndent{true, true, false}, false, "|  |  `- "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.indent.prefix(tt.guides, tt.continuation))
+		})
+	}
+
+	indent := streamIndent(2)
+	assert.Equal(t, treeIndent{true, true}, indent)
+	assert.Equal(t, treeIndent{true, true, false}, indent.child(false))
+	assert.Equal(t, treeIndent{true, false}, indent.withMore(false))
+	assert.Equal(t, treeIndent{true, true}, indent)
+	assert.True(t, indent.more())
+	assert.False(t, treeIndent{}.more())
+}
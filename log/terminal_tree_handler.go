@@ -6,12 +6,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"slices"
 	"strings"
 	"sync"
 
-	"golang.org/x/term"
+	"github.com/fornellas/slogxt/ansi"
 )
 
 // currHandlerChain tracks the chain of TerminalTreeHandler instances to avoid
@@ -63,6 +62,7 @@ type TerminalTreeHandler struct {
 	opts             *TerminalHandlerOptions
 	writer           io.Writer
 	writerMutex      *sync.Mutex
+	clock            *terminalClock
 	groups           []string
 	attrs            []slog.Attr
 	handlerChain     []*TerminalTreeHandler
@@ -71,28 +71,13 @@ type TerminalTreeHandler struct {
 
 // NewTerminalTreeHandler creates a new TerminalTreeHandler
 func NewTerminalTreeHandler(w io.Writer, opts *TerminalHandlerOptions) *TerminalTreeHandler {
-	var optsValue TerminalHandlerOptions
-	if opts != nil {
-		optsValue = *opts
-	}
-
-	if optsValue.ColorScheme == nil {
-		optsValue.ColorScheme = DefaultTerminalHandlerColorScheme
-	}
-
-	isTTY := false
-	if f, ok := w.(*os.File); ok {
-		isTTY = term.IsTerminal(int(f.Fd()))
-	}
-
-	if !(!optsValue.NoColor && (optsValue.ForceColor || isTTY)) {
-		optsValue.ColorScheme = &TerminalHandlerColorScheme{}
-	}
+	optsValue := newTerminalHandlerOptions(w, opts)
 
 	h := &TerminalTreeHandler{
-		opts:             &optsValue,
+		opts:             optsValue,
 		writer:           w,
 		writerMutex:      &sync.Mutex{},
+		clock:            &terminalClock{},
 		groups:           []string{},
 		attrs:            []slog.Attr{},
 		currHandlerChain: newCurrHandlerChain(),
@@ -139,68 +124,183 @@ func (h *TerminalTreeHandler) WithGroup(name string) slog.Handler {
 	return h2
 }
 
-func (h *TerminalTreeHandler) writeAttrGroupValue(w io.Writer, indent int, attr slog.Attr) error {
+// writeIndent writes the indentation before the first line of a node, or before its continuation
+// lines, with tree guides styled.
+func (h *TerminalTreeHandler) writeIndent(w io.Writer, indent treeIndent, continuation bool) error {
+	prefix := indent.prefix(h.opts.TreeGuides, continuation)
+	if strings.TrimSpace(prefix) == "" {
+		_, err := io.WriteString(w, prefix)
+		return err
+	}
+	_, err := h.opts.ColorScheme.TreeGuide.Fprintf(w, "%s", prefix)
+	return err
+}
+
+// writeContinuation starts a new continuation line of a node, for multiline or wrapped values.
+func (h *TerminalTreeHandler) writeContinuation(w io.Writer, indent treeIndent) error {
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	if err := h.writeIndent(w, indent, true); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte("  "))
+	return err
+}
+
+// writeAttrs writes the given attributes with writeAttr, eliding the ones beyond MaxGroupAttrs.
+// All attributes are written as siblings at indent, where only the last one takes whether more
+// siblings follow from indent.
+func (h *TerminalTreeHandler) writeAttrs(
+	w io.Writer,
+	indent treeIndent,
+	attrs []slog.Attr,
+	writeAttr func(w io.Writer, indent treeIndent, attr slog.Attr) error,
+) error {
+	var elided int
+	if h.opts.MaxGroupAttrs > 0 && len(attrs) > h.opts.MaxGroupAttrs {
+		elided = len(attrs) - h.opts.MaxGroupAttrs
+		attrs = attrs[:h.opts.MaxGroupAttrs]
+	}
+	for i, attr := range attrs {
+		more := i < len(attrs)-1 || elided > 0 || indent.more()
+		if err := writeAttr(w, indent.withMore(more), attr); err != nil {
+			return err
+		}
+	}
+	if elided > 0 {
+		if err := h.writeIndent(w, indent, false); err != nil {
+			return err
+		}
+		if _, err := writeElision(w, h.opts, elided, "attributes"); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAttrCompositeValue writes an expanded map, slice, array or struct value, with its elements
+// nested under key.
+func (h *TerminalTreeHandler) writeAttrCompositeValue(
+	w io.Writer, indent treeIndent, key string, cv compositeValue,
+) error {
+	if err := h.writeIndent(w, indent, false); err != nil {
+		return err
+	}
+	if _, err := h.opts.ColorScheme.AttrKey.Fprintf(w, "%s:", escape(key)); err != nil {
+		return err
+	}
+	if len(cv.attrs) == 0 {
+		empty := "{}"
+		if cv.list {
+			empty = "[]"
+		}
+		if _, err := h.opts.ColorScheme.AttrValue.Fprintf(w, " %s", empty); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	return h.writeAttrs(w, indent.child(false), cv.attrs, h.writeAttrValue)
+}
+
+func (h *TerminalTreeHandler) writeAttrGroupValue(w io.Writer, indent treeIndent, attr slog.Attr) error {
 	groupAttrs := attr.Value.Group()
 	if len(attr.Key) == 0 {
-		for _, groupAttr := range groupAttrs {
-			if err := h.writeAttr(w, indent, groupAttr); err != nil {
-				return err
-			}
+		if err := h.writeAttrs(w, indent, groupAttrs, h.writeAttr); err != nil {
+			return err
 		}
 	} else {
-		indentStr := strings.Repeat("  ", indent)
-		if _, err := w.Write([]byte(indentStr)); err != nil {
+		if err := h.writeIndent(w, indent, false); err != nil {
 			return err
 		}
-		if _, err := writeGroup(w, h.opts.ColorScheme, attr.Key); err != nil {
+		if _, err := writeGroup(w, h.opts, attr.Key); err != nil {
 			return err
 		}
 		if _, err := w.Write([]byte("\n")); err != nil {
 			return err
 		}
-		for _, groupAttr := range groupAttrs {
-			if err := h.writeAttr(w, indent+1, groupAttr); err != nil {
-				return err
-			}
+		if err := h.writeAttrs(w, indent.child(false), groupAttrs, h.writeAttr); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (h *TerminalTreeHandler) writeAttrNonGroupValue(w io.Writer, indent int, attr slog.Attr) error {
-	indentStr := strings.Repeat("  ", indent)
-	if _, err := fmt.Fprintf(w, "%s", indentStr); err != nil {
+func (h *TerminalTreeHandler) writeAttrNonGroupValue(w io.Writer, indent treeIndent, attr slog.Attr) error {
+	indentWidth := textWidth(indent.prefix(h.opts.TreeGuides, false))
+	if err := h.writeIndent(w, indent, false); err != nil {
 		return err
 	}
-	if _, err := h.opts.ColorScheme.AttrKey.Fprintf(w, "%s:", escape(attr.Key)); err != nil {
+	key := escape(attr.Key)
+	if _, err := h.opts.ColorScheme.AttrKey.Fprintf(w, "%s:", key); err != nil {
 		return err
 	}
 
 	var valueStr string
 	var useANSI bool
+	valueStyle := h.opts.ColorScheme.attrValue(attr.Value)
 	if tv, ok := attr.Value.Any().(TerminalValuer); ok {
 		terminalValue := tv.TerminalValue()
-		valueStr = terminalValue.String()
+		valueStr = ansi.Sanitize(terminalValue.String())
+		useANSI = true
+	} else if link, ok := hyperlinkValue(attr.Value, h.opts); ok {
+		valueStr = link
+		useANSI = true
+	} else if pretty, ok := prettyValue(attr.Value, h.opts, true); ok {
+		valueStr = pretty
 		useANSI = true
+		valueStyle = ansi.Style{}
 	} else {
 		valueStr = attr.Value.String()
 		useANSI = false
 	}
+	valueStr, elided, units := elideValue(valueStr, h.opts.MaxValueLength, h.opts.MaxValueLines)
 
+	var lines []string
 	if len(valueStr) > 0 && strings.Contains(valueStr, "\n") {
-		var fnErr error
-		for line := range strings.SplitSeq(valueStr, "\n") {
-			if _, fnErr = fmt.Fprintf(w, "\n  %s", indentStr); fnErr != nil {
-				return fnErr
-			}
+		lines = strings.Split(valueStr, "\n")
+	} else if h.opts.Width > 0 {
+		width := textWidth(valueStr)
+		if !useANSI {
+			width = textWidth(escape(valueStr))
+		}
+		if indentWidth+textWidth(key)+2+width > h.opts.Width {
+			lines = []string{valueStr}
+		}
+	}
+
+	if lines != nil {
+		continuationWidth := textWidth(indent.prefix(h.opts.TreeGuides, true)) + 2
+		var wrappedLines []string
+		for _, line := range lines {
 			var processedLine string
 			if useANSI {
 				processedLine = line
 			} else {
 				processedLine = escape(line)
 			}
-			if _, fnErr = h.opts.ColorScheme.AttrValue.Fprintf(w, "%s", processedLine); fnErr != nil {
-				return fnErr
+			wrappedLines = append(wrappedLines, wrapLine(processedLine, h.opts.Width-continuationWidth)...)
+		}
+		// styles spanning lines must not leak into continuations
+		for _, wrappedLine := range ansi.SplitLines(wrappedLines) {
+			if err := h.writeContinuation(w, indent); err != nil {
+				return err
+			}
+			if _, err := valueStyle.Fprintf(w, "%s", wrappedLine); err != nil {
+				return err
+			}
+		}
+		if elided > 0 {
+			if err := h.writeContinuation(w, indent); err != nil {
+				return err
+			}
+			if _, err := writeElision(w, h.opts, elided, units); err != nil {
+				return err
 			}
 		}
 		if _, err := w.Write([]byte("\n")); err != nil {
@@ -213,9 +313,17 @@ func (h *TerminalTreeHandler) writeAttrNonGroupValue(w io.Writer, indent int, at
 		} else {
 			processedValue = escape(valueStr)
 		}
-		if _, err := h.opts.ColorScheme.AttrValue.Fprintf(w, " %s", processedValue); err != nil {
+		if _, err := valueStyle.Fprintf(w, " %s", processedValue); err != nil {
 			return err
 		}
+		if elided > 0 {
+			if _, err := w.Write([]byte(" ")); err != nil {
+				return err
+			}
+			if _, err := writeElision(w, h.opts, elided, units); err != nil {
+				return err
+			}
+		}
 		if _, err := fmt.Fprintf(w, "\n"); err != nil {
 			return err
 		}
@@ -223,7 +331,93 @@ func (h *TerminalTreeHandler) writeAttrNonGroupValue(w io.Writer, indent int, at
 	return nil
 }
 
-func (h *TerminalTreeHandler) writeAttr(w io.Writer, indent int, attr slog.Attr) error {
+func (h *TerminalTreeHandler) writeErrorMessage(w io.Writer, message string) error {
+	message, elided, units := elideValue(message, h.opts.MaxValueLength, 0)
+	valueStyle := h.opts.ColorScheme.attrValueOr(h.opts.ColorScheme.AttrValueError)
+	if _, err := valueStyle.Fprintf(w, "%s", escape(message)); err != nil {
+		return err
+	}
+	if elided > 0 {
+		if _, err := w.Write([]byte(" ")); err != nil {
+			return err
+		}
+		if _, err := writeElision(w, h.opts, elided, units); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeErrorNodeDetails writes the stack trace and children of the error node, as siblings at
+// indent, where only the last one takes whether more siblings follow from indent.
+func (h *TerminalTreeHandler) writeErrorNodeDetails(w io.Writer, indent treeIndent, node errorNode) error {
+	items := len(node.frames) + len(node.children)
+	item := 0
+	nextIndent := func() treeIndent {
+		item++
+		return indent.withMore(item < items || indent.more())
+	}
+	for _, frame := range node.frames {
+		if err := h.writeIndent(w, nextIndent(), false); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("at ")); err != nil {
+			return err
+		}
+		if err := writeFrame(w, h.opts, frame); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.children {
+		childIndent := nextIndent()
+		if len(child.message) == 0 {
+			if err := h.writeErrorNodeDetails(w, childIndent, child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := h.writeIndent(w, childIndent, false); err != nil {
+			return err
+		}
+		if err := h.writeErrorMessage(w, child.message); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+		if err := h.writeErrorNodeDetails(w, childIndent.child(false), child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *TerminalTreeHandler) writeAttrErrorValue(w io.Writer, indent treeIndent, key string, err error) error {
+	if err := h.writeIndent(w, indent, false); err != nil {
+		return err
+	}
+	if _, err := h.opts.ColorScheme.AttrKey.Fprintf(w, "%s:", escape(key)); err != nil {
+		return err
+	}
+	node := newErrorNode(err, h.opts.ErrorStackTrace)
+	if len(node.message) > 0 {
+		if _, err := w.Write([]byte(" ")); err != nil {
+			return err
+		}
+		if err := h.writeErrorMessage(w, node.message); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	return h.writeErrorNodeDetails(w, indent.child(false), node)
+}
+
+func (h *TerminalTreeHandler) writeAttr(w io.Writer, indent treeIndent, attr slog.Attr) error {
 	attr.Value = attr.Value.Resolve()
 	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
 		attr = h.opts.ReplaceAttr(h.groups, attr)
@@ -234,10 +428,26 @@ func (h *TerminalTreeHandler) writeAttr(w io.Writer, indent int, attr slog.Attr)
 		return nil
 	}
 
+	if cv, ok := expandComposite(attr.Value, h.opts.CompositeValueDepth); ok {
+		attr.Value = slog.AnyValue(cv)
+	}
+
+	return h.writeAttrValue(w, indent, attr)
+}
+
+func (h *TerminalTreeHandler) writeAttrValue(w io.Writer, indent treeIndent, attr slog.Attr) error {
 	if attr.Value.Kind() == slog.KindGroup {
 		if err := h.writeAttrGroupValue(w, indent, attr); err != nil {
 			return err
 		}
+	} else if cv, ok := attr.Value.Any().(compositeValue); ok {
+		if err := h.writeAttrCompositeValue(w, indent, attr.Key, cv); err != nil {
+			return err
+		}
+	} else if errValue, ok := errorValue(attr.Value); ok {
+		if err := h.writeAttrErrorValue(w, indent, attr.Key, errValue); err != nil {
+			return err
+		}
 	} else {
 		if err := h.writeAttrNonGroupValue(w, indent, attr); err != nil {
 			return err
@@ -280,27 +490,23 @@ func (h *TerminalTreeHandler) writeHandlerGroupAttrs(writer io.Writer, ch *Termi
 		attrs = h.attrs
 	}
 	if len(h.groups) > 0 {
-		if sameGroups {
-			for _, attr := range attrs {
-				if err := h.writeAttr(writer, len(h.groups), attr); err != nil {
-					return err
-				}
-			}
-		} else {
-			attrAny := make([]any, len(attrs))
-			for i, attr := range attrs {
-				attrAny[i] = attr
+		if !sameGroups {
+			if err := h.writeIndent(writer, streamIndent(len(h.groups)-1), false); err != nil {
+				return err
 			}
-			if err := h.writeAttr(writer, len(h.groups)-1, slog.Group(h.groups[len(h.groups)-1], attrAny...)); err != nil {
+			if _, err := writeGroup(writer, h.opts, h.groups[len(h.groups)-1]); err != nil {
 				return err
 			}
-		}
-	} else {
-		for _, attr := range attrs {
-			if err := h.writeAttr(writer, 0, attr); err != nil {
+			if _, err := writer.Write([]byte("\n")); err != nil {
 				return err
 			}
 		}
+		return h.writeAttrs(writer, streamIndent(len(h.groups)), attrs, h.writeAttr)
+	}
+	for _, attr := range attrs {
+		if err := h.writeAttr(writer, treeIndent{}, attr); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -310,7 +516,7 @@ func (h *TerminalTreeHandler) writeLevelMessage(
 ) (int, error) {
 	var n int
 
-	np, err := writeLevel(w, h.opts.ColorScheme, level)
+	np, err := writeLevel(w, h.opts.ColorScheme, h.opts.Levels, h.opts.LevelFormat, h.opts.levelNames, level)
 	n += np
 	if err != nil {
 		return n, err
@@ -322,7 +528,7 @@ func (h *TerminalTreeHandler) writeLevelMessage(
 		return n, err
 	}
 
-	np, err = writeMessage(w, h.opts.ColorScheme, level, message)
+	np, err = writeMessage(w, h.opts.ColorScheme, h.opts.Levels, level, message)
 	n += np
 	if err != nil {
 		return n, err
@@ -347,7 +553,8 @@ func (h *TerminalTreeHandler) Handle(_ context.Context, record slog.Record) erro
 	}
 
 	// Indent
-	if _, err := buff.WriteString(strings.Repeat("  ", len(h.groups))); err != nil {
+	indent := streamIndent(len(h.groups))
+	if err := h.writeIndent(&buff, indent, false); err != nil {
 		return err
 	}
 
@@ -357,11 +564,12 @@ func (h *TerminalTreeHandler) Handle(_ context.Context, record slog.Record) erro
 	}
 
 	// Record: Time
-	if h.opts.TimeLayout != "" && !record.Time.IsZero() {
-		if _, err := buff.WriteString(strings.Repeat("  ", len(h.groups)+1)); err != nil {
+	if timeText := h.clock.format(h.opts.TimeMode, h.opts.TimeLayout, record.Time); timeText != "" {
+		more := h.opts.HandlerOptions.AddSource || record.NumAttrs() > 0
+		if err := h.writeIndent(&buff, indent.child(more), false); err != nil {
 			return err
 		}
-		if _, err := writeTime(&buff, h.opts.TimeLayout, record.Time, h.opts.ColorScheme); err != nil {
+		if _, err := writeTime(&buff, timeText, h.opts.ColorScheme); err != nil {
 			return err
 		}
 		if _, err := buff.WriteString("\n"); err != nil {
@@ -371,10 +579,10 @@ func (h *TerminalTreeHandler) Handle(_ context.Context, record slog.Record) erro
 
 	// Record: PC
 	if h.opts.HandlerOptions.AddSource {
-		if _, err := fmt.Fprintf(&buff, "%s  ", strings.Repeat("  ", len(h.groups))); err != nil {
+		if err := h.writeIndent(&buff, indent.child(record.NumAttrs() > 0), false); err != nil {
 			return err
 		}
-		writePC(&buff, h.opts.ColorScheme, record.PC)
+		writePC(&buff, h.opts, record.PC)
 		if _, err := buff.WriteString("\n"); err != nil {
 			return err
 		}
@@ -382,13 +590,13 @@ func (h *TerminalTreeHandler) Handle(_ context.Context, record slog.Record) erro
 
 	// Record: Attr
 	if record.NumAttrs() > 0 {
-		var attrErr error
+		attrs := make([]slog.Attr, 0, record.NumAttrs())
 		record.Attrs(func(attr slog.Attr) bool {
-			attrErr = h.writeAttr(&buff, len(h.groups)+1, attr)
-			return attrErr == nil
+			attrs = append(attrs, attr)
+			return true
 		})
-		if attrErr != nil {
-			return attrErr
+		if err := h.writeAttrs(&buff, indent.child(false), attrs, h.writeAttr); err != nil {
+			return err
 		}
 	}
 
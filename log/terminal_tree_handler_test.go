@@ -3,13 +3,18 @@ package log
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/fornellas/slogxt/ansi"
 )
 
 func TestTerminalTreeHandler(t *testing.T) {
@@ -368,6 +373,602 @@ $`, output)
 					)
 				},
 			},
+			{
+				name: "width_short_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, Width: 20})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", "short value")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  key: short value\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "width_wrap_long_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, Width: 20})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message",
+						slog.Group("sql",
+							"query", "SELECT id, name FROM users WHERE id = 1",
+						),
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  🏷️ sql\n"+
+							"    query:\n"+
+							"      SELECT id, \n"+
+							"      name FROM \n"+
+							"      users WHERE \n"+
+							"      id = 1\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "width_wrap_multiline_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, Width: 10})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", "123456789012\nabc")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  key:\n"+
+							"    123456\n"+
+							"    789012\n"+
+							"    abc\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "width_wrap_terminal_valuer",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, Width: 10})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", NewTerminalValue("\033[31m1234\033[0m\033[32m5678\033[0m9"))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  key:\n"+
+							"    \033[31m1234\033[0m\033[32m56\033[39m\n"+
+							"    \033[32m78\033[0m9\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "width_wrap_colored_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						ColorMode:  ColorModeAlways,
+						ColorLevel: ansi.ColorLevelTrueColor,
+						TreeGuides: TreeGuidesUnicode,
+						Width:      16,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", NewTerminalValue("\033[31mred words wrapped\033[0m"), "other", 1)
+				},
+				check: func(t *testing.T, output string) {
+					lines := strings.Split(output, "\n")
+					require.Len(t, lines, 6)
+					// the style is closed before each newline, and reopened after the tree guide
+					assert.Equal(t, "\033[2m│  \033[22m  \033[2m\033[31mred words \033[39m\033[22m", lines[2])
+					assert.Equal(t, "\033[2m│  \033[22m  \033[2m\033[31mwrapped\033[0m\033[22m", lines[3])
+				},
+			},
+			{
+				name: "width_wrap_wide_characters",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, Width: 11})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", "日本語のテキスト", "emoji", "a👍🏽bcafe\u0301日x")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  key:\n"+
+							"    日本語\n"+
+							"    のテキ\n"+
+							"    スト\n"+
+							"  emoji:\n"+
+							"    a👍🏽bcaf\n"+
+							"    e\u0301日x\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "max_value_length",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxValueLength: 5})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "short", "12345", "long", "1234567890")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  short: 12345\n"+
+							"  long: 12345 … 5 more bytes\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "max_value_length_terminal_valuer",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxValueLength: 5})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "key", NewTerminalValue("\033[31m1234567890\033[0m"))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  key: \033[31m12345\033[0m … 5 more bytes\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "max_value_lines",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxValueLines: 2})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "lines", "line1\nline2\nline3\nline4")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  lines:\n"+
+							"    line1\n"+
+							"    line2\n"+
+							"    … 2 more lines\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "max_group_attrs",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, MaxGroupAttrs: 2})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message",
+						slog.Group("group", "a", 1, "b", 2, "c", 3),
+						"d", 4,
+						"e", 5,
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  🏷️ group\n"+
+							"    a: 1\n"+
+							"    b: 2\n"+
+							"    … 1 more attribute\n"+
+							"  d: 4\n"+
+							"  … 1 more attribute\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "error_tree",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Error("failed",
+						"err", errors.Join(
+							fmt.Errorf("open config: %w", errors.New("not found")),
+							errors.New("invalid flag"),
+						),
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"ERROR failed\n"+
+							"  err:\n"+
+							"    open config\n"+
+							"      not found\n"+
+							"    invalid flag\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "error_nil_pointer",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, ErrorStackTrace: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					var err *stackError
+					logger.Error("failed", "err", err)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "ERROR failed\n  err: <nil>\n", output)
+				},
+			},
+			{
+				name: "error_stack_trace",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:         true,
+						ErrorStackTrace: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Error("failed", "err", fmt.Errorf("run: %w", framesError{message: "boom"}))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"ERROR failed\n"+
+							"  err: run\n"+
+							"    boom\n"+
+							"      at /src/main.go:10 (main.run)\n"+
+							"      at /src/main.go:3 (main.main)\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "composite_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:             true,
+						CompositeValueDepth: 2,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("config",
+						"server", map[string]any{
+							"host":  "localhost",
+							"ports": []int{80, 443},
+							"tls":   map[string]any{"cert": "a.pem"},
+							"empty": []int{},
+						},
+						"list", []string{"a"},
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO config\n"+
+							"  server:\n"+
+							"    empty: []\n"+
+							"    host: localhost\n"+
+							"    ports:\n"+
+							"      0: 80\n"+
+							"      1: 443\n"+
+							"    tls:\n"+
+							"      cert: a.pem\n"+
+							"  list:\n"+
+							"    0: a\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "pretty_json",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true, PrettyJSON: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("response", "body", `{"id":1,"tags":["a"]}`, "invalid", `{"id":`)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO response\n"+
+							"  body:\n"+
+							"    {\n"+
+							"      \"id\": 1,\n"+
+							"      \"tags\": [\n"+
+							"        \"a\"\n"+
+							"      ]\n"+
+							"    }\n"+
+							"  invalid: {\"id\":\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "custom_levels",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						HandlerOptions: slog.HandlerOptions{Level: LevelTrace},
+						NoColor:        true,
+						Levels:         DefaultTerminalLevels,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					ctx := context.Background()
+					logger.Log(ctx, LevelTrace, "trace message")
+					logger.Log(ctx, LevelNotice, "notice message")
+					logger.Log(ctx, LevelNotice+1, "notice offset message")
+					logger.Log(ctx, LevelFatal, "fatal message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"TRACE trace message\n"+
+							"NOTICE notice message\n"+
+							"NOTICE+1 notice offset message\n"+
+							"FATAL fatal message\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "custom_levels_emoji",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						Levels: TerminalLevels{
+							slog.LevelWarn: {Name: "WARNING", Emoji: "⚠️"},
+						},
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Warn("warn message")
+					logger.Error("error message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "⚠️ WARNING warn message\nERROR error message\n", output)
+				},
+			},
+			{
+				name: "with_source_relative",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						HandlerOptions: slog.HandlerOptions{
+							AddSource: true,
+						},
+						SourcePath:          SourcePathRelative,
+						SourceShortFunction: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message with source")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Regexp(t, `^INFO message with source\n  log/terminal_tree_handler_test\.go:\d+ \(log\.TestTerminalTreeHandler\.func\d+\.\d+\)\n$`, output)
+				},
+			},
+			{
+				name: "hyperlinks_url_value",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						ForceColor:  true,
+						ColorScheme: &TerminalHandlerColorScheme{},
+						Hyperlinks:  true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("link", "url", &url.URL{Scheme: "https", Host: "example.com", Path: "/a b"})
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO link\n  url: \033]8;;https://example.com/a%20b\033\\https://example.com/a%20b\033]8;;\033\\\n", output)
+				},
+			},
+			{
+				name: "hyperlinks_no_color",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:    true,
+						Hyperlinks: true,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("link", "url", &url.URL{Scheme: "https", Host: "example.com", Path: "/a b"})
+				},
+				check: func(t *testing.T, output string) {
+					assert.NotContains(t, output, "\033")
+				},
+			},
+			{
+				name: "hyperlinks_source",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						HandlerOptions: slog.HandlerOptions{
+							AddSource: true,
+						},
+						ForceColor:        true,
+						ColorScheme:       &TerminalHandlerColorScheme{},
+						Hyperlinks:        true,
+						HyperlinkTemplate: "editor://open?file={path}&line={line}",
+						SourcePath:        SourcePathRelative,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message with source")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Regexp(t, `^INFO message with source\n  \033\]8;;editor://open\?file=/.+/log/terminal_tree_handler_test\.go&line=\d+\033\\log/terminal_tree_handler_test\.go:\d+\033\]8;;\033\\ \(.+\)\n$`, output)
+				},
+			},
+			{
+				name: "tree_guides_unicode",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:    true,
+						TreeGuides: TreeGuidesUnicode,
+					})
+					return slog.New(h.WithGroup("server").WithAttrs([]slog.Attr{
+						slog.Int("port", 80),
+					}))
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info(
+						"started",
+						slog.Group("tls", "cert", "c.pem", "key", "k.pem"),
+						"multi", "line1\nline2",
+					)
+					logger.Info("done", "ok", true)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"🏷️ server\n"+
+							"├─ port: 80\n"+
+							"├─ INFO started\n"+
+							"│  ├─ 🏷️ tls\n"+
+							"│  │  ├─ cert: c.pem\n"+
+							"│  │  └─ key: k.pem\n"+
+							"│  └─ multi:\n"+
+							"│       line1\n"+
+							"│       line2\n"+
+							"├─ INFO done\n"+
+							"│  └─ ok: true\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "tree_guides_ascii",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:       true,
+						TreeGuides:    TreeGuidesASCII,
+						MaxGroupAttrs: 2,
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", "a", 1, "b", 2, "c", 3)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"|- a: 1\n"+
+							"|- b: 2\n"+
+							"`- … 1 more attribute\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "group_icon",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor: true,
+						GroupIcon: func(name string) string {
+							if name == "database" {
+								return "[db]"
+							}
+							return ""
+						},
+					})
+					return slog.New(h).WithGroup("database").With("name", "users").WithGroup("server")
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message")
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "[db] database\n  name: users\n  server\n    INFO message\n", output)
+				},
+			},
+			{
+				name: "default_group_icon",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{NoColor: true})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message",
+						slog.Group("1st", "a", 1),
+						slog.Group("#️⃣ channel", "b", 2),
+						slog.Group("🇧🇷 region", "c", 3),
+					)
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(
+						t,
+						"INFO message\n"+
+							"  🏷️ 1st\n"+
+							"    a: 1\n"+
+							"  #️⃣ channel\n"+
+							"    b: 2\n"+
+							"  🇧🇷 region\n"+
+							"    c: 3\n",
+						output,
+					)
+				},
+			},
+			{
+				name: "ascii",
+				setupLogger: func(buf *bytes.Buffer) *slog.Logger {
+					h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+						NoColor:       true,
+						ASCII:         true,
+						TreeGuides:    TreeGuidesUnicode,
+						MaxGroupAttrs: 1,
+						Levels: TerminalLevels{
+							slog.LevelInfo: {Name: "INFO", Emoji: "ℹ️"},
+						},
+					})
+					return slog.New(h)
+				},
+				logFunc: func(logger *slog.Logger) {
+					logger.Info("message", slog.Group("server", "a", 1, "b", 2))
+				},
+				check: func(t *testing.T, output string) {
+					assert.Equal(t, "INFO message\n`- server\n   |- a: 1\n   `- ... 1 more attribute\n", output)
+				},
+			},
 		}
 
 		for _, tt := range tests {
@@ -401,12 +1002,12 @@ $`, output)
 						},
 					},
 					{
-						name: "terminal_valuer_with_all_sequences",
+						name: "terminal_valuer_non_sgr_sequences_removed",
 						value: TestColoredValue{
 							plainText:    "plain text",
 							terminalText: "\033[2J\033[31mred\033[H\033[0m",
 						},
-						expected: "  value: \033[2J\033[31mred\033[H\033[0m\n",
+						expected: "  value: \033[31mred\033[0m\n",
 						logFn: func(logger *slog.Logger) {
 							logger.Info("test message", "value", TestColoredValue{
 								plainText:    "plain text",
@@ -501,6 +1102,21 @@ $`, output)
 				opts:       &TerminalHandlerOptions{ForceColor: true, NoColor: true},
 				wantColors: false,
 			},
+			{
+				name:       "color_mode_always",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeAlways},
+				wantColors: true,
+			},
+			{
+				name:       "no_color_overrides_color_mode",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeAlways, NoColor: true},
+				wantColors: false,
+			},
+			{
+				name:       "force_color_overrides_color_mode",
+				opts:       &TerminalHandlerOptions{ColorMode: ColorModeNever, ForceColor: true},
+				wantColors: true,
+			},
 		}
 
 		for _, tt := range tests {
@@ -517,4 +1133,113 @@ $`, output)
 			})
 		}
 	})
+
+	t.Run("ColorLevel", func(t *testing.T) {
+		colorScheme := &TerminalHandlerColorScheme{
+			LevelInfo: ansi.NewStyle(ansi.RGBColor{R: 255, G: 135, B: 0}.Fg()),
+		}
+		tests := []struct {
+			name       string
+			colorLevel ansi.ColorLevel
+			expected   string
+		}{
+			{
+				name:       "truecolor",
+				colorLevel: ansi.ColorLevelTrueColor,
+				expected:   "\033[38;2;255;135;0mINFO\033[39m test message\n",
+			},
+			{
+				name:       "256",
+				colorLevel: ansi.ColorLevel256,
+				expected:   "\033[38;5;208mINFO\033[39m test message\n",
+			},
+			{
+				name:       "16",
+				colorLevel: ansi.ColorLevel16,
+				expected:   "\033[33mINFO\033[39m test message\n",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+					ForceColor:  true,
+					ColorScheme: colorScheme,
+					ColorLevel:  tt.colorLevel,
+				})
+
+				logger := slog.New(h)
+				logger.Info("test message")
+
+				assert.Equal(t, tt.expected, buf.String())
+			})
+		}
+	})
+
+	t.Run("ValueColor", func(t *testing.T) {
+		colorScheme := &TerminalHandlerColorScheme{
+			AttrValue:       ansi.NewStyle(ansi.FgWhite),
+			AttrValueNumber: ansi.NewStyle(ansi.FgMagenta),
+			AttrValueBool:   ansi.NewStyle(ansi.FgYellow),
+			AttrValueNil:    ansi.NewStyle(ansi.Dim),
+		}
+		tests := []struct {
+			name     string
+			value    any
+			expected string
+		}{
+			{name: "number", value: 42, expected: "\033[35m 42\033[39m"},
+			{name: "bool", value: true, expected: "\033[33m true\033[39m"},
+			{name: "nil", value: nil, expected: "\033[2m <nil>\033[22m"},
+			{name: "empty_string", value: "", expected: "\033[2m \033[22m"},
+			{name: "fallback", value: "text", expected: "\033[37m text\033[39m"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+					ForceColor:  true,
+					ColorScheme: colorScheme,
+				})
+
+				logger := slog.New(h)
+				logger.Info("test message", "key", tt.value)
+
+				assert.Contains(t, buf.String(), tt.expected)
+			})
+		}
+	})
+
+	t.Run("TimeMode", func(t *testing.T) {
+		start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		setProcessStart(t, start)
+
+		buf := &bytes.Buffer{}
+		h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+			NoColor:  true,
+			TimeMode: TimeModeDelta,
+		})
+		logger := slog.New(h.WithAttrs([]slog.Attr{}))
+		require.NoError(t, logger.Handler().Handle(
+			context.Background(), slog.NewRecord(start.Add(time.Second), slog.LevelInfo, "first", 0),
+		))
+		require.NoError(t, h.Handle(
+			context.Background(), slog.NewRecord(start.Add(1250*time.Millisecond), slog.LevelInfo, "second", 0),
+		))
+
+		assert.Equal(t, "INFO first\n  +1.000s\nINFO second\n  +0.250s\n", buf.String())
+	})
+
+	t.Run("TreeGuideColor", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		h := NewTerminalTreeHandler(buf, &TerminalHandlerOptions{
+			ForceColor:  true,
+			ColorScheme: &TerminalHandlerColorScheme{TreeGuide: ansi.NewStyle(ansi.Dim)},
+			TreeGuides:  TreeGuidesUnicode,
+		})
+		slog.New(h).Info("message", "key", "value")
+		assert.Equal(t, "INFO message\n\033[2m└─ \033[22mkey: value\n", buf.String())
+	})
 }
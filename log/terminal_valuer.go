@@ -2,27 +2,28 @@ package log
 
 import (
 	"log/slog"
-	"regexp"
+
+	"github.com/fornellas/slogxt/ansi"
 )
 
 // TerminalValuer is implemented by any value that wants to provide
 // a terminal representation for slogxt terminal handlers.
 //
 // IMPORTANT: Only use Select Graphic Rendition (SGR) escape sequences
-// for colors and text formatting. Other ANSI control sequences (cursor
-// movement, screen clearing, etc.) will disrupt terminal output.
+// for colors and text formatting, and OSC 8 hyperlinks. Other ANSI control
+// sequences (cursor movement, screen clearing, etc.) and control characters
+// other than tab and newline would disrupt terminal output, so terminal
+// handlers remove them; use ansi.Validate to check values.
 type TerminalValuer interface {
 	// TerminalValue returns a slog.Value that should only contain
-	// Select Graphic Rendition (SGR) ANSI escape sequences.
-	// Other control sequences will disrupt terminal display.
+	// Select Graphic Rendition (SGR) ANSI escape sequences and OSC 8
+	// hyperlinks. Other control sequences are removed by terminal handlers.
 	TerminalValue() slog.Value
 }
 
-var ansiRegex = regexp.MustCompile(`\033\[[0-9;]*[A-Za-z]`)
-
 // stripANSI removes all ANSI escape sequences from a string
 func stripANSI(s string) string {
-	return ansiRegex.ReplaceAllString(s, "")
+	return ansi.Strip(s)
 }
 
 // TerminalValue represents a string value that may contain ANSI escape sequences.
@@ -76,6 +76,16 @@ func TestStripANSI(t *testing.T) {
 			input:    "\033[mreset",
 			expected: "reset",
 		},
+		{
+			name:     "hyperlink_stripped",
+			input:    "see \033]8;;https://example.com/\033\\\033[4mexample\033[0m\033]8;;\033\\ page",
+			expected: "see example page",
+		},
+		{
+			name:     "hyperlink_bel_stripped",
+			input:    "\033]8;;https://example.com/\aexample\033]8;;\a",
+			expected: "example",
+		},
 	}
 
 	for _, tt := range tests {
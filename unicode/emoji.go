@@ -2,10 +2,13 @@ package unicode
 
 import (
 	"unicode"
+	"unicode/utf8"
 )
 
-// https://unicode.org/Public/emoji/16.0/emoji-test.txt
-// This RangeTable contains all codepoints that start an emoji.
+// rangeTableEmoji16 contains all code points that start an emoji in Emoji 16.0, from
+// https://unicode.org/Public/emoji/16.0/emoji-test.txt. It is a superset of the generated
+// rangeTableEmoji, which is from an older emoji-test.txt, and can be removed once emoji-test.txt
+// 16.0 is vendored and the tables are regenerated from it.
 var rangeTableEmoji16 = &unicode.RangeTable{
 	R16: []unicode.Range16{
 		{Lo: 0x23, Hi: 0x2a, Stride: 0x7},
@@ -137,3 +140,33 @@ var rangeTableEmoji16 = &unicode.RangeTable{
 func IsEmojiStartCodePoint(r rune) bool {
 	return unicode.In(r, rangeTableEmoji16)
 }
+
+// EmojiPrefix returns the longest emoji sequence at the start of s, eg: a single code point
+// emoji, a keycap, a flag, an emoji with skin tone modifiers or a ZWJ sequence. It returns an
+// empty string if s does not start with an emoji.
+func EmojiPrefix(s string) string {
+	r, _ := utf8.DecodeRuneInString(s)
+	if !IsEmojiStartCodePoint(r) {
+		return ""
+	}
+	var prefix string
+	i := 0
+	for length := 1; length <= maxEmojiSequenceLength && i < len(s); length++ {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if _, ok := emojiSequences[s[:i]]; ok {
+			prefix = s[:i]
+		}
+	}
+	if prefix == "" && !unicode.In(r, rangeTableEmoji) {
+		// emoji newer than emojiSequences
+		_, size := utf8.DecodeRuneInString(s)
+		prefix = s[:size]
+	}
+	return prefix
+}
+
+// HasEmojiPrefix returns true when s starts with an emoji sequence.
+func HasEmojiPrefix(s string) bool {
+	return EmojiPrefix(s) != ""
+}
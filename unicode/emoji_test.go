@@ -0,0 +1,49 @@
+package unicode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEmojiStartCodePoint(t *testing.T) {
+	require.True(t, IsEmojiStartCodePoint('😀'))
+	require.True(t, IsEmojiStartCodePoint('#'))
+	require.True(t, IsEmojiStartCodePoint('\U0001f1e7'))
+	require.False(t, IsEmojiStartCodePoint('a'))
+	require.False(t, IsEmojiStartCodePoint('世'))
+	// Emoji 16.0
+	for _, r := range []rune{'\U0001fae9', '\U0001fac6', '\U0001fabe', '\U0001fadf'} {
+		require.True(t, IsEmojiStartCodePoint(r), "%U", r)
+	}
+}
+
+func TestEmojiPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"text", "server", ""},
+		{"single code point", "😀 happy", "😀"},
+		{"variation selector", "❤️ love", "❤️"},
+		{"unqualified", "❤ love", "❤"},
+		{"keycap", "#️⃣ channel", "#️⃣"},
+		{"keycap start without keycap", "1st", ""},
+		{"hash without keycap", "#channel", ""},
+		{"flag", "🇧🇷 Brazil", "🇧🇷"},
+		{"lone regional indicator", "🇧 B", ""},
+		{"skin tone", "👍🏽 ok", "👍🏽"},
+		{"ZWJ sequence", "👨‍👩‍👧 family", "👨‍👩‍👧"},
+		{"longest match", "👨‍👩‍👧‍👦", "👨‍👩‍👧‍👦"},
+		{"partial ZWJ sequence", "👨‍x", "👨"},
+		{"Emoji 16.0", "\U0001fae9 tired", "\U0001fae9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, EmojiPrefix(tt.s))
+			require.Equal(t, tt.expected != "", HasEmojiPrefix(tt.s))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+//go:build ignore
+
+// gen_emoji generates tables_emoji.go from emoji-test.txt, the Emoji Keyboard/Display Test Data
+// for UTS #51.
+//
+// Usage:
+//
+//	go run gen_emoji.go [-version 15.1] [-input emoji-test.txt]
+//
+// The -input flag accepts either a local file, which defaults to the vendored emoji-test.txt, or
+// a URL, eg: https://www.unicode.org/Public/emoji/15.1/emoji-test.txt. The emoji version is read
+// from the file header, and must match -version.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/rangetable"
+)
+
+var (
+	expectedVersion = flag.String("version", "15.1", "expected emoji version of the input file")
+	input           = flag.String("input", "emoji-test.txt", "emoji-test.txt file or URL")
+	output          = flag.String("output", "tables_emoji.go", "output file")
+)
+
+func open(name string) io.ReadCloser {
+	if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+		resp, err := http.Get(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("%s: %s", name, resp.Status)
+		}
+		return resp.Body
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return f
+}
+
+// parse returns the emoji version and all emoji sequences of any status listed in emoji-test.txt.
+func parse(name string) (string, []string) {
+	r := open(name)
+	defer r.Close()
+	var version string
+	sequences := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "# Version:"); ok {
+			version = strings.TrimSpace(v)
+			continue
+		}
+		line, _, _ = strings.Cut(line, "#")
+		codePoints, _, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+		var sequence strings.Builder
+		for _, codePoint := range strings.Fields(codePoints) {
+			c, err := strconv.ParseUint(codePoint, 16, 32)
+			if err != nil {
+				log.Fatalf("%s: %s: %s", name, scanner.Text(), err)
+			}
+			sequence.WriteRune(rune(c))
+		}
+		sequences = append(sequences, sequence.String())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if version == "" {
+		log.Fatalf("%s: missing version header", name)
+	}
+	return version, sequences
+}
+
+func main() {
+	flag.Parse()
+
+	version, sequences := parse(*input)
+	if version != *expectedVersion {
+		log.Fatalf("%s: emoji version %s, expected %s", *input, version, *expectedVersion)
+	}
+	slices.Sort(sequences)
+	sequences = slices.Compact(sequences)
+
+	starts := []rune{}
+	maxLength := 0
+	for _, sequence := range sequences {
+		r, _ := utf8.DecodeRuneInString(sequence)
+		starts = append(starts, r)
+		maxLength = max(maxLength, utf8.RuneCountInString(sequence))
+	}
+	table := rangetable.New(starts...)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_emoji.go from emoji-test.txt %s. DO NOT EDIT.\n\n", version)
+	fmt.Fprintf(&buf, "package unicode\n\n")
+	fmt.Fprintf(&buf, "import \"unicode\"\n\n")
+	fmt.Fprintf(&buf, "// EmojiVersion is the emoji version from which the emoji tables are generated.\n")
+	fmt.Fprintf(&buf, "const EmojiVersion = %q\n\n", version)
+
+	fmt.Fprintf(&buf, "// This RangeTable contains all code points that start an emoji.\n")
+	fmt.Fprintf(&buf, "var rangeTableEmoji = &unicode.RangeTable{\n")
+	if len(table.R16) > 0 {
+		fmt.Fprintf(&buf, "R16: []unicode.Range16{\n")
+		for _, r := range table.R16 {
+			fmt.Fprintf(&buf, "{Lo: %#x, Hi: %#x, Stride: %#x},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	if len(table.R32) > 0 {
+		fmt.Fprintf(&buf, "R32: []unicode.Range32{\n")
+		for _, r := range table.R32 {
+			fmt.Fprintf(&buf, "{Lo: %#x, Hi: %#x, Stride: %#x},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	if table.LatinOffset > 0 {
+		fmt.Fprintf(&buf, "LatinOffset: %d,\n", table.LatinOffset)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// maxEmojiSequenceLength is the number of code points of the longest emoji sequence.\n")
+	fmt.Fprintf(&buf, "const maxEmojiSequenceLength = %d\n\n", maxLength)
+
+	fmt.Fprintf(&buf, "// emojiSequences contains all fully-qualified, minimally-qualified, unqualified and component\n")
+	fmt.Fprintf(&buf, "// emoji sequences.\n")
+	fmt.Fprintf(&buf, "var emojiSequences = map[string]struct{}{\n")
+	for _, sequence := range sequences {
+		fmt.Fprintf(&buf, "%+q: {},\n", sequence)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,205 @@
+//go:build ignore
+
+// gen_width generates tables_width.go from the Unicode Character Database files
+// EastAsianWidth.txt and extracted/DerivedGeneralCategory.txt.
+//
+// Usage:
+//
+//	go run gen_width.go [-version 15.1.0] [-ucd ucd]
+//
+// The -ucd flag accepts either a local directory, which defaults to ucd/, where the official UCD
+// files are vendored unchanged from https://www.unicode.org/Public/15.1.0/ucd/, or a base URL. The
+// Unicode version is read from the file headers, and must match -version.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/rangetable"
+)
+
+var (
+	version = flag.String("version", "15.1.0", "expected Unicode version of the UCD files")
+	ucd     = flag.String("ucd", "ucd", "directory or base URL of the UCD files")
+	output  = flag.String("output", "tables_width.go", "output file")
+)
+
+// open returns the contents of a UCD file, from a local directory or a URL.
+func open(name string) io.ReadCloser {
+	if strings.HasPrefix(*ucd, "http://") || strings.HasPrefix(*ucd, "https://") {
+		resp, err := http.Get(*ucd + "/" + name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("%s/%s: %s", *ucd, name, resp.Status)
+		}
+		return resp.Body
+	}
+	f, err := os.Open(filepath.Join(*ucd, filepath.FromSlash(name)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Fatalf("%s; vendor it unchanged from https://www.unicode.org/Public/%s/ucd/%s", err, *version, name)
+		}
+		log.Fatal(err)
+	}
+	return f
+}
+
+// fileVersion returns the Unicode version of a UCD file, from its first line, eg:
+// "# EastAsianWidth-15.1.0.txt".
+func fileVersion(name string) string {
+	r := open(name)
+	defer r.Close()
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil {
+		log.Fatalf("%s: %s", name, err)
+	}
+	base := strings.TrimSuffix(filepath.Base(name), ".txt")
+	fileVersion, ok := strings.CutPrefix(strings.TrimSpace(line), "# "+base+"-")
+	if !ok {
+		log.Fatalf("%s: missing version header", name)
+	}
+	return strings.TrimSuffix(fileVersion, ".txt")
+}
+
+// parse calls fn for each code point range and property value of a UCD file.
+func parse(name string, fn func(lo, hi rune, value string)) {
+	r := open(name)
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		codePoints, value, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+		loStr, hiStr, isRange := strings.Cut(strings.TrimSpace(codePoints), "..")
+		if !isRange {
+			hiStr = loStr
+		}
+		lo, err := strconv.ParseUint(loStr, 16, 32)
+		if err != nil {
+			log.Fatalf("%s: %s: %s", name, scanner.Text(), err)
+		}
+		hi, err := strconv.ParseUint(hiStr, 16, 32)
+		if err != nil {
+			log.Fatalf("%s: %s: %s", name, scanner.Text(), err)
+		}
+		fn(rune(lo), rune(hi), strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// defaultWide are the ranges where unlisted code points default to East Asian Wide, as
+// documented at the header of EastAsianWidth.txt.
+var defaultWide = [][2]rune{
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xF900, 0xFAFF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+func main() {
+	flag.Parse()
+
+	for _, name := range []string{"EastAsianWidth.txt", "extracted/DerivedGeneralCategory.txt"} {
+		if v := fileVersion(name); v != *version {
+			log.Fatalf("%s: Unicode version %s, expected %s", name, v, *version)
+		}
+	}
+
+	wide := map[rune]bool{}
+	for _, r := range defaultWide {
+		for c := r[0]; c <= r[1]; c++ {
+			wide[c] = true
+		}
+	}
+	parse("EastAsianWidth.txt", func(lo, hi rune, value string) {
+		for c := lo; c <= hi; c++ {
+			wide[c] = value == "W" || value == "F"
+		}
+	})
+
+	zeroWidth := map[rune]bool{}
+	parse("extracted/DerivedGeneralCategory.txt", func(lo, hi rune, value string) {
+		// Nonspacing and enclosing marks, and format characters, except for the soft hyphen,
+		// which terminals display.
+		if value != "Mn" && value != "Me" && value != "Cf" {
+			return
+		}
+		for c := lo; c <= hi; c++ {
+			if c != 0x00AD {
+				zeroWidth[c] = true
+			}
+		}
+	})
+	// Hangul Jungseong and Jongseong, which combine with a preceding Choseong.
+	for c := rune(0x1160); c <= 0x11FF; c++ {
+		zeroWidth[c] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_width.go from Unicode %s. DO NOT EDIT.\n\n", *version)
+	fmt.Fprintf(&buf, "package unicode\n\n")
+	fmt.Fprintf(&buf, "import \"unicode\"\n\n")
+	fmt.Fprintf(&buf, "// UnicodeWidthVersion is the Unicode version from which the width tables are generated.\n")
+	fmt.Fprintf(&buf, "const UnicodeWidthVersion = %q\n\n", *version)
+	writeTable(&buf, "rangeTableWide", "East Asian Wide (W) and Fullwidth (F) code points.", wide)
+	writeTable(&buf, "rangeTableZeroWidth", "Code points that take no columns: nonspacing and enclosing marks, format characters\n// other than the soft hyphen, and Hangul Jungseong and Jongseong.", zeroWidth)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeTable(w io.Writer, name, doc string, set map[rune]bool) {
+	runes := []rune{}
+	for c := rune(0); c <= unicode.MaxRune; c++ {
+		if set[c] {
+			runes = append(runes, c)
+		}
+	}
+	table := rangetable.New(runes...)
+	fmt.Fprintf(w, "// %s\n", doc)
+	fmt.Fprintf(w, "var %s = &unicode.RangeTable{\n", name)
+	if len(table.R16) > 0 {
+		fmt.Fprintf(w, "R16: []unicode.Range16{\n")
+		for _, r := range table.R16 {
+			fmt.Fprintf(w, "{Lo: %#x, Hi: %#x, Stride: %#x},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(w, "},\n")
+	}
+	if len(table.R32) > 0 {
+		fmt.Fprintf(w, "R32: []unicode.Range32{\n")
+		for _, r := range table.R32 {
+			fmt.Fprintf(w, "{Lo: %#x, Hi: %#x, Stride: %#x},\n", r.Lo, r.Hi, r.Stride)
+		}
+		fmt.Fprintf(w, "},\n")
+	}
+	if table.LatinOffset > 0 {
+		fmt.Fprintf(w, "LatinOffset: %d,\n", table.LatinOffset)
+	}
+	fmt.Fprintf(w, "}\n\n")
+}
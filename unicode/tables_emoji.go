@@ -0,0 +1,5179 @@
+// Code generated by gen_emoji.go from emoji-test.txt 15.1. DO NOT EDIT.
+
+package unicode
+
+import "unicode"
+
+// EmojiVersion is the emoji version from which the emoji tables are generated.
+const EmojiVersion = "15.1"
+
+// This RangeTable contains all code points that start an emoji.
+var rangeTableEmoji = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x23, Hi: 0x2a, Stride: 0x7},
+		{Lo: 0x30, Hi: 0x39, Stride: 0x1},
+		{Lo: 0xa9, Hi: 0xae, Stride: 0x5},
+		{Lo: 0x203c, Hi: 0x2049, Stride: 0xd},
+		{Lo: 0x2122, Hi: 0x2139, Stride: 0x17},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 0x1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 0x1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 0x1},
+		{Lo: 0x2328, Hi: 0x23cf, Stride: 0xa7},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 0x1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 0x1},
+		{Lo: 0x24c2, Hi: 0x25aa, Stride: 0xe8},
+		{Lo: 0x25ab, Hi: 0x25b6, Stride: 0xb},
+		{Lo: 0x25c0, Hi: 0x25fb, Stride: 0x3b},
+		{Lo: 0x25fc, Hi: 0x25fe, Stride: 0x1},
+		{Lo: 0x2600, Hi: 0x2604, Stride: 0x1},
+		{Lo: 0x260e, Hi: 0x2614, Stride: 0x3},
+		{Lo: 0x2615, Hi: 0x2618, Stride: 0x3},
+		{Lo: 0x261d, Hi: 0x2620, Stride: 0x3},
+		{Lo: 0x2622, Hi: 0x2623, Stride: 0x1},
+		{Lo: 0x2626, Hi: 0x262e, Stride: 0x4},
+		{Lo: 0x262f, Hi: 0x2638, Stride: 0x9},
+		{Lo: 0x2639, Hi: 0x263a, Stride: 0x1},
+		{Lo: 0x2640, Hi: 0x2642, Stride: 0x2},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 0x1},
+		{Lo: 0x265f, Hi: 0x2660, Stride: 0x1},
+		{Lo: 0x2663, Hi: 0x2665, Stride: 0x2},
+		{Lo: 0x2666, Hi: 0x2668, Stride: 0x2},
+		{Lo: 0x267b, Hi: 0x267e, Stride: 0x3},
+		{Lo: 0x267f, Hi: 0x2692, Stride: 0x13},
+		{Lo: 0x2693, Hi: 0x2697, Stride: 0x1},
+		{Lo: 0x2699, Hi: 0x269b, Stride: 0x2},
+		{Lo: 0x269c, Hi: 0x26a0, Stride: 0x4},
+		{Lo: 0x26a1, Hi: 0x26a7, Stride: 0x6},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 0x1},
+		{Lo: 0x26b0, Hi: 0x26b1, Stride: 0x1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 0x1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 0x1},
+		{Lo: 0x26c8, Hi: 0x26ce, Stride: 0x6},
+		{Lo: 0x26cf, Hi: 0x26d3, Stride: 0x2},
+		{Lo: 0x26d4, Hi: 0x26e9, Stride: 0x15},
+		{Lo: 0x26ea, Hi: 0x26f0, Stride: 0x6},
+		{Lo: 0x26f1, Hi: 0x26f5, Stride: 0x1},
+		{Lo: 0x26f7, Hi: 0x26fa, Stride: 0x1},
+		{Lo: 0x26fd, Hi: 0x2702, Stride: 0x5},
+		{Lo: 0x2705, Hi: 0x2708, Stride: 0x3},
+		{Lo: 0x2709, Hi: 0x270d, Stride: 0x1},
+		{Lo: 0x270f, Hi: 0x2712, Stride: 0x3},
+		{Lo: 0x2714, Hi: 0x2716, Stride: 0x2},
+		{Lo: 0x271d, Hi: 0x2721, Stride: 0x4},
+		{Lo: 0x2728, Hi: 0x2733, Stride: 0xb},
+		{Lo: 0x2734, Hi: 0x2744, Stride: 0x10},
+		{Lo: 0x2747, Hi: 0x274c, Stride: 0x5},
+		{Lo: 0x274e, Hi: 0x2753, Stride: 0x5},
+		{Lo: 0x2754, Hi: 0x2755, Stride: 0x1},
+		{Lo: 0x2757, Hi: 0x2763, Stride: 0xc},
+		{Lo: 0x2764, Hi: 0x2795, Stride: 0x31},
+		{Lo: 0x2796, Hi: 0x2797, Stride: 0x1},
+		{Lo: 0x27a1, Hi: 0x27bf, Stride: 0xf},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 0x1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 0x1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 0x1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 0x5},
+		{Lo: 0x3030, Hi: 0x303d, Stride: 0xd},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 0x2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f004, Hi: 0x1f0cf, Stride: 0xcb},
+		{Lo: 0x1f170, Hi: 0x1f171, Stride: 0x1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 0x1},
+		{Lo: 0x1f18e, Hi: 0x1f191, Stride: 0x3},
+		{Lo: 0x1f192, Hi: 0x1f19a, Stride: 0x1},
+		{Lo: 0x1f1e6, Hi: 0x1f1ff, Stride: 0x1},
+		{Lo: 0x1f201, Hi: 0x1f202, Stride: 0x1},
+		{Lo: 0x1f21a, Hi: 0x1f22f, Stride: 0x15},
+		{Lo: 0x1f232, Hi: 0x1f23a, Stride: 0x1},
+		{Lo: 0x1f250, Hi: 0x1f251, Stride: 0x1},
+		{Lo: 0x1f300, Hi: 0x1f321, Stride: 0x1},
+		{Lo: 0x1f324, Hi: 0x1f393, Stride: 0x1},
+		{Lo: 0x1f396, Hi: 0x1f397, Stride: 0x1},
+		{Lo: 0x1f399, Hi: 0x1f39b, Stride: 0x1},
+		{Lo: 0x1f39e, Hi: 0x1f3f0, Stride: 0x1},
+		{Lo: 0x1f3f3, Hi: 0x1f3f5, Stride: 0x1},
+		{Lo: 0x1f3f7, Hi: 0x1f4fd, Stride: 0x1},
+		{Lo: 0x1f4ff, Hi: 0x1f53d, Stride: 0x1},
+		{Lo: 0x1f549, Hi: 0x1f54e, Stride: 0x1},
+		{Lo: 0x1f550, Hi: 0x1f567, Stride: 0x1},
+		{Lo: 0x1f56f, Hi: 0x1f570, Stride: 0x1},
+		{Lo: 0x1f573, Hi: 0x1f57a, Stride: 0x1},
+		{Lo: 0x1f587, Hi: 0x1f58a, Stride: 0x3},
+		{Lo: 0x1f58b, Hi: 0x1f58d, Stride: 0x1},
+		{Lo: 0x1f590, Hi: 0x1f595, Stride: 0x5},
+		{Lo: 0x1f596, Hi: 0x1f5a4, Stride: 0xe},
+		{Lo: 0x1f5a5, Hi: 0x1f5a8, Stride: 0x3},
+		{Lo: 0x1f5b1, Hi: 0x1f5b2, Stride: 0x1},
+		{Lo: 0x1f5bc, Hi: 0x1f5c2, Stride: 0x6},
+		{Lo: 0x1f5c3, Hi: 0x1f5c4, Stride: 0x1},
+		{Lo: 0x1f5d1, Hi: 0x1f5d3, Stride: 0x1},
+		{Lo: 0x1f5dc, Hi: 0x1f5de, Stride: 0x1},
+		{Lo: 0x1f5e1, Hi: 0x1f5e3, Stride: 0x2},
+		{Lo: 0x1f5e8, Hi: 0x1f5ef, Stride: 0x7},
+		{Lo: 0x1f5f3, Hi: 0x1f5fa, Stride: 0x7},
+		{Lo: 0x1f5fb, Hi: 0x1f64f, Stride: 0x1},
+		{Lo: 0x1f680, Hi: 0x1f6c5, Stride: 0x1},
+		{Lo: 0x1f6cb, Hi: 0x1f6d2, Stride: 0x1},
+		{Lo: 0x1f6d5, Hi: 0x1f6d7, Stride: 0x1},
+		{Lo: 0x1f6dc, Hi: 0x1f6e5, Stride: 0x1},
+		{Lo: 0x1f6e9, Hi: 0x1f6eb, Stride: 0x2},
+		{Lo: 0x1f6ec, Hi: 0x1f6f0, Stride: 0x4},
+		{Lo: 0x1f6f3, Hi: 0x1f6fc, Stride: 0x1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 0x1},
+		{Lo: 0x1f7f0, Hi: 0x1f90c, Stride: 0x11c},
+		{Lo: 0x1f90d, Hi: 0x1f93a, Stride: 0x1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 0x1},
+		{Lo: 0x1f947, Hi: 0x1f9ff, Stride: 0x1},
+		{Lo: 0x1fa70, Hi: 0x1fa7c, Stride: 0x1},
+		{Lo: 0x1fa80, Hi: 0x1fa88, Stride: 0x1},
+		{Lo: 0x1fa90, Hi: 0x1fabd, Stride: 0x1},
+		{Lo: 0x1fabf, Hi: 0x1fac5, Stride: 0x1},
+		{Lo: 0x1face, Hi: 0x1fadb, Stride: 0x1},
+		{Lo: 0x1fae0, Hi: 0x1fae8, Stride: 0x1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 0x1},
+	},
+	LatinOffset: 3,
+}
+
+// maxEmojiSequenceLength is the number of code points of the longest emoji sequence.
+const maxEmojiSequenceLength = 10
+
+// emojiSequences contains all fully-qualified, minimally-qualified, unqualified and component
+// emoji sequences.
+var emojiSequences = map[string]struct{}{
+	"#\u20e3":                            {},
+	"#\ufe0f\u20e3":                      {},
+	"*\u20e3":                            {},
+	"*\ufe0f\u20e3":                      {},
+	"0\u20e3":                            {},
+	"0\ufe0f\u20e3":                      {},
+	"1\u20e3":                            {},
+	"1\ufe0f\u20e3":                      {},
+	"2\u20e3":                            {},
+	"2\ufe0f\u20e3":                      {},
+	"3\u20e3":                            {},
+	"3\ufe0f\u20e3":                      {},
+	"4\u20e3":                            {},
+	"4\ufe0f\u20e3":                      {},
+	"5\u20e3":                            {},
+	"5\ufe0f\u20e3":                      {},
+	"6\u20e3":                            {},
+	"6\ufe0f\u20e3":                      {},
+	"7\u20e3":                            {},
+	"7\ufe0f\u20e3":                      {},
+	"8\u20e3":                            {},
+	"8\ufe0f\u20e3":                      {},
+	"9\u20e3":                            {},
+	"9\ufe0f\u20e3":                      {},
+	"\u00a9":                             {},
+	"\u00a9\ufe0f":                       {},
+	"\u00ae":                             {},
+	"\u00ae\ufe0f":                       {},
+	"\u203c":                             {},
+	"\u203c\ufe0f":                       {},
+	"\u2049":                             {},
+	"\u2049\ufe0f":                       {},
+	"\u2122":                             {},
+	"\u2122\ufe0f":                       {},
+	"\u2139":                             {},
+	"\u2139\ufe0f":                       {},
+	"\u2194":                             {},
+	"\u2194\ufe0f":                       {},
+	"\u2195":                             {},
+	"\u2195\ufe0f":                       {},
+	"\u2196":                             {},
+	"\u2196\ufe0f":                       {},
+	"\u2197":                             {},
+	"\u2197\ufe0f":                       {},
+	"\u2198":                             {},
+	"\u2198\ufe0f":                       {},
+	"\u2199":                             {},
+	"\u2199\ufe0f":                       {},
+	"\u21a9":                             {},
+	"\u21a9\ufe0f":                       {},
+	"\u21aa":                             {},
+	"\u21aa\ufe0f":                       {},
+	"\u231a":                             {},
+	"\u231b":                             {},
+	"\u2328":                             {},
+	"\u2328\ufe0f":                       {},
+	"\u23cf":                             {},
+	"\u23cf\ufe0f":                       {},
+	"\u23e9":                             {},
+	"\u23ea":                             {},
+	"\u23eb":                             {},
+	"\u23ec":                             {},
+	"\u23ed":                             {},
+	"\u23ed\ufe0f":                       {},
+	"\u23ee":                             {},
+	"\u23ee\ufe0f":                       {},
+	"\u23ef":                             {},
+	"\u23ef\ufe0f":                       {},
+	"\u23f0":                             {},
+	"\u23f1":                             {},
+	"\u23f1\ufe0f":                       {},
+	"\u23f2":                             {},
+	"\u23f2\ufe0f":                       {},
+	"\u23f3":                             {},
+	"\u23f8":                             {},
+	"\u23f8\ufe0f":                       {},
+	"\u23f9":                             {},
+	"\u23f9\ufe0f":                       {},
+	"\u23fa":                             {},
+	"\u23fa\ufe0f":                       {},
+	"\u24c2":                             {},
+	"\u24c2\ufe0f":                       {},
+	"\u25aa":                             {},
+	"\u25aa\ufe0f":                       {},
+	"\u25ab":                             {},
+	"\u25ab\ufe0f":                       {},
+	"\u25b6":                             {},
+	"\u25b6\ufe0f":                       {},
+	"\u25c0":                             {},
+	"\u25c0\ufe0f":                       {},
+	"\u25fb":                             {},
+	"\u25fb\ufe0f":                       {},
+	"\u25fc":                             {},
+	"\u25fc\ufe0f":                       {},
+	"\u25fd":                             {},
+	"\u25fe":                             {},
+	"\u2600":                             {},
+	"\u2600\ufe0f":                       {},
+	"\u2601":                             {},
+	"\u2601\ufe0f":                       {},
+	"\u2602":                             {},
+	"\u2602\ufe0f":                       {},
+	"\u2603":                             {},
+	"\u2603\ufe0f":                       {},
+	"\u2604":                             {},
+	"\u2604\ufe0f":                       {},
+	"\u260e":                             {},
+	"\u260e\ufe0f":                       {},
+	"\u2611":                             {},
+	"\u2611\ufe0f":                       {},
+	"\u2614":                             {},
+	"\u2615":                             {},
+	"\u2618":                             {},
+	"\u2618\ufe0f":                       {},
+	"\u261d":                             {},
+	"\u261d\ufe0f":                       {},
+	"\u261d\U0001f3fb":                   {},
+	"\u261d\U0001f3fc":                   {},
+	"\u261d\U0001f3fd":                   {},
+	"\u261d\U0001f3fe":                   {},
+	"\u261d\U0001f3ff":                   {},
+	"\u2620":                             {},
+	"\u2620\ufe0f":                       {},
+	"\u2622":                             {},
+	"\u2622\ufe0f":                       {},
+	"\u2623":                             {},
+	"\u2623\ufe0f":                       {},
+	"\u2626":                             {},
+	"\u2626\ufe0f":                       {},
+	"\u262a":                             {},
+	"\u262a\ufe0f":                       {},
+	"\u262e":                             {},
+	"\u262e\ufe0f":                       {},
+	"\u262f":                             {},
+	"\u262f\ufe0f":                       {},
+	"\u2638":                             {},
+	"\u2638\ufe0f":                       {},
+	"\u2639":                             {},
+	"\u2639\ufe0f":                       {},
+	"\u263a":                             {},
+	"\u263a\ufe0f":                       {},
+	"\u2640":                             {},
+	"\u2640\ufe0f":                       {},
+	"\u2642":                             {},
+	"\u2642\ufe0f":                       {},
+	"\u2648":                             {},
+	"\u2649":                             {},
+	"\u264a":                             {},
+	"\u264b":                             {},
+	"\u264c":                             {},
+	"\u264d":                             {},
+	"\u264e":                             {},
+	"\u264f":                             {},
+	"\u2650":                             {},
+	"\u2651":                             {},
+	"\u2652":                             {},
+	"\u2653":                             {},
+	"\u265f":                             {},
+	"\u265f\ufe0f":                       {},
+	"\u2660":                             {},
+	"\u2660\ufe0f":                       {},
+	"\u2663":                             {},
+	"\u2663\ufe0f":                       {},
+	"\u2665":                             {},
+	"\u2665\ufe0f":                       {},
+	"\u2666":                             {},
+	"\u2666\ufe0f":                       {},
+	"\u2668":                             {},
+	"\u2668\ufe0f":                       {},
+	"\u267b":                             {},
+	"\u267b\ufe0f":                       {},
+	"\u267e":                             {},
+	"\u267e\ufe0f":                       {},
+	"\u267f":                             {},
+	"\u2692":                             {},
+	"\u2692\ufe0f":                       {},
+	"\u2693":                             {},
+	"\u2694":                             {},
+	"\u2694\ufe0f":                       {},
+	"\u2695":                             {},
+	"\u2695\ufe0f":                       {},
+	"\u2696":                             {},
+	"\u2696\ufe0f":                       {},
+	"\u2697":                             {},
+	"\u2697\ufe0f":                       {},
+	"\u2699":                             {},
+	"\u2699\ufe0f":                       {},
+	"\u269b":                             {},
+	"\u269b\ufe0f":                       {},
+	"\u269c":                             {},
+	"\u269c\ufe0f":                       {},
+	"\u26a0":                             {},
+	"\u26a0\ufe0f":                       {},
+	"\u26a1":                             {},
+	"\u26a7":                             {},
+	"\u26a7\ufe0f":                       {},
+	"\u26aa":                             {},
+	"\u26ab":                             {},
+	"\u26b0":                             {},
+	"\u26b0\ufe0f":                       {},
+	"\u26b1":                             {},
+	"\u26b1\ufe0f":                       {},
+	"\u26bd":                             {},
+	"\u26be":                             {},
+	"\u26c4":                             {},
+	"\u26c5":                             {},
+	"\u26c8":                             {},
+	"\u26c8\ufe0f":                       {},
+	"\u26ce":                             {},
+	"\u26cf":                             {},
+	"\u26cf\ufe0f":                       {},
+	"\u26d1":                             {},
+	"\u26d1\ufe0f":                       {},
+	"\u26d3":                             {},
+	"\u26d3\u200d\U0001f4a5":             {},
+	"\u26d3\ufe0f":                       {},
+	"\u26d3\ufe0f\u200d\U0001f4a5":       {},
+	"\u26d4":                             {},
+	"\u26e9":                             {},
+	"\u26e9\ufe0f":                       {},
+	"\u26ea":                             {},
+	"\u26f0":                             {},
+	"\u26f0\ufe0f":                       {},
+	"\u26f1":                             {},
+	"\u26f1\ufe0f":                       {},
+	"\u26f2":                             {},
+	"\u26f3":                             {},
+	"\u26f4":                             {},
+	"\u26f4\ufe0f":                       {},
+	"\u26f5":                             {},
+	"\u26f7":                             {},
+	"\u26f7\ufe0f":                       {},
+	"\u26f8":                             {},
+	"\u26f8\ufe0f":                       {},
+	"\u26f9":                             {},
+	"\u26f9\u200d\u2640":                 {},
+	"\u26f9\u200d\u2640\ufe0f":           {},
+	"\u26f9\u200d\u2642":                 {},
+	"\u26f9\u200d\u2642\ufe0f":           {},
+	"\u26f9\ufe0f":                       {},
+	"\u26f9\ufe0f\u200d\u2640":           {},
+	"\u26f9\ufe0f\u200d\u2640\ufe0f":     {},
+	"\u26f9\ufe0f\u200d\u2642":           {},
+	"\u26f9\ufe0f\u200d\u2642\ufe0f":     {},
+	"\u26f9\U0001f3fb":                   {},
+	"\u26f9\U0001f3fb\u200d\u2640":       {},
+	"\u26f9\U0001f3fb\u200d\u2640\ufe0f": {},
+	"\u26f9\U0001f3fb\u200d\u2642":       {},
+	"\u26f9\U0001f3fb\u200d\u2642\ufe0f": {},
+	"\u26f9\U0001f3fc":                   {},
+	"\u26f9\U0001f3fc\u200d\u2640":       {},
+	"\u26f9\U0001f3fc\u200d\u2640\ufe0f": {},
+	"\u26f9\U0001f3fc\u200d\u2642":       {},
+	"\u26f9\U0001f3fc\u200d\u2642\ufe0f": {},
+	"\u26f9\U0001f3fd":                   {},
+	"\u26f9\U0001f3fd\u200d\u2640":       {},
+	"\u26f9\U0001f3fd\u200d\u2640\ufe0f": {},
+	"\u26f9\U0001f3fd\u200d\u2642":       {},
+	"\u26f9\U0001f3fd\u200d\u2642\ufe0f": {},
+	"\u26f9\U0001f3fe":                   {},
+	"\u26f9\U0001f3fe\u200d\u2640":       {},
+	"\u26f9\U0001f3fe\u200d\u2640\ufe0f": {},
+	"\u26f9\U0001f3fe\u200d\u2642":       {},
+	"\u26f9\U0001f3fe\u200d\u2642\ufe0f": {},
+	"\u26f9\U0001f3ff":                   {},
+	"\u26f9\U0001f3ff\u200d\u2640":       {},
+	"\u26f9\U0001f3ff\u200d\u2640\ufe0f": {},
+	"\u26f9\U0001f3ff\u200d\u2642":       {},
+	"\u26f9\U0001f3ff\u200d\u2642\ufe0f": {},
+	"\u26fa":                             {},
+	"\u26fd":                             {},
+	"\u2702":                             {},
+	"\u2702\ufe0f":                       {},
+	"\u2705":                             {},
+	"\u2708":                             {},
+	"\u2708\ufe0f":                       {},
+	"\u2709":                             {},
+	"\u2709\ufe0f":                       {},
+	"\u270a":                             {},
+	"\u270a\U0001f3fb":                   {},
+	"\u270a\U0001f3fc":                   {},
+	"\u270a\U0001f3fd":                   {},
+	"\u270a\U0001f3fe":                   {},
+	"\u270a\U0001f3ff":                   {},
+	"\u270b":                             {},
+	"\u270b\U0001f3fb":                   {},
+	"\u270b\U0001f3fc":                   {},
+	"\u270b\U0001f3fd":                   {},
+	"\u270b\U0001f3fe":                   {},
+	"\u270b\U0001f3ff":                   {},
+	"\u270c":                             {},
+	"\u270c\ufe0f":                       {},
+	"\u270c\U0001f3fb":                   {},
+	"\u270c\U0001f3fc":                   {},
+	"\u270c\U0001f3fd":                   {},
+	"\u270c\U0001f3fe":                   {},
+	"\u270c\U0001f3ff":                   {},
+	"\u270d":                             {},
+	"\u270d\ufe0f":                       {},
+	"\u270d\U0001f3fb":                   {},
+	"\u270d\U0001f3fc":                   {},
+	"\u270d\U0001f3fd":                   {},
+	"\u270d\U0001f3fe":                   {},
+	"\u270d\U0001f3ff":                   {},
+	"\u270f":                             {},
+	"\u270f\ufe0f":                       {},
+	"\u2712":                             {},
+	"\u2712\ufe0f":                       {},
+	"\u2714":                             {},
+	"\u2714\ufe0f":                       {},
+	"\u2716":                             {},
+	"\u2716\ufe0f":                       {},
+	"\u271d":                             {},
+	"\u271d\ufe0f":                       {},
+	"\u2721":                             {},
+	"\u2721\ufe0f":                       {},
+	"\u2728":                             {},
+	"\u2733":                             {},
+	"\u2733\ufe0f":                       {},
+	"\u2734":                             {},
+	"\u2734\ufe0f":                       {},
+	"\u2744":                             {},
+	"\u2744\ufe0f":                       {},
+	"\u2747":                             {},
+	"\u2747\ufe0f":                       {},
+	"\u274c":                             {},
+	"\u274e":                             {},
+	"\u2753":                             {},
+	"\u2754":                             {},
+	"\u2755":                             {},
+	"\u2757":                             {},
+	"\u2763":                             {},
+	"\u2763\ufe0f":                       {},
+	"\u2764":                             {},
+	"\u2764\u200d\U0001f525":             {},
+	"\u2764\u200d\U0001fa79":             {},
+	"\u2764\ufe0f":                       {},
+	"\u2764\ufe0f\u200d\U0001f525":       {},
+	"\u2764\ufe0f\u200d\U0001fa79":       {},
+	"\u2795":                             {},
+	"\u2796":                             {},
+	"\u2797":                             {},
+	"\u27a1":                             {},
+	"\u27a1\ufe0f":                       {},
+	"\u27b0":                             {},
+	"\u27bf":                             {},
+	"\u2934":                             {},
+	"\u2934\ufe0f":                       {},
+	"\u2935":                             {},
+	"\u2935\ufe0f":                       {},
+	"\u2b05":                             {},
+	"\u2b05\ufe0f":                       {},
+	"\u2b06":                             {},
+	"\u2b06\ufe0f":                       {},
+	"\u2b07":                             {},
+	"\u2b07\ufe0f":                       {},
+	"\u2b1b":                             {},
+	"\u2b1c":                             {},
+	"\u2b50":                             {},
+	"\u2b55":                             {},
+	"\u3030":                             {},
+	"\u3030\ufe0f":                       {},
+	"\u303d":                             {},
+	"\u303d\ufe0f":                       {},
+	"\u3297":                             {},
+	"\u3297\ufe0f":                       {},
+	"\u3299":                             {},
+	"\u3299\ufe0f":                       {},
+	"\U0001f004":                         {},
+	"\U0001f0cf":                         {},
+	"\U0001f170":                         {},
+	"\U0001f170\ufe0f":                   {},
+	"\U0001f171":                         {},
+	"\U0001f171\ufe0f":                   {},
+	"\U0001f17e":                         {},
+	"\U0001f17e\ufe0f":                   {},
+	"\U0001f17f":                         {},
+	"\U0001f17f\ufe0f":                   {},
+	"\U0001f18e":                         {},
+	"\U0001f191":                         {},
+	"\U0001f192":                         {},
+	"\U0001f193":                         {},
+	"\U0001f194":                         {},
+	"\U0001f195":                         {},
+	"\U0001f196":                         {},
+	"\U0001f197":                         {},
+	"\U0001f198":                         {},
+	"\U0001f199":                         {},
+	"\U0001f19a":                         {},
+	"\U0001f1e6\U0001f1e8":               {},
+	"\U0001f1e6\U0001f1e9":               {},
+	"\U0001f1e6\U0001f1ea":               {},
+	"\U0001f1e6\U0001f1eb":               {},
+	"\U0001f1e6\U0001f1ec":               {},
+	"\U0001f1e6\U0001f1ee":               {},
+	"\U0001f1e6\U0001f1f1":               {},
+	"\U0001f1e6\U0001f1f2":               {},
+	"\U0001f1e6\U0001f1f4":               {},
+	"\U0001f1e6\U0001f1f6":               {},
+	"\U0001f1e6\U0001f1f7":               {},
+	"\U0001f1e6\U0001f1f8":               {},
+	"\U0001f1e6\U0001f1f9":               {},
+	"\U0001f1e6\U0001f1fa":               {},
+	"\U0001f1e6\U0001f1fc":               {},
+	"\U0001f1e6\U0001f1fd":               {},
+	"\U0001f1e6\U0001f1ff":               {},
+	"\U0001f1e7\U0001f1e6":               {},
+	"\U0001f1e7\U0001f1e7":               {},
+	"\U0001f1e7\U0001f1e9":               {},
+	"\U0001f1e7\U0001f1ea":               {},
+	"\U0001f1e7\U0001f1eb":               {},
+	"\U0001f1e7\U0001f1ec":               {},
+	"\U0001f1e7\U0001f1ed":               {},
+	"\U0001f1e7\U0001f1ee":               {},
+	"\U0001f1e7\U0001f1ef":               {},
+	"\U0001f1e7\U0001f1f1":               {},
+	"\U0001f1e7\U0001f1f2":               {},
+	"\U0001f1e7\U0001f1f3":               {},
+	"\U0001f1e7\U0001f1f4":               {},
+	"\U0001f1e7\U0001f1f6":               {},
+	"\U0001f1e7\U0001f1f7":               {},
+	"\U0001f1e7\U0001f1f8":               {},
+	"\U0001f1e7\U0001f1f9":               {},
+	"\U0001f1e7\U0001f1fb":               {},
+	"\U0001f1e7\U0001f1fc":               {},
+	"\U0001f1e7\U0001f1fe":               {},
+	"\U0001f1e7\U0001f1ff":               {},
+	"\U0001f1e8\U0001f1e6":               {},
+	"\U0001f1e8\U0001f1e8":               {},
+	"\U0001f1e8\U0001f1e9":               {},
+	"\U0001f1e8\U0001f1eb":               {},
+	"\U0001f1e8\U0001f1ec":               {},
+	"\U0001f1e8\U0001f1ed":               {},
+	"\U0001f1e8\U0001f1ee":               {},
+	"\U0001f1e8\U0001f1f0":               {},
+	"\U0001f1e8\U0001f1f1":               {},
+	"\U0001f1e8\U0001f1f2":               {},
+	"\U0001f1e8\U0001f1f3":               {},
+	"\U0001f1e8\U0001f1f4":               {},
+	"\U0001f1e8\U0001f1f5":               {},
+	"\U0001f1e8\U0001f1f7":               {},
+	"\U0001f1e8\U0001f1fa":               {},
+	"\U0001f1e8\U0001f1fb":               {},
+	"\U0001f1e8\U0001f1fc":               {},
+	"\U0001f1e8\U0001f1fd":               {},
+	"\U0001f1e8\U0001f1fe":               {},
+	"\U0001f1e8\U0001f1ff":               {},
+	"\U0001f1e9\U0001f1ea":               {},
+	"\U0001f1e9\U0001f1ec":               {},
+	"\U0001f1e9\U0001f1ef":               {},
+	"\U0001f1e9\U0001f1f0":               {},
+	"\U0001f1e9\U0001f1f2":               {},
+	"\U0001f1e9\U0001f1f4":               {},
+	"\U0001f1e9\U0001f1ff":               {},
+	"\U0001f1ea\U0001f1e6":               {},
+	"\U0001f1ea\U0001f1e8":               {},
+	"\U0001f1ea\U0001f1ea":               {},
+	"\U0001f1ea\U0001f1ec":               {},
+	"\U0001f1ea\U0001f1ed":               {},
+	"\U0001f1ea\U0001f1f7":               {},
+	"\U0001f1ea\U0001f1f8":               {},
+	"\U0001f1ea\U0001f1f9":               {},
+	"\U0001f1ea\U0001f1fa":               {},
+	"\U0001f1eb\U0001f1ee":               {},
+	"\U0001f1eb\U0001f1ef":               {},
+	"\U0001f1eb\U0001f1f0":               {},
+	"\U0001f1eb\U0001f1f2":               {},
+	"\U0001f1eb\U0001f1f4":               {},
+	"\U0001f1eb\U0001f1f7":               {},
+	"\U0001f1ec\U0001f1e6":               {},
+	"\U0001f1ec\U0001f1e7":               {},
+	"\U0001f1ec\U0001f1e9":               {},
+	"\U0001f1ec\U0001f1ea":               {},
+	"\U0001f1ec\U0001f1eb":               {},
+	"\U0001f1ec\U0001f1ec":               {},
+	"\U0001f1ec\U0001f1ed":               {},
+	"\U0001f1ec\U0001f1ee":               {},
+	"\U0001f1ec\U0001f1f1":               {},
+	"\U0001f1ec\U0001f1f2":               {},
+	"\U0001f1ec\U0001f1f3":               {},
+	"\U0001f1ec\U0001f1f5":               {},
+	"\U0001f1ec\U0001f1f6":               {},
+	"\U0001f1ec\U0001f1f7":               {},
+	"\U0001f1ec\U0001f1f8":               {},
+	"\U0001f1ec\U0001f1f9":               {},
+	"\U0001f1ec\U0001f1fa":               {},
+	"\U0001f1ec\U0001f1fc":               {},
+	"\U0001f1ec\U0001f1fe":               {},
+	"\U0001f1ed\U0001f1f0":               {},
+	"\U0001f1ed\U0001f1f2":               {},
+	"\U0001f1ed\U0001f1f3":               {},
+	"\U0001f1ed\U0001f1f7":               {},
+	"\U0001f1ed\U0001f1f9":               {},
+	"\U0001f1ed\U0001f1fa":               {},
+	"\U0001f1ee\U0001f1e8":               {},
+	"\U0001f1ee\U0001f1e9":               {},
+	"\U0001f1ee\U0001f1ea":               {},
+	"\U0001f1ee\U0001f1f1":               {},
+	"\U0001f1ee\U0001f1f2":               {},
+	"\U0001f1ee\U0001f1f3":               {},
+	"\U0001f1ee\U0001f1f4":               {},
+	"\U0001f1ee\U0001f1f6":               {},
+	"\U0001f1ee\U0001f1f7":               {},
+	"\U0001f1ee\U0001f1f8":               {},
+	"\U0001f1ee\U0001f1f9":               {},
+	"\U0001f1ef\U0001f1ea":               {},
+	"\U0001f1ef\U0001f1f2":               {},
+	"\U0001f1ef\U0001f1f4":               {},
+	"\U0001f1ef\U0001f1f5":               {},
+	"\U0001f1f0\U0001f1ea":               {},
+	"\U0001f1f0\U0001f1ec":               {},
+	"\U0001f1f0\U0001f1ed":               {},
+	"\U0001f1f0\U0001f1ee":               {},
+	"\U0001f1f0\U0001f1f2":               {},
+	"\U0001f1f0\U0001f1f3":               {},
+	"\U0001f1f0\U0001f1f5":               {},
+	"\U0001f1f0\U0001f1f7":               {},
+	"\U0001f1f0\U0001f1fc":               {},
+	"\U0001f1f0\U0001f1fe":               {},
+	"\U0001f1f0\U0001f1ff":               {},
+	"\U0001f1f1\U0001f1e6":               {},
+	"\U0001f1f1\U0001f1e7":               {},
+	"\U0001f1f1\U0001f1e8":               {},
+	"\U0001f1f1\U0001f1ee":               {},
+	"\U0001f1f1\U0001f1f0":               {},
+	"\U0001f1f1\U0001f1f7":               {},
+	"\U0001f1f1\U0001f1f8":               {},
+	"\U0001f1f1\U0001f1f9":               {},
+	"\U0001f1f1\U0001f1fa":               {},
+	"\U0001f1f1\U0001f1fb":               {},
+	"\U0001f1f1\U0001f1fe":               {},
+	"\U0001f1f2\U0001f1e6":               {},
+	"\U0001f1f2\U0001f1e8":               {},
+	"\U0001f1f2\U0001f1e9":               {},
+	"\U0001f1f2\U0001f1ea":               {},
+	"\U0001f1f2\U0001f1eb":               {},
+	"\U0001f1f2\U0001f1ec":               {},
+	"\U0001f1f2\U0001f1ed":               {},
+	"\U0001f1f2\U0001f1f0":               {},
+	"\U0001f1f2\U0001f1f1":               {},
+	"\U0001f1f2\U0001f1f2":               {},
+	"\U0001f1f2\U0001f1f3":               {},
+	"\U0001f1f2\U0001f1f4":               {},
+	"\U0001f1f2\U0001f1f5":               {},
+	"\U0001f1f2\U0001f1f6":               {},
+	"\U0001f1f2\U0001f1f7":               {},
+	"\U0001f1f2\U0001f1f8":               {},
+	"\U0001f1f2\U0001f1f9":               {},
+	"\U0001f1f2\U0001f1fa":               {},
+	"\U0001f1f2\U0001f1fb":               {},
+	"\U0001f1f2\U0001f1fc":               {},
+	"\U0001f1f2\U0001f1fd":               {},
+	"\U0001f1f2\U0001f1fe":               {},
+	"\U0001f1f2\U0001f1ff":               {},
+	"\U0001f1f3\U0001f1e6":               {},
+	"\U0001f1f3\U0001f1e8":               {},
+	"\U0001f1f3\U0001f1ea":               {},
+	"\U0001f1f3\U0001f1eb":               {},
+	"\U0001f1f3\U0001f1ec":               {},
+	"\U0001f1f3\U0001f1ee":               {},
+	"\U0001f1f3\U0001f1f1":               {},
+	"\U0001f1f3\U0001f1f4":               {},
+	"\U0001f1f3\U0001f1f5":               {},
+	"\U0001f1f3\U0001f1f7":               {},
+	"\U0001f1f3\U0001f1fa":               {},
+	"\U0001f1f3\U0001f1ff":               {},
+	"\U0001f1f4\U0001f1f2":               {},
+	"\U0001f1f5\U0001f1e6":               {},
+	"\U0001f1f5\U0001f1ea":               {},
+	"\U0001f1f5\U0001f1eb":               {},
+	"\U0001f1f5\U0001f1ec":               {},
+	"\U0001f1f5\U0001f1ed":               {},
+	"\U0001f1f5\U0001f1f0":               {},
+	"\U0001f1f5\U0001f1f1":               {},
+	"\U0001f1f5\U0001f1f2":               {},
+	"\U0001f1f5\U0001f1f3":               {},
+	"\U0001f1f5\U0001f1f7":               {},
+	"\U0001f1f5\U0001f1f8":               {},
+	"\U0001f1f5\U0001f1f9":               {},
+	"\U0001f1f5\U0001f1fc":               {},
+	"\U0001f1f5\U0001f1fe":               {},
+	"\U0001f1f6\U0001f1e6":               {},
+	"\U0001f1f7\U0001f1ea":               {},
+	"\U0001f1f7\U0001f1f4":               {},
+	"\U0001f1f7\U0001f1f8":               {},
+	"\U0001f1f7\U0001f1fa":               {},
+	"\U0001f1f7\U0001f1fc":               {},
+	"\U0001f1f8\U0001f1e6":               {},
+	"\U0001f1f8\U0001f1e7":               {},
+	"\U0001f1f8\U0001f1e8":               {},
+	"\U0001f1f8\U0001f1e9":               {},
+	"\U0001f1f8\U0001f1ea":               {},
+	"\U0001f1f8\U0001f1ec":               {},
+	"\U0001f1f8\U0001f1ed":               {},
+	"\U0001f1f8\U0001f1ee":               {},
+	"\U0001f1f8\U0001f1ef":               {},
+	"\U0001f1f8\U0001f1f0":               {},
+	"\U0001f1f8\U0001f1f1":               {},
+	"\U0001f1f8\U0001f1f2":               {},
+	"\U0001f1f8\U0001f1f3":               {},
+	"\U0001f1f8\U0001f1f4":               {},
+	"\U0001f1f8\U0001f1f7":               {},
+	"\U0001f1f8\U0001f1f8":               {},
+	"\U0001f1f8\U0001f1f9":               {},
+	"\U0001f1f8\U0001f1fb":               {},
+	"\U0001f1f8\U0001f1fd":               {},
+	"\U0001f1f8\U0001f1fe":               {},
+	"\U0001f1f8\U0001f1ff":               {},
+	"\U0001f1f9\U0001f1e6":               {},
+	"\U0001f1f9\U0001f1e8":               {},
+	"\U0001f1f9\U0001f1e9":               {},
+	"\U0001f1f9\U0001f1eb":               {},
+	"\U0001f1f9\U0001f1ec":               {},
+	"\U0001f1f9\U0001f1ed":               {},
+	"\U0001f1f9\U0001f1ef":               {},
+	"\U0001f1f9\U0001f1f0":               {},
+	"\U0001f1f9\U0001f1f1":               {},
+	"\U0001f1f9\U0001f1f2":               {},
+	"\U0001f1f9\U0001f1f3":               {},
+	"\U0001f1f9\U0001f1f4":               {},
+	"\U0001f1f9\U0001f1f7":               {},
+	"\U0001f1f9\U0001f1f9":               {},
+	"\U0001f1f9\U0001f1fb":               {},
+	"\U0001f1f9\U0001f1fc":               {},
+	"\U0001f1f9\U0001f1ff":               {},
+	"\U0001f1fa\U0001f1e6":               {},
+	"\U0001f1fa\U0001f1ec":               {},
+	"\U0001f1fa\U0001f1f2":               {},
+	"\U0001f1fa\U0001f1f3":               {},
+	"\U0001f1fa\U0001f1f8":               {},
+	"\U0001f1fa\U0001f1fe":               {},
+	"\U0001f1fa\U0001f1ff":               {},
+	"\U0001f1fb\U0001f1e6":               {},
+	"\U0001f1fb\U0001f1e8":               {},
+	"\U0001f1fb\U0001f1ea":               {},
+	"\U0001f1fb\U0001f1ec":               {},
+	"\U0001f1fb\U0001f1ee":               {},
+	"\U0001f1fb\U0001f1f3":               {},
+	"\U0001f1fb\U0001f1fa":               {},
+	"\U0001f1fc\U0001f1eb":               {},
+	"\U0001f1fc\U0001f1f8":               {},
+	"\U0001f1fd\U0001f1f0":               {},
+	"\U0001f1fe\U0001f1ea":               {},
+	"\U0001f1fe\U0001f1f9":               {},
+	"\U0001f1ff\U0001f1e6":               {},
+	"\U0001f1ff\U0001f1f2":               {},
+	"\U0001f1ff\U0001f1fc":               {},
+	"\U0001f201":                         {},
+	"\U0001f202":                         {},
+	"\U0001f202\ufe0f":                   {},
+	"\U0001f21a":                         {},
+	"\U0001f22f":                         {},
+	"\U0001f232":                         {},
+	"\U0001f233":                         {},
+	"\U0001f234":                         {},
+	"\U0001f235":                         {},
+	"\U0001f236":                         {},
+	"\U0001f237":                         {},
+	"\U0001f237\ufe0f":                   {},
+	"\U0001f238":                         {},
+	"\U0001f239":                         {},
+	"\U0001f23a":                         {},
+	"\U0001f250":                         {},
+	"\U0001f251":                         {},
+	"\U0001f300":                         {},
+	"\U0001f301":                         {},
+	"\U0001f302":                         {},
+	"\U0001f303":                         {},
+	"\U0001f304":                         {},
+	"\U0001f305":                         {},
+	"\U0001f306":                         {},
+	"\U0001f307":                         {},
+	"\U0001f308":                         {},
+	"\U0001f309":                         {},
+	"\U0001f30a":                         {},
+	"\U0001f30b":                         {},
+	"\U0001f30c":                         {},
+	"\U0001f30d":                         {},
+	"\U0001f30e":                         {},
+	"\U0001f30f":                         {},
+	"\U0001f310":                         {},
+	"\U0001f311":                         {},
+	"\U0001f312":                         {},
+	"\U0001f313":                         {},
+	"\U0001f314":                         {},
+	"\U0001f315":                         {},
+	"\U0001f316":                         {},
+	"\U0001f317":                         {},
+	"\U0001f318":                         {},
+	"\U0001f319":                         {},
+	"\U0001f31a":                         {},
+	"\U0001f31b":                         {},
+	"\U0001f31c":                         {},
+	"\U0001f31d":                         {},
+	"\U0001f31e":                         {},
+	"\U0001f31f":                         {},
+	"\U0001f320":                         {},
+	"\U0001f321":                         {},
+	"\U0001f321\ufe0f":                   {},
+	"\U0001f324":                         {},
+	"\U0001f324\ufe0f":                   {},
+	"\U0001f325":                         {},
+	"\U0001f325\ufe0f":                   {},
+	"\U0001f326":                         {},
+	"\U0001f326\ufe0f":                   {},
+	"\U0001f327":                         {},
+	"\U0001f327\ufe0f":                   {},
+	"\U0001f328":                         {},
+	"\U0001f328\ufe0f":                   {},
+	"\U0001f329":                         {},
+	"\U0001f329\ufe0f":                   {},
+	"\U0001f32a":                         {},
+	"\U0001f32a\ufe0f":                   {},
+	"\U0001f32b":                         {},
+	"\U0001f32b\ufe0f":                   {},
+	"\U0001f32c":                         {},
+	"\U0001f32c\ufe0f":                   {},
+	"\U0001f32d":                         {},
+	"\U0001f32e":                         {},
+	"\U0001f32f":                         {},
+	"\U0001f330":                         {},
+	"\U0001f331":                         {},
+	"\U0001f332":                         {},
+	"\U0001f333":                         {},
+	"\U0001f334":                         {},
+	"\U0001f335":                         {},
+	"\U0001f336":                         {},
+	"\U0001f336\ufe0f":                   {},
+	"\U0001f337":                         {},
+	"\U0001f338":                         {},
+	"\U0001f339":                         {},
+	"\U0001f33a":                         {},
+	"\U0001f33b":                         {},
+	"\U0001f33c":                         {},
+	"\U0001f33d":                         {},
+	"\U0001f33e":                         {},
+	"\U0001f33f":                         {},
+	"\U0001f340":                         {},
+	"\U0001f341":                         {},
+	"\U0001f342":                         {},
+	"\U0001f343":                         {},
+	"\U0001f344":                         {},
+	"\U0001f344\u200d\U0001f7eb":         {},
+	"\U0001f345":                         {},
+	"\U0001f346":                         {},
+	"\U0001f347":                         {},
+	"\U0001f348":                         {},
+	"\U0001f349":                         {},
+	"\U0001f34a":                         {},
+	"\U0001f34b":                         {},
+	"\U0001f34b\u200d\U0001f7e9":         {},
+	"\U0001f34c":                         {},
+	"\U0001f34d":                         {},
+	"\U0001f34e":                         {},
+	"\U0001f34f":                         {},
+	"\U0001f350":                         {},
+	"\U0001f351":                         {},
+	"\U0001f352":                         {},
+	"\U0001f353":                         {},
+	"\U0001f354":                         {},
+	"\U0001f355":                         {},
+	"\U0001f356":                         {},
+	"\U0001f357":                         {},
+	"\U0001f358":                         {},
+	"\U0001f359":                         {},
+	"\U0001f35a":                         {},
+	"\U0001f35b":                         {},
+	"\U0001f35c":                         {},
+	"\U0001f35d":                         {},
+	"\U0001f35e":                         {},
+	"\U0001f35f":                         {},
+	"\U0001f360":                         {},
+	"\U0001f361":                         {},
+	"\U0001f362":                         {},
+	"\U0001f363":                         {},
+	"\U0001f364":                         {},
+	"\U0001f365":                         {},
+	"\U0001f366":                         {},
+	"\U0001f367":                         {},
+	"\U0001f368":                         {},
+	"\U0001f369":                         {},
+	"\U0001f36a":                         {},
+	"\U0001f36b":                         {},
+	"\U0001f36c":                         {},
+	"\U0001f36d":                         {},
+	"\U0001f36e":                         {},
+	"\U0001f36f":                         {},
+	"\U0001f370":                         {},
+	"\U0001f371":                         {},
+	"\U0001f372":                         {},
+	"\U0001f373":                         {},
+	"\U0001f374":                         {},
+	"\U0001f375":                         {},
+	"\U0001f376":                         {},
+	"\U0001f377":                         {},
+	"\U0001f378":                         {},
+	"\U0001f379":                         {},
+	"\U0001f37a":                         {},
+	"\U0001f37b":                         {},
+	"\U0001f37c":                         {},
+	"\U0001f37d":                         {},
+	"\U0001f37d\ufe0f":                   {},
+	"\U0001f37e":                         {},
+	"\U0001f37f":                         {},
+	"\U0001f380":                         {},
+	"\U0001f381":                         {},
+	"\U0001f382":                         {},
+	"\U0001f383":                         {},
+	"\U0001f384":                         {},
+	"\U0001f385":                         {},
+	"\U0001f385\U0001f3fb":               {},
+	"\U0001f385\U0001f3fc":               {},
+	"\U0001f385\U0001f3fd":               {},
+	"\U0001f385\U0001f3fe":               {},
+	"\U0001f385\U0001f3ff":               {},
+	"\U0001f386":                         {},
+	"\U0001f387":                         {},
+	"\U0001f388":                         {},
+	"\U0001f389":                         {},
+	"\U0001f38a":                         {},
+	"\U0001f38b":                         {},
+	"\U0001f38c":                         {},
+	"\U0001f38d":                         {},
+	"\U0001f38e":                         {},
+	"\U0001f38f":                         {},
+	"\U0001f390":                         {},
+	"\U0001f391":                         {},
+	"\U0001f392":                         {},
+	"\U0001f393":                         {},
+	"\U0001f396":                         {},
+	"\U0001f396\ufe0f":                   {},
+	"\U0001f397":                         {},
+	"\U0001f397\ufe0f":                   {},
+	"\U0001f399":                         {},
+	"\U0001f399\ufe0f":                   {},
+	"\U0001f39a":                         {},
+	"\U0001f39a\ufe0f":                   {},
+	"\U0001f39b":                         {},
+	"\U0001f39b\ufe0f":                   {},
+	"\U0001f39e":                         {},
+	"\U0001f39e\ufe0f":                   {},
+	"\U0001f39f":                         {},
+	"\U0001f39f\ufe0f":                   {},
+	"\U0001f3a0":                         {},
+	"\U0001f3a1":                         {},
+	"\U0001f3a2":                         {},
+	"\U0001f3a3":                         {},
+	"\U0001f3a4":                         {},
+	"\U0001f3a5":                         {},
+	"\U0001f3a6":                         {},
+	"\U0001f3a7":                         {},
+	"\U0001f3a8":                         {},
+	"\U0001f3a9":                         {},
+	"\U0001f3aa":                         {},
+	"\U0001f3ab":                         {},
+	"\U0001f3ac":                         {},
+	"\U0001f3ad":                         {},
+	"\U0001f3ae":                         {},
+	"\U0001f3af":                         {},
+	"\U0001f3b0":                         {},
+	"\U0001f3b1":                         {},
+	"\U0001f3b2":                         {},
+	"\U0001f3b3":                         {},
+	"\U0001f3b4":                         {},
+	"\U0001f3b5":                         {},
+	"\U0001f3b6":                         {},
+	"\U0001f3b7":                         {},
+	"\U0001f3b8":                         {},
+	"\U0001f3b9":                         {},
+	"\U0001f3ba":                         {},
+	"\U0001f3bb":                         {},
+	"\U0001f3bc":                         {},
+	"\U0001f3bd":                         {},
+	"\U0001f3be":                         {},
+	"\U0001f3bf":                         {},
+	"\U0001f3c0":                         {},
+	"\U0001f3c1":                         {},
+	"\U0001f3c2":                         {},
+	"\U0001f3c2\U0001f3fb":               {},
+	"\U0001f3c2\U0001f3fc":               {},
+	"\U0001f3c2\U0001f3fd":               {},
+	"\U0001f3c2\U0001f3fe":               {},
+	"\U0001f3c2\U0001f3ff":               {},
+	"\U0001f3c3":                         {},
+	"\U0001f3c3\u200d\u2640":             {},
+	"\U0001f3c3\u200d\u2640\u200d\u27a1": {},
+	"\U0001f3c3\u200d\u2640\u200d\u27a1\ufe0f":                 {},
+	"\U0001f3c3\u200d\u2640\ufe0f":                             {},
+	"\U0001f3c3\u200d\u2640\ufe0f\u200d\u27a1":                 {},
+	"\U0001f3c3\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":           {},
+	"\U0001f3c3\u200d\u2642":                                   {},
+	"\U0001f3c3\u200d\u2642\u200d\u27a1":                       {},
+	"\U0001f3c3\u200d\u2642\u200d\u27a1\ufe0f":                 {},
+	"\U0001f3c3\u200d\u2642\ufe0f":                             {},
+	"\U0001f3c3\u200d\u2642\ufe0f\u200d\u27a1":                 {},
+	"\U0001f3c3\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":           {},
+	"\U0001f3c3\u200d\u27a1":                                   {},
+	"\U0001f3c3\u200d\u27a1\ufe0f":                             {},
+	"\U0001f3c3\U0001f3fb":                                     {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fb\u200d\u27a1":                         {},
+	"\U0001f3c3\U0001f3fb\u200d\u27a1\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fc":                                     {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fc\u200d\u27a1":                         {},
+	"\U0001f3c3\U0001f3fc\u200d\u27a1\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fd":                                     {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fd\u200d\u27a1":                         {},
+	"\U0001f3c3\U0001f3fd\u200d\u27a1\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fe":                                     {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3fe\u200d\u27a1":                         {},
+	"\U0001f3c3\U0001f3fe\u200d\u27a1\ufe0f":                   {},
+	"\U0001f3c3\U0001f3ff":                                     {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f3c3\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f3c3\U0001f3ff\u200d\u27a1":                         {},
+	"\U0001f3c3\U0001f3ff\u200d\u27a1\ufe0f":                   {},
+	"\U0001f3c4":                                               {},
+	"\U0001f3c4\u200d\u2640":                                   {},
+	"\U0001f3c4\u200d\u2640\ufe0f":                             {},
+	"\U0001f3c4\u200d\u2642":                                   {},
+	"\U0001f3c4\u200d\u2642\ufe0f":                             {},
+	"\U0001f3c4\U0001f3fb":                                     {},
+	"\U0001f3c4\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f3c4\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f3c4\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fc":                                     {},
+	"\U0001f3c4\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f3c4\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f3c4\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fd":                                     {},
+	"\U0001f3c4\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f3c4\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f3c4\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fe":                                     {},
+	"\U0001f3c4\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f3c4\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c4\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f3c4\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c4\U0001f3ff":                                     {},
+	"\U0001f3c4\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f3c4\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f3c4\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f3c4\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f3c5":                                               {},
+	"\U0001f3c6":                                               {},
+	"\U0001f3c7":                                               {},
+	"\U0001f3c7\U0001f3fb":                                     {},
+	"\U0001f3c7\U0001f3fc":                                     {},
+	"\U0001f3c7\U0001f3fd":                                     {},
+	"\U0001f3c7\U0001f3fe":                                     {},
+	"\U0001f3c7\U0001f3ff":                                     {},
+	"\U0001f3c8":                                               {},
+	"\U0001f3c9":                                               {},
+	"\U0001f3ca":                                               {},
+	"\U0001f3ca\u200d\u2640":                                   {},
+	"\U0001f3ca\u200d\u2640\ufe0f":                             {},
+	"\U0001f3ca\u200d\u2642":                                   {},
+	"\U0001f3ca\u200d\u2642\ufe0f":                             {},
+	"\U0001f3ca\U0001f3fb":                                     {},
+	"\U0001f3ca\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f3ca\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f3ca\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fc":                                     {},
+	"\U0001f3ca\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f3ca\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f3ca\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fd":                                     {},
+	"\U0001f3ca\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f3ca\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f3ca\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fe":                                     {},
+	"\U0001f3ca\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f3ca\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f3ca\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f3ca\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f3ca\U0001f3ff":                                     {},
+	"\U0001f3ca\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f3ca\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f3ca\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f3ca\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cb":                                               {},
+	"\U0001f3cb\u200d\u2640":                                   {},
+	"\U0001f3cb\u200d\u2640\ufe0f":                             {},
+	"\U0001f3cb\u200d\u2642":                                   {},
+	"\U0001f3cb\u200d\u2642\ufe0f":                             {},
+	"\U0001f3cb\ufe0f":                                         {},
+	"\U0001f3cb\ufe0f\u200d\u2640":                             {},
+	"\U0001f3cb\ufe0f\u200d\u2640\ufe0f":                       {},
+	"\U0001f3cb\ufe0f\u200d\u2642":                             {},
+	"\U0001f3cb\ufe0f\u200d\u2642\ufe0f":                       {},
+	"\U0001f3cb\U0001f3fb":                                     {},
+	"\U0001f3cb\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f3cb\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f3cb\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fc":                                     {},
+	"\U0001f3cb\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f3cb\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f3cb\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fd":                                     {},
+	"\U0001f3cb\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f3cb\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f3cb\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fe":                                     {},
+	"\U0001f3cb\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f3cb\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cb\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f3cb\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cb\U0001f3ff":                                     {},
+	"\U0001f3cb\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f3cb\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cb\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f3cb\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cc":                                               {},
+	"\U0001f3cc\u200d\u2640":                                   {},
+	"\U0001f3cc\u200d\u2640\ufe0f":                             {},
+	"\U0001f3cc\u200d\u2642":                                   {},
+	"\U0001f3cc\u200d\u2642\ufe0f":                             {},
+	"\U0001f3cc\ufe0f":                                         {},
+	"\U0001f3cc\ufe0f\u200d\u2640":                             {},
+	"\U0001f3cc\ufe0f\u200d\u2640\ufe0f":                       {},
+	"\U0001f3cc\ufe0f\u200d\u2642":                             {},
+	"\U0001f3cc\ufe0f\u200d\u2642\ufe0f":                       {},
+	"\U0001f3cc\U0001f3fb":                                     {},
+	"\U0001f3cc\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f3cc\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f3cc\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fc":                                     {},
+	"\U0001f3cc\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f3cc\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f3cc\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fd":                                     {},
+	"\U0001f3cc\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f3cc\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f3cc\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fe":                                     {},
+	"\U0001f3cc\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f3cc\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cc\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f3cc\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cc\U0001f3ff":                                     {},
+	"\U0001f3cc\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f3cc\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f3cc\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f3cc\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f3cd":                                               {},
+	"\U0001f3cd\ufe0f":                                         {},
+	"\U0001f3ce":                                               {},
+	"\U0001f3ce\ufe0f":                                         {},
+	"\U0001f3cf":                                               {},
+	"\U0001f3d0":                                               {},
+	"\U0001f3d1":                                               {},
+	"\U0001f3d2":                                               {},
+	"\U0001f3d3":                                               {},
+	"\U0001f3d4":                                               {},
+	"\U0001f3d4\ufe0f":                                         {},
+	"\U0001f3d5":                                               {},
+	"\U0001f3d5\ufe0f":                                         {},
+	"\U0001f3d6":                                               {},
+	"\U0001f3d6\ufe0f":                                         {},
+	"\U0001f3d7":                                               {},
+	"\U0001f3d7\ufe0f":                                         {},
+	"\U0001f3d8":                                               {},
+	"\U0001f3d8\ufe0f":                                         {},
+	"\U0001f3d9":                                               {},
+	"\U0001f3d9\ufe0f":                                         {},
+	"\U0001f3da":                                               {},
+	"\U0001f3da\ufe0f":                                         {},
+	"\U0001f3db":                                               {},
+	"\U0001f3db\ufe0f":                                         {},
+	"\U0001f3dc":                                               {},
+	"\U0001f3dc\ufe0f":                                         {},
+	"\U0001f3dd":                                               {},
+	"\U0001f3dd\ufe0f":                                         {},
+	"\U0001f3de":                                               {},
+	"\U0001f3de\ufe0f":                                         {},
+	"\U0001f3df":                                               {},
+	"\U0001f3df\ufe0f":                                         {},
+	"\U0001f3e0":                                               {},
+	"\U0001f3e1":                                               {},
+	"\U0001f3e2":                                               {},
+	"\U0001f3e3":                                               {},
+	"\U0001f3e4":                                               {},
+	"\U0001f3e5":                                               {},
+	"\U0001f3e6":                                               {},
+	"\U0001f3e7":                                               {},
+	"\U0001f3e8":                                               {},
+	"\U0001f3e9":                                               {},
+	"\U0001f3ea":                                               {},
+	"\U0001f3eb":                                               {},
+	"\U0001f3ec":                                               {},
+	"\U0001f3ed":                                               {},
+	"\U0001f3ee":                                               {},
+	"\U0001f3ef":                                               {},
+	"\U0001f3f0":                                               {},
+	"\U0001f3f3":                                               {},
+	"\U0001f3f3\u200d\u26a7":                                   {},
+	"\U0001f3f3\u200d\u26a7\ufe0f":                             {},
+	"\U0001f3f3\u200d\U0001f308":                               {},
+	"\U0001f3f3\ufe0f":                                         {},
+	"\U0001f3f3\ufe0f\u200d\u26a7":                             {},
+	"\U0001f3f3\ufe0f\u200d\u26a7\ufe0f":                       {},
+	"\U0001f3f3\ufe0f\u200d\U0001f308":                         {},
+	"\U0001f3f4":                                               {},
+	"\U0001f3f4\u200d\u2620":                                   {},
+	"\U0001f3f4\u200d\u2620\ufe0f":                             {},
+	"\U0001f3f4\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f": {},
+	"\U0001f3f4\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f": {},
+	"\U0001f3f4\U000e0067\U000e0062\U000e0077\U000e006c\U000e0073\U000e007f": {},
+	"\U0001f3f5":                             {},
+	"\U0001f3f5\ufe0f":                       {},
+	"\U0001f3f7":                             {},
+	"\U0001f3f7\ufe0f":                       {},
+	"\U0001f3f8":                             {},
+	"\U0001f3f9":                             {},
+	"\U0001f3fa":                             {},
+	"\U0001f3fb":                             {},
+	"\U0001f3fc":                             {},
+	"\U0001f3fd":                             {},
+	"\U0001f3fe":                             {},
+	"\U0001f3ff":                             {},
+	"\U0001f400":                             {},
+	"\U0001f401":                             {},
+	"\U0001f402":                             {},
+	"\U0001f403":                             {},
+	"\U0001f404":                             {},
+	"\U0001f405":                             {},
+	"\U0001f406":                             {},
+	"\U0001f407":                             {},
+	"\U0001f408":                             {},
+	"\U0001f408\u200d\u2b1b":                 {},
+	"\U0001f409":                             {},
+	"\U0001f40a":                             {},
+	"\U0001f40b":                             {},
+	"\U0001f40c":                             {},
+	"\U0001f40d":                             {},
+	"\U0001f40e":                             {},
+	"\U0001f40f":                             {},
+	"\U0001f410":                             {},
+	"\U0001f411":                             {},
+	"\U0001f412":                             {},
+	"\U0001f413":                             {},
+	"\U0001f414":                             {},
+	"\U0001f415":                             {},
+	"\U0001f415\u200d\U0001f9ba":             {},
+	"\U0001f416":                             {},
+	"\U0001f417":                             {},
+	"\U0001f418":                             {},
+	"\U0001f419":                             {},
+	"\U0001f41a":                             {},
+	"\U0001f41b":                             {},
+	"\U0001f41c":                             {},
+	"\U0001f41d":                             {},
+	"\U0001f41e":                             {},
+	"\U0001f41f":                             {},
+	"\U0001f420":                             {},
+	"\U0001f421":                             {},
+	"\U0001f422":                             {},
+	"\U0001f423":                             {},
+	"\U0001f424":                             {},
+	"\U0001f425":                             {},
+	"\U0001f426":                             {},
+	"\U0001f426\u200d\u2b1b":                 {},
+	"\U0001f426\u200d\U0001f525":             {},
+	"\U0001f427":                             {},
+	"\U0001f428":                             {},
+	"\U0001f429":                             {},
+	"\U0001f42a":                             {},
+	"\U0001f42b":                             {},
+	"\U0001f42c":                             {},
+	"\U0001f42d":                             {},
+	"\U0001f42e":                             {},
+	"\U0001f42f":                             {},
+	"\U0001f430":                             {},
+	"\U0001f431":                             {},
+	"\U0001f432":                             {},
+	"\U0001f433":                             {},
+	"\U0001f434":                             {},
+	"\U0001f435":                             {},
+	"\U0001f436":                             {},
+	"\U0001f437":                             {},
+	"\U0001f438":                             {},
+	"\U0001f439":                             {},
+	"\U0001f43a":                             {},
+	"\U0001f43b":                             {},
+	"\U0001f43b\u200d\u2744":                 {},
+	"\U0001f43b\u200d\u2744\ufe0f":           {},
+	"\U0001f43c":                             {},
+	"\U0001f43d":                             {},
+	"\U0001f43e":                             {},
+	"\U0001f43f":                             {},
+	"\U0001f43f\ufe0f":                       {},
+	"\U0001f440":                             {},
+	"\U0001f441":                             {},
+	"\U0001f441\u200d\U0001f5e8":             {},
+	"\U0001f441\u200d\U0001f5e8\ufe0f":       {},
+	"\U0001f441\ufe0f":                       {},
+	"\U0001f441\ufe0f\u200d\U0001f5e8":       {},
+	"\U0001f441\ufe0f\u200d\U0001f5e8\ufe0f": {},
+	"\U0001f442":                             {},
+	"\U0001f442\U0001f3fb":                   {},
+	"\U0001f442\U0001f3fc":                   {},
+	"\U0001f442\U0001f3fd":                   {},
+	"\U0001f442\U0001f3fe":                   {},
+	"\U0001f442\U0001f3ff":                   {},
+	"\U0001f443":                             {},
+	"\U0001f443\U0001f3fb":                   {},
+	"\U0001f443\U0001f3fc":                   {},
+	"\U0001f443\U0001f3fd":                   {},
+	"\U0001f443\U0001f3fe":                   {},
+	"\U0001f443\U0001f3ff":                   {},
+	"\U0001f444":                             {},
+	"\U0001f445":                             {},
+	"\U0001f446":                             {},
+	"\U0001f446\U0001f3fb":                   {},
+	"\U0001f446\U0001f3fc":                   {},
+	"\U0001f446\U0001f3fd":                   {},
+	"\U0001f446\U0001f3fe":                   {},
+	"\U0001f446\U0001f3ff":                   {},
+	"\U0001f447":                             {},
+	"\U0001f447\U0001f3fb":                   {},
+	"\U0001f447\U0001f3fc":                   {},
+	"\U0001f447\U0001f3fd":                   {},
+	"\U0001f447\U0001f3fe":                   {},
+	"\U0001f447\U0001f3ff":                   {},
+	"\U0001f448":                             {},
+	"\U0001f448\U0001f3fb":                   {},
+	"\U0001f448\U0001f3fc":                   {},
+	"\U0001f448\U0001f3fd":                   {},
+	"\U0001f448\U0001f3fe":                   {},
+	"\U0001f448\U0001f3ff":                   {},
+	"\U0001f449":                             {},
+	"\U0001f449\U0001f3fb":                   {},
+	"\U0001f449\U0001f3fc":                   {},
+	"\U0001f449\U0001f3fd":                   {},
+	"\U0001f449\U0001f3fe":                   {},
+	"\U0001f449\U0001f3ff":                   {},
+	"\U0001f44a":                             {},
+	"\U0001f44a\U0001f3fb":                   {},
+	"\U0001f44a\U0001f3fc":                   {},
+	"\U0001f44a\U0001f3fd":                   {},
+	"\U0001f44a\U0001f3fe":                   {},
+	"\U0001f44a\U0001f3ff":                   {},
+	"\U0001f44b":                             {},
+	"\U0001f44b\U0001f3fb":                   {},
+	"\U0001f44b\U0001f3fc":                   {},
+	"\U0001f44b\U0001f3fd":                   {},
+	"\U0001f44b\U0001f3fe":                   {},
+	"\U0001f44b\U0001f3ff":                   {},
+	"\U0001f44c":                             {},
+	"\U0001f44c\U0001f3fb":                   {},
+	"\U0001f44c\U0001f3fc":                   {},
+	"\U0001f44c\U0001f3fd":                   {},
+	"\U0001f44c\U0001f3fe":                   {},
+	"\U0001f44c\U0001f3ff":                   {},
+	"\U0001f44d":                             {},
+	"\U0001f44d\U0001f3fb":                   {},
+	"\U0001f44d\U0001f3fc":                   {},
+	"\U0001f44d\U0001f3fd":                   {},
+	"\U0001f44d\U0001f3fe":                   {},
+	"\U0001f44d\U0001f3ff":                   {},
+	"\U0001f44e":                             {},
+	"\U0001f44e\U0001f3fb":                   {},
+	"\U0001f44e\U0001f3fc":                   {},
+	"\U0001f44e\U0001f3fd":                   {},
+	"\U0001f44e\U0001f3fe":                   {},
+	"\U0001f44e\U0001f3ff":                   {},
+	"\U0001f44f":                             {},
+	"\U0001f44f\U0001f3fb":                   {},
+	"\U0001f44f\U0001f3fc":                   {},
+	"\U0001f44f\U0001f3fd":                   {},
+	"\U0001f44f\U0001f3fe":                   {},
+	"\U0001f44f\U0001f3ff":                   {},
+	"\U0001f450":                             {},
+	"\U0001f450\U0001f3fb":                   {},
+	"\U0001f450\U0001f3fc":                   {},
+	"\U0001f450\U0001f3fd":                   {},
+	"\U0001f450\U0001f3fe":                   {},
+	"\U0001f450\U0001f3ff":                   {},
+	"\U0001f451":                             {},
+	"\U0001f452":                             {},
+	"\U0001f453":                             {},
+	"\U0001f454":                             {},
+	"\U0001f455":                             {},
+	"\U0001f456":                             {},
+	"\U0001f457":                             {},
+	"\U0001f458":                             {},
+	"\U0001f459":                             {},
+	"\U0001f45a":                             {},
+	"\U0001f45b":                             {},
+	"\U0001f45c":                             {},
+	"\U0001f45d":                             {},
+	"\U0001f45e":                             {},
+	"\U0001f45f":                             {},
+	"\U0001f460":                             {},
+	"\U0001f461":                             {},
+	"\U0001f462":                             {},
+	"\U0001f463":                             {},
+	"\U0001f464":                             {},
+	"\U0001f465":                             {},
+	"\U0001f466":                             {},
+	"\U0001f466\U0001f3fb":                   {},
+	"\U0001f466\U0001f3fc":                   {},
+	"\U0001f466\U0001f3fd":                   {},
+	"\U0001f466\U0001f3fe":                   {},
+	"\U0001f466\U0001f3ff":                   {},
+	"\U0001f467":                             {},
+	"\U0001f467\U0001f3fb":                   {},
+	"\U0001f467\U0001f3fc":                   {},
+	"\U0001f467\U0001f3fd":                   {},
+	"\U0001f467\U0001f3fe":                   {},
+	"\U0001f467\U0001f3ff":                   {},
+	"\U0001f468":                             {},
+	"\U0001f468\u200d\u2695":                 {},
+	"\U0001f468\u200d\u2695\ufe0f":           {},
+	"\U0001f468\u200d\u2696":                 {},
+	"\U0001f468\u200d\u2696\ufe0f":           {},
+	"\U0001f468\u200d\u2708":                 {},
+	"\U0001f468\u200d\u2708\ufe0f":           {},
+	"\U0001f468\u200d\u2764\u200d\U0001f468": {},
+	"\U0001f468\u200d\u2764\u200d\U0001f48b\u200d\U0001f468":                           {},
+	"\U0001f468\u200d\u2764\ufe0f\u200d\U0001f468":                                     {},
+	"\U0001f468\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468":                     {},
+	"\U0001f468\u200d\U0001f33e":                                                       {},
+	"\U0001f468\u200d\U0001f373":                                                       {},
+	"\U0001f468\u200d\U0001f37c":                                                       {},
+	"\U0001f468\u200d\U0001f393":                                                       {},
+	"\U0001f468\u200d\U0001f3a4":                                                       {},
+	"\U0001f468\u200d\U0001f3a8":                                                       {},
+	"\U0001f468\u200d\U0001f3eb":                                                       {},
+	"\U0001f468\u200d\U0001f3ed":                                                       {},
+	"\U0001f468\u200d\U0001f466":                                                       {},
+	"\U0001f468\u200d\U0001f466\u200d\U0001f466":                                       {},
+	"\U0001f468\u200d\U0001f467":                                                       {},
+	"\U0001f468\u200d\U0001f467\u200d\U0001f466":                                       {},
+	"\U0001f468\u200d\U0001f467\u200d\U0001f467":                                       {},
+	"\U0001f468\u200d\U0001f468\u200d\U0001f466":                                       {},
+	"\U0001f468\u200d\U0001f468\u200d\U0001f466\u200d\U0001f466":                       {},
+	"\U0001f468\u200d\U0001f468\u200d\U0001f467":                                       {},
+	"\U0001f468\u200d\U0001f468\u200d\U0001f467\u200d\U0001f466":                       {},
+	"\U0001f468\u200d\U0001f468\u200d\U0001f467\u200d\U0001f467":                       {},
+	"\U0001f468\u200d\U0001f469\u200d\U0001f466":                                       {},
+	"\U0001f468\u200d\U0001f469\u200d\U0001f466\u200d\U0001f466":                       {},
+	"\U0001f468\u200d\U0001f469\u200d\U0001f467":                                       {},
+	"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466":                       {},
+	"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f467":                       {},
+	"\U0001f468\u200d\U0001f4bb":                                                       {},
+	"\U0001f468\u200d\U0001f4bc":                                                       {},
+	"\U0001f468\u200d\U0001f527":                                                       {},
+	"\U0001f468\u200d\U0001f52c":                                                       {},
+	"\U0001f468\u200d\U0001f680":                                                       {},
+	"\U0001f468\u200d\U0001f692":                                                       {},
+	"\U0001f468\u200d\U0001f9af":                                                       {},
+	"\U0001f468\u200d\U0001f9af\u200d\u27a1":                                           {},
+	"\U0001f468\u200d\U0001f9af\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f468\u200d\U0001f9b0":                                                       {},
+	"\U0001f468\u200d\U0001f9b1":                                                       {},
+	"\U0001f468\u200d\U0001f9b2":                                                       {},
+	"\U0001f468\u200d\U0001f9b3":                                                       {},
+	"\U0001f468\u200d\U0001f9bc":                                                       {},
+	"\U0001f468\u200d\U0001f9bc\u200d\u27a1":                                           {},
+	"\U0001f468\u200d\U0001f9bc\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f468\u200d\U0001f9bd":                                                       {},
+	"\U0001f468\u200d\U0001f9bd\u200d\u27a1":                                           {},
+	"\U0001f468\u200d\U0001f9bd\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f468\U0001f3fb":                                                             {},
+	"\U0001f468\U0001f3fb\u200d\u2695":                                                 {},
+	"\U0001f468\U0001f3fb\u200d\u2695\ufe0f":                                           {},
+	"\U0001f468\U0001f3fb\u200d\u2696":                                                 {},
+	"\U0001f468\U0001f3fb\u200d\u2696\ufe0f":                                           {},
+	"\U0001f468\U0001f3fb\u200d\u2708":                                                 {},
+	"\U0001f468\U0001f3fb\u200d\u2708\ufe0f":                                           {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f468\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f468\U0001f3fb\u200d\U0001f33e":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f373":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f37c":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f393":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f3a4":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f3a8":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f3eb":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f3ed":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f4bb":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f4bc":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f527":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f52c":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f680":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f692":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f468\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f468\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f468\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9af":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9b0":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9b1":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9b2":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9b3":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bc":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bd":                                             {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fb\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fc":                                                             {},
+	"\U0001f468\U0001f3fc\u200d\u2695":                                                 {},
+	"\U0001f468\U0001f3fc\u200d\u2695\ufe0f":                                           {},
+	"\U0001f468\U0001f3fc\u200d\u2696":                                                 {},
+	"\U0001f468\U0001f3fc\u200d\u2696\ufe0f":                                           {},
+	"\U0001f468\U0001f3fc\u200d\u2708":                                                 {},
+	"\U0001f468\U0001f3fc\u200d\u2708\ufe0f":                                           {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f468\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f468\U0001f3fc\u200d\U0001f33e":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f373":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f37c":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f393":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f3a4":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f3a8":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f3eb":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f3ed":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f4bb":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f4bc":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f527":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f52c":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f680":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f692":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f468\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f468\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f468\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9af":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9b0":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9b1":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9b2":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9b3":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bc":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bd":                                             {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fc\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fd":                                                             {},
+	"\U0001f468\U0001f3fd\u200d\u2695":                                                 {},
+	"\U0001f468\U0001f3fd\u200d\u2695\ufe0f":                                           {},
+	"\U0001f468\U0001f3fd\u200d\u2696":                                                 {},
+	"\U0001f468\U0001f3fd\u200d\u2696\ufe0f":                                           {},
+	"\U0001f468\U0001f3fd\u200d\u2708":                                                 {},
+	"\U0001f468\U0001f3fd\u200d\u2708\ufe0f":                                           {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f468\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f468\U0001f3fd\u200d\U0001f33e":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f373":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f37c":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f393":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f3a4":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f3a8":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f3eb":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f3ed":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f4bb":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f4bc":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f527":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f52c":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f680":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f692":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f468\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f468\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f468\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9af":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9b0":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9b1":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9b2":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9b3":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bc":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bd":                                             {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fd\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fe":                                                             {},
+	"\U0001f468\U0001f3fe\u200d\u2695":                                                 {},
+	"\U0001f468\U0001f3fe\u200d\u2695\ufe0f":                                           {},
+	"\U0001f468\U0001f3fe\u200d\u2696":                                                 {},
+	"\U0001f468\U0001f3fe\u200d\u2696\ufe0f":                                           {},
+	"\U0001f468\U0001f3fe\u200d\u2708":                                                 {},
+	"\U0001f468\U0001f3fe\u200d\u2708\ufe0f":                                           {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f468\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f468\U0001f3fe\u200d\U0001f33e":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f373":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f37c":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f393":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f3a4":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f3a8":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f3eb":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f3ed":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f4bb":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f4bc":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f527":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f52c":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f680":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f692":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f468\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f468\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f468\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9af":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9b0":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9b1":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9b2":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9b3":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bc":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bd":                                             {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3fe\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3ff":                                                             {},
+	"\U0001f468\U0001f3ff\u200d\u2695":                                                 {},
+	"\U0001f468\U0001f3ff\u200d\u2695\ufe0f":                                           {},
+	"\U0001f468\U0001f3ff\u200d\u2696":                                                 {},
+	"\U0001f468\U0001f3ff\u200d\u2696\ufe0f":                                           {},
+	"\U0001f468\U0001f3ff\u200d\u2708":                                                 {},
+	"\U0001f468\U0001f3ff\u200d\u2708\ufe0f":                                           {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f468\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f468\U0001f3ff\u200d\U0001f33e":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f373":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f37c":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f393":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f3a4":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f3a8":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f3eb":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f3ed":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f4bb":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f4bc":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f527":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f52c":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f680":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f692":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f468\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f468\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f468\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9af":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9b0":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9b1":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9b2":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9b3":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bc":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bd":                                             {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f468\U0001f3ff\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469":                                                                       {},
+	"\U0001f469\u200d\u2695":                                                           {},
+	"\U0001f469\u200d\u2695\ufe0f":                                                     {},
+	"\U0001f469\u200d\u2696":                                                           {},
+	"\U0001f469\u200d\u2696\ufe0f":                                                     {},
+	"\U0001f469\u200d\u2708":                                                           {},
+	"\U0001f469\u200d\u2708\ufe0f":                                                     {},
+	"\U0001f469\u200d\u2764\u200d\U0001f468":                                           {},
+	"\U0001f469\u200d\u2764\u200d\U0001f469":                                           {},
+	"\U0001f469\u200d\u2764\u200d\U0001f48b\u200d\U0001f468":                           {},
+	"\U0001f469\u200d\u2764\u200d\U0001f48b\u200d\U0001f469":                           {},
+	"\U0001f469\u200d\u2764\ufe0f\u200d\U0001f468":                                     {},
+	"\U0001f469\u200d\u2764\ufe0f\u200d\U0001f469":                                     {},
+	"\U0001f469\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468":                     {},
+	"\U0001f469\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469":                     {},
+	"\U0001f469\u200d\U0001f33e":                                                       {},
+	"\U0001f469\u200d\U0001f373":                                                       {},
+	"\U0001f469\u200d\U0001f37c":                                                       {},
+	"\U0001f469\u200d\U0001f393":                                                       {},
+	"\U0001f469\u200d\U0001f3a4":                                                       {},
+	"\U0001f469\u200d\U0001f3a8":                                                       {},
+	"\U0001f469\u200d\U0001f3eb":                                                       {},
+	"\U0001f469\u200d\U0001f3ed":                                                       {},
+	"\U0001f469\u200d\U0001f466":                                                       {},
+	"\U0001f469\u200d\U0001f466\u200d\U0001f466":                                       {},
+	"\U0001f469\u200d\U0001f467":                                                       {},
+	"\U0001f469\u200d\U0001f467\u200d\U0001f466":                                       {},
+	"\U0001f469\u200d\U0001f467\u200d\U0001f467":                                       {},
+	"\U0001f469\u200d\U0001f469\u200d\U0001f466":                                       {},
+	"\U0001f469\u200d\U0001f469\u200d\U0001f466\u200d\U0001f466":                       {},
+	"\U0001f469\u200d\U0001f469\u200d\U0001f467":                                       {},
+	"\U0001f469\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466":                       {},
+	"\U0001f469\u200d\U0001f469\u200d\U0001f467\u200d\U0001f467":                       {},
+	"\U0001f469\u200d\U0001f4bb":                                                       {},
+	"\U0001f469\u200d\U0001f4bc":                                                       {},
+	"\U0001f469\u200d\U0001f527":                                                       {},
+	"\U0001f469\u200d\U0001f52c":                                                       {},
+	"\U0001f469\u200d\U0001f680":                                                       {},
+	"\U0001f469\u200d\U0001f692":                                                       {},
+	"\U0001f469\u200d\U0001f9af":                                                       {},
+	"\U0001f469\u200d\U0001f9af\u200d\u27a1":                                           {},
+	"\U0001f469\u200d\U0001f9af\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f469\u200d\U0001f9b0":                                                       {},
+	"\U0001f469\u200d\U0001f9b1":                                                       {},
+	"\U0001f469\u200d\U0001f9b2":                                                       {},
+	"\U0001f469\u200d\U0001f9b3":                                                       {},
+	"\U0001f469\u200d\U0001f9bc":                                                       {},
+	"\U0001f469\u200d\U0001f9bc\u200d\u27a1":                                           {},
+	"\U0001f469\u200d\U0001f9bc\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f469\u200d\U0001f9bd":                                                       {},
+	"\U0001f469\u200d\U0001f9bd\u200d\u27a1":                                           {},
+	"\U0001f469\u200d\U0001f9bd\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f469\U0001f3fb":                                                             {},
+	"\U0001f469\U0001f3fb\u200d\u2695":                                                 {},
+	"\U0001f469\U0001f3fb\u200d\u2695\ufe0f":                                           {},
+	"\U0001f469\U0001f3fb\u200d\u2696":                                                 {},
+	"\U0001f469\U0001f3fb\u200d\u2696\ufe0f":                                           {},
+	"\U0001f469\U0001f3fb\u200d\u2708":                                                 {},
+	"\U0001f469\U0001f3fb\u200d\u2708\ufe0f":                                           {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f469\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f469\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f469\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f469\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f469\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fb":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fc":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fd":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fe":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3ff":       {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fb": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fc": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fd": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fe": {},
+	"\U0001f469\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3ff": {},
+	"\U0001f469\U0001f3fb\u200d\U0001f33e":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f373":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f37c":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f393":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f3a4":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f3a8":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f3eb":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f3ed":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f4bb":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f4bc":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f527":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f52c":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f680":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f692":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f469\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f469\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f469\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f91d\u200d\U0001f469\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9af":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9b0":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9b1":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9b2":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9b3":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bc":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bd":                                             {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fb\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fc":                                                             {},
+	"\U0001f469\U0001f3fc\u200d\u2695":                                                 {},
+	"\U0001f469\U0001f3fc\u200d\u2695\ufe0f":                                           {},
+	"\U0001f469\U0001f3fc\u200d\u2696":                                                 {},
+	"\U0001f469\U0001f3fc\u200d\u2696\ufe0f":                                           {},
+	"\U0001f469\U0001f3fc\u200d\u2708":                                                 {},
+	"\U0001f469\U0001f3fc\u200d\u2708\ufe0f":                                           {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f469\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f469\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f469\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f469\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f469\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fb":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fc":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fd":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fe":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3ff":       {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fb": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fc": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fd": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fe": {},
+	"\U0001f469\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3ff": {},
+	"\U0001f469\U0001f3fc\u200d\U0001f33e":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f373":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f37c":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f393":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f3a4":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f3a8":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f3eb":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f3ed":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f4bb":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f4bc":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f527":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f52c":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f680":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f692":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f469\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f469\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f469\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f91d\u200d\U0001f469\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9af":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9b0":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9b1":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9b2":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9b3":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bc":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bd":                                             {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fc\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fd":                                                             {},
+	"\U0001f469\U0001f3fd\u200d\u2695":                                                 {},
+	"\U0001f469\U0001f3fd\u200d\u2695\ufe0f":                                           {},
+	"\U0001f469\U0001f3fd\u200d\u2696":                                                 {},
+	"\U0001f469\U0001f3fd\u200d\u2696\ufe0f":                                           {},
+	"\U0001f469\U0001f3fd\u200d\u2708":                                                 {},
+	"\U0001f469\U0001f3fd\u200d\u2708\ufe0f":                                           {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f469\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f469\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f469\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f469\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f469\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fb":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fc":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fd":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fe":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3ff":       {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fb": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fc": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fd": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fe": {},
+	"\U0001f469\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3ff": {},
+	"\U0001f469\U0001f3fd\u200d\U0001f33e":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f373":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f37c":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f393":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f3a4":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f3a8":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f3eb":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f3ed":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f4bb":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f4bc":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f527":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f52c":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f680":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f692":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f469\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f469\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f469\U0001f3fe":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f91d\u200d\U0001f469\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9af":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9b0":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9b1":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9b2":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9b3":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bc":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bd":                                             {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fd\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fe":                                                             {},
+	"\U0001f469\U0001f3fe\u200d\u2695":                                                 {},
+	"\U0001f469\U0001f3fe\u200d\u2695\ufe0f":                                           {},
+	"\U0001f469\U0001f3fe\u200d\u2696":                                                 {},
+	"\U0001f469\U0001f3fe\u200d\u2696\ufe0f":                                           {},
+	"\U0001f469\U0001f3fe\u200d\u2708":                                                 {},
+	"\U0001f469\U0001f3fe\u200d\u2708\ufe0f":                                           {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f469\U0001f3fb":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f469\U0001f3fc":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f469\U0001f3fd":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f469\U0001f3fe":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f469\U0001f3ff":                       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fb":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fc":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fd":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fe":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3ff":       {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fb":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fc":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fd":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fe":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3ff":                 {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fb": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fc": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fd": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fe": {},
+	"\U0001f469\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3ff": {},
+	"\U0001f469\U0001f3fe\u200d\U0001f33e":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f373":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f37c":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f393":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f3a4":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f3a8":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f3eb":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f3ed":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f4bb":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f4bc":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f527":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f52c":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f680":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f692":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f468\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f469\U0001f3fb":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f469\U0001f3fc":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f469\U0001f3fd":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f91d\u200d\U0001f469\U0001f3ff":                   {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9af":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9b0":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9b1":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9b2":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9b3":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bc":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bd":                                             {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3fe\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3ff":                                                             {},
+	"\U0001f469\U0001f3ff\u200d\u2695":                                                 {},
+	"\U0001f469\U0001f3ff\u200d\u2695\ufe0f":                                           {},
+	"\U0001f469\U0001f3ff\u200d\u2696":                                                 {},
+	"\U0001f469\U0001f3ff\u200d\u2696\ufe0f":                                           {},
+	"\U0001f469\U0001f3ff\u200d\u2708":                                                 {},
+	"\U0001f469\U0001f3ff\u200d\u2708\ufe0f":                                           {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fb":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fc":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fd":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3fe":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f468\U0001f3ff":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f469\U0001f3fb":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f469\U0001f3fc":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f469\U0001f3fd":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f469\U0001f3fe":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f469\U0001f3ff":                       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fb":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fc":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fd":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3fe":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f468\U0001f3ff":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fb":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fc":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fd":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3fe":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f469\U0001f3ff":       {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fb":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fc":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fd":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3fe":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f468\U0001f3ff":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fb":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fc":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fd":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3fe":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f469\U0001f3ff":                 {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fb": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fc": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fd": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3fe": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f468\U0001f3ff": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fb": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fc": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fd": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3fe": {},
+	"\U0001f469\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469\U0001f3ff": {},
+	"\U0001f469\U0001f3ff\u200d\U0001f33e":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f373":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f37c":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f393":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f3a4":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f3a8":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f3eb":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f3ed":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f4bb":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f4bc":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f527":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f52c":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f680":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f692":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fb":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fc":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fd":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f468\U0001f3fe":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f469\U0001f3fb":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f469\U0001f3fc":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f469\U0001f3fd":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f91d\u200d\U0001f469\U0001f3fe":                   {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9af":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9b0":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9b1":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9b2":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9b3":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bc":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bd":                                             {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f469\U0001f3ff\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f46a":                                         {},
+	"\U0001f46b":                                         {},
+	"\U0001f46b\U0001f3fb":                               {},
+	"\U0001f46b\U0001f3fc":                               {},
+	"\U0001f46b\U0001f3fd":                               {},
+	"\U0001f46b\U0001f3fe":                               {},
+	"\U0001f46b\U0001f3ff":                               {},
+	"\U0001f46c":                                         {},
+	"\U0001f46c\U0001f3fb":                               {},
+	"\U0001f46c\U0001f3fc":                               {},
+	"\U0001f46c\U0001f3fd":                               {},
+	"\U0001f46c\U0001f3fe":                               {},
+	"\U0001f46c\U0001f3ff":                               {},
+	"\U0001f46d":                                         {},
+	"\U0001f46d\U0001f3fb":                               {},
+	"\U0001f46d\U0001f3fc":                               {},
+	"\U0001f46d\U0001f3fd":                               {},
+	"\U0001f46d\U0001f3fe":                               {},
+	"\U0001f46d\U0001f3ff":                               {},
+	"\U0001f46e":                                         {},
+	"\U0001f46e\u200d\u2640":                             {},
+	"\U0001f46e\u200d\u2640\ufe0f":                       {},
+	"\U0001f46e\u200d\u2642":                             {},
+	"\U0001f46e\u200d\u2642\ufe0f":                       {},
+	"\U0001f46e\U0001f3fb":                               {},
+	"\U0001f46e\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f46e\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f46e\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f46e\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f46e\U0001f3fc":                               {},
+	"\U0001f46e\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f46e\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f46e\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f46e\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f46e\U0001f3fd":                               {},
+	"\U0001f46e\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f46e\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f46e\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f46e\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f46e\U0001f3fe":                               {},
+	"\U0001f46e\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f46e\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f46e\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f46e\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f46e\U0001f3ff":                               {},
+	"\U0001f46e\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f46e\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f46e\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f46e\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f46f":                                         {},
+	"\U0001f46f\u200d\u2640":                             {},
+	"\U0001f46f\u200d\u2640\ufe0f":                       {},
+	"\U0001f46f\u200d\u2642":                             {},
+	"\U0001f46f\u200d\u2642\ufe0f":                       {},
+	"\U0001f470":                                         {},
+	"\U0001f470\u200d\u2640":                             {},
+	"\U0001f470\u200d\u2640\ufe0f":                       {},
+	"\U0001f470\u200d\u2642":                             {},
+	"\U0001f470\u200d\u2642\ufe0f":                       {},
+	"\U0001f470\U0001f3fb":                               {},
+	"\U0001f470\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f470\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f470\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f470\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f470\U0001f3fc":                               {},
+	"\U0001f470\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f470\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f470\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f470\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f470\U0001f3fd":                               {},
+	"\U0001f470\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f470\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f470\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f470\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f470\U0001f3fe":                               {},
+	"\U0001f470\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f470\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f470\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f470\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f470\U0001f3ff":                               {},
+	"\U0001f470\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f470\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f470\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f470\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f471":                                         {},
+	"\U0001f471\u200d\u2640":                             {},
+	"\U0001f471\u200d\u2640\ufe0f":                       {},
+	"\U0001f471\u200d\u2642":                             {},
+	"\U0001f471\u200d\u2642\ufe0f":                       {},
+	"\U0001f471\U0001f3fb":                               {},
+	"\U0001f471\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f471\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f471\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f471\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f471\U0001f3fc":                               {},
+	"\U0001f471\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f471\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f471\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f471\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f471\U0001f3fd":                               {},
+	"\U0001f471\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f471\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f471\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f471\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f471\U0001f3fe":                               {},
+	"\U0001f471\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f471\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f471\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f471\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f471\U0001f3ff":                               {},
+	"\U0001f471\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f471\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f471\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f471\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f472":                                         {},
+	"\U0001f472\U0001f3fb":                               {},
+	"\U0001f472\U0001f3fc":                               {},
+	"\U0001f472\U0001f3fd":                               {},
+	"\U0001f472\U0001f3fe":                               {},
+	"\U0001f472\U0001f3ff":                               {},
+	"\U0001f473":                                         {},
+	"\U0001f473\u200d\u2640":                             {},
+	"\U0001f473\u200d\u2640\ufe0f":                       {},
+	"\U0001f473\u200d\u2642":                             {},
+	"\U0001f473\u200d\u2642\ufe0f":                       {},
+	"\U0001f473\U0001f3fb":                               {},
+	"\U0001f473\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f473\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f473\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f473\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f473\U0001f3fc":                               {},
+	"\U0001f473\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f473\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f473\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f473\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f473\U0001f3fd":                               {},
+	"\U0001f473\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f473\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f473\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f473\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f473\U0001f3fe":                               {},
+	"\U0001f473\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f473\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f473\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f473\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f473\U0001f3ff":                               {},
+	"\U0001f473\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f473\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f473\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f473\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f474":                                         {},
+	"\U0001f474\U0001f3fb":                               {},
+	"\U0001f474\U0001f3fc":                               {},
+	"\U0001f474\U0001f3fd":                               {},
+	"\U0001f474\U0001f3fe":                               {},
+	"\U0001f474\U0001f3ff":                               {},
+	"\U0001f475":                                         {},
+	"\U0001f475\U0001f3fb":                               {},
+	"\U0001f475\U0001f3fc":                               {},
+	"\U0001f475\U0001f3fd":                               {},
+	"\U0001f475\U0001f3fe":                               {},
+	"\U0001f475\U0001f3ff":                               {},
+	"\U0001f476":                                         {},
+	"\U0001f476\U0001f3fb":                               {},
+	"\U0001f476\U0001f3fc":                               {},
+	"\U0001f476\U0001f3fd":                               {},
+	"\U0001f476\U0001f3fe":                               {},
+	"\U0001f476\U0001f3ff":                               {},
+	"\U0001f477":                                         {},
+	"\U0001f477\u200d\u2640":                             {},
+	"\U0001f477\u200d\u2640\ufe0f":                       {},
+	"\U0001f477\u200d\u2642":                             {},
+	"\U0001f477\u200d\u2642\ufe0f":                       {},
+	"\U0001f477\U0001f3fb":                               {},
+	"\U0001f477\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f477\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f477\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f477\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f477\U0001f3fc":                               {},
+	"\U0001f477\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f477\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f477\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f477\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f477\U0001f3fd":                               {},
+	"\U0001f477\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f477\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f477\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f477\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f477\U0001f3fe":                               {},
+	"\U0001f477\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f477\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f477\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f477\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f477\U0001f3ff":                               {},
+	"\U0001f477\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f477\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f477\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f477\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f478":                                         {},
+	"\U0001f478\U0001f3fb":                               {},
+	"\U0001f478\U0001f3fc":                               {},
+	"\U0001f478\U0001f3fd":                               {},
+	"\U0001f478\U0001f3fe":                               {},
+	"\U0001f478\U0001f3ff":                               {},
+	"\U0001f479":                                         {},
+	"\U0001f47a":                                         {},
+	"\U0001f47b":                                         {},
+	"\U0001f47c":                                         {},
+	"\U0001f47c\U0001f3fb":                               {},
+	"\U0001f47c\U0001f3fc":                               {},
+	"\U0001f47c\U0001f3fd":                               {},
+	"\U0001f47c\U0001f3fe":                               {},
+	"\U0001f47c\U0001f3ff":                               {},
+	"\U0001f47d":                                         {},
+	"\U0001f47e":                                         {},
+	"\U0001f47f":                                         {},
+	"\U0001f480":                                         {},
+	"\U0001f481":                                         {},
+	"\U0001f481\u200d\u2640":                             {},
+	"\U0001f481\u200d\u2640\ufe0f":                       {},
+	"\U0001f481\u200d\u2642":                             {},
+	"\U0001f481\u200d\u2642\ufe0f":                       {},
+	"\U0001f481\U0001f3fb":                               {},
+	"\U0001f481\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f481\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f481\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f481\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f481\U0001f3fc":                               {},
+	"\U0001f481\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f481\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f481\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f481\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f481\U0001f3fd":                               {},
+	"\U0001f481\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f481\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f481\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f481\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f481\U0001f3fe":                               {},
+	"\U0001f481\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f481\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f481\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f481\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f481\U0001f3ff":                               {},
+	"\U0001f481\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f481\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f481\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f481\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f482":                                         {},
+	"\U0001f482\u200d\u2640":                             {},
+	"\U0001f482\u200d\u2640\ufe0f":                       {},
+	"\U0001f482\u200d\u2642":                             {},
+	"\U0001f482\u200d\u2642\ufe0f":                       {},
+	"\U0001f482\U0001f3fb":                               {},
+	"\U0001f482\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f482\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f482\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f482\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f482\U0001f3fc":                               {},
+	"\U0001f482\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f482\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f482\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f482\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f482\U0001f3fd":                               {},
+	"\U0001f482\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f482\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f482\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f482\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f482\U0001f3fe":                               {},
+	"\U0001f482\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f482\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f482\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f482\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f482\U0001f3ff":                               {},
+	"\U0001f482\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f482\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f482\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f482\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f483":                                         {},
+	"\U0001f483\U0001f3fb":                               {},
+	"\U0001f483\U0001f3fc":                               {},
+	"\U0001f483\U0001f3fd":                               {},
+	"\U0001f483\U0001f3fe":                               {},
+	"\U0001f483\U0001f3ff":                               {},
+	"\U0001f484":                                         {},
+	"\U0001f485":                                         {},
+	"\U0001f485\U0001f3fb":                               {},
+	"\U0001f485\U0001f3fc":                               {},
+	"\U0001f485\U0001f3fd":                               {},
+	"\U0001f485\U0001f3fe":                               {},
+	"\U0001f485\U0001f3ff":                               {},
+	"\U0001f486":                                         {},
+	"\U0001f486\u200d\u2640":                             {},
+	"\U0001f486\u200d\u2640\ufe0f":                       {},
+	"\U0001f486\u200d\u2642":                             {},
+	"\U0001f486\u200d\u2642\ufe0f":                       {},
+	"\U0001f486\U0001f3fb":                               {},
+	"\U0001f486\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f486\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f486\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f486\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f486\U0001f3fc":                               {},
+	"\U0001f486\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f486\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f486\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f486\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f486\U0001f3fd":                               {},
+	"\U0001f486\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f486\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f486\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f486\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f486\U0001f3fe":                               {},
+	"\U0001f486\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f486\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f486\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f486\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f486\U0001f3ff":                               {},
+	"\U0001f486\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f486\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f486\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f486\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f487":                                         {},
+	"\U0001f487\u200d\u2640":                             {},
+	"\U0001f487\u200d\u2640\ufe0f":                       {},
+	"\U0001f487\u200d\u2642":                             {},
+	"\U0001f487\u200d\u2642\ufe0f":                       {},
+	"\U0001f487\U0001f3fb":                               {},
+	"\U0001f487\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f487\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f487\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f487\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f487\U0001f3fc":                               {},
+	"\U0001f487\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f487\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f487\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f487\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f487\U0001f3fd":                               {},
+	"\U0001f487\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f487\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f487\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f487\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f487\U0001f3fe":                               {},
+	"\U0001f487\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f487\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f487\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f487\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f487\U0001f3ff":                               {},
+	"\U0001f487\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f487\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f487\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f487\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f488":                                         {},
+	"\U0001f489":                                         {},
+	"\U0001f48a":                                         {},
+	"\U0001f48b":                                         {},
+	"\U0001f48c":                                         {},
+	"\U0001f48d":                                         {},
+	"\U0001f48e":                                         {},
+	"\U0001f48f":                                         {},
+	"\U0001f48f\U0001f3fb":                               {},
+	"\U0001f48f\U0001f3fc":                               {},
+	"\U0001f48f\U0001f3fd":                               {},
+	"\U0001f48f\U0001f3fe":                               {},
+	"\U0001f48f\U0001f3ff":                               {},
+	"\U0001f490":                                         {},
+	"\U0001f491":                                         {},
+	"\U0001f491\U0001f3fb":                               {},
+	"\U0001f491\U0001f3fc":                               {},
+	"\U0001f491\U0001f3fd":                               {},
+	"\U0001f491\U0001f3fe":                               {},
+	"\U0001f491\U0001f3ff":                               {},
+	"\U0001f492":                                         {},
+	"\U0001f493":                                         {},
+	"\U0001f494":                                         {},
+	"\U0001f495":                                         {},
+	"\U0001f496":                                         {},
+	"\U0001f497":                                         {},
+	"\U0001f498":                                         {},
+	"\U0001f499":                                         {},
+	"\U0001f49a":                                         {},
+	"\U0001f49b":                                         {},
+	"\U0001f49c":                                         {},
+	"\U0001f49d":                                         {},
+	"\U0001f49e":                                         {},
+	"\U0001f49f":                                         {},
+	"\U0001f4a0":                                         {},
+	"\U0001f4a1":                                         {},
+	"\U0001f4a2":                                         {},
+	"\U0001f4a3":                                         {},
+	"\U0001f4a4":                                         {},
+	"\U0001f4a5":                                         {},
+	"\U0001f4a6":                                         {},
+	"\U0001f4a7":                                         {},
+	"\U0001f4a8":                                         {},
+	"\U0001f4a9":                                         {},
+	"\U0001f4aa":                                         {},
+	"\U0001f4aa\U0001f3fb":                               {},
+	"\U0001f4aa\U0001f3fc":                               {},
+	"\U0001f4aa\U0001f3fd":                               {},
+	"\U0001f4aa\U0001f3fe":                               {},
+	"\U0001f4aa\U0001f3ff":                               {},
+	"\U0001f4ab":                                         {},
+	"\U0001f4ac":                                         {},
+	"\U0001f4ad":                                         {},
+	"\U0001f4ae":                                         {},
+	"\U0001f4af":                                         {},
+	"\U0001f4b0":                                         {},
+	"\U0001f4b1":                                         {},
+	"\U0001f4b2":                                         {},
+	"\U0001f4b3":                                         {},
+	"\U0001f4b4":                                         {},
+	"\U0001f4b5":                                         {},
+	"\U0001f4b6":                                         {},
+	"\U0001f4b7":                                         {},
+	"\U0001f4b8":                                         {},
+	"\U0001f4b9":                                         {},
+	"\U0001f4ba":                                         {},
+	"\U0001f4bb":                                         {},
+	"\U0001f4bc":                                         {},
+	"\U0001f4bd":                                         {},
+	"\U0001f4be":                                         {},
+	"\U0001f4bf":                                         {},
+	"\U0001f4c0":                                         {},
+	"\U0001f4c1":                                         {},
+	"\U0001f4c2":                                         {},
+	"\U0001f4c3":                                         {},
+	"\U0001f4c4":                                         {},
+	"\U0001f4c5":                                         {},
+	"\U0001f4c6":                                         {},
+	"\U0001f4c7":                                         {},
+	"\U0001f4c8":                                         {},
+	"\U0001f4c9":                                         {},
+	"\U0001f4ca":                                         {},
+	"\U0001f4cb":                                         {},
+	"\U0001f4cc":                                         {},
+	"\U0001f4cd":                                         {},
+	"\U0001f4ce":                                         {},
+	"\U0001f4cf":                                         {},
+	"\U0001f4d0":                                         {},
+	"\U0001f4d1":                                         {},
+	"\U0001f4d2":                                         {},
+	"\U0001f4d3":                                         {},
+	"\U0001f4d4":                                         {},
+	"\U0001f4d5":                                         {},
+	"\U0001f4d6":                                         {},
+	"\U0001f4d7":                                         {},
+	"\U0001f4d8":                                         {},
+	"\U0001f4d9":                                         {},
+	"\U0001f4da":                                         {},
+	"\U0001f4db":                                         {},
+	"\U0001f4dc":                                         {},
+	"\U0001f4dd":                                         {},
+	"\U0001f4de":                                         {},
+	"\U0001f4df":                                         {},
+	"\U0001f4e0":                                         {},
+	"\U0001f4e1":                                         {},
+	"\U0001f4e2":                                         {},
+	"\U0001f4e3":                                         {},
+	"\U0001f4e4":                                         {},
+	"\U0001f4e5":                                         {},
+	"\U0001f4e6":                                         {},
+	"\U0001f4e7":                                         {},
+	"\U0001f4e8":                                         {},
+	"\U0001f4e9":                                         {},
+	"\U0001f4ea":                                         {},
+	"\U0001f4eb":                                         {},
+	"\U0001f4ec":                                         {},
+	"\U0001f4ed":                                         {},
+	"\U0001f4ee":                                         {},
+	"\U0001f4ef":                                         {},
+	"\U0001f4f0":                                         {},
+	"\U0001f4f1":                                         {},
+	"\U0001f4f2":                                         {},
+	"\U0001f4f3":                                         {},
+	"\U0001f4f4":                                         {},
+	"\U0001f4f5":                                         {},
+	"\U0001f4f6":                                         {},
+	"\U0001f4f7":                                         {},
+	"\U0001f4f8":                                         {},
+	"\U0001f4f9":                                         {},
+	"\U0001f4fa":                                         {},
+	"\U0001f4fb":                                         {},
+	"\U0001f4fc":                                         {},
+	"\U0001f4fd":                                         {},
+	"\U0001f4fd\ufe0f":                                   {},
+	"\U0001f4ff":                                         {},
+	"\U0001f500":                                         {},
+	"\U0001f501":                                         {},
+	"\U0001f502":                                         {},
+	"\U0001f503":                                         {},
+	"\U0001f504":                                         {},
+	"\U0001f505":                                         {},
+	"\U0001f506":                                         {},
+	"\U0001f507":                                         {},
+	"\U0001f508":                                         {},
+	"\U0001f509":                                         {},
+	"\U0001f50a":                                         {},
+	"\U0001f50b":                                         {},
+	"\U0001f50c":                                         {},
+	"\U0001f50d":                                         {},
+	"\U0001f50e":                                         {},
+	"\U0001f50f":                                         {},
+	"\U0001f510":                                         {},
+	"\U0001f511":                                         {},
+	"\U0001f512":                                         {},
+	"\U0001f513":                                         {},
+	"\U0001f514":                                         {},
+	"\U0001f515":                                         {},
+	"\U0001f516":                                         {},
+	"\U0001f517":                                         {},
+	"\U0001f518":                                         {},
+	"\U0001f519":                                         {},
+	"\U0001f51a":                                         {},
+	"\U0001f51b":                                         {},
+	"\U0001f51c":                                         {},
+	"\U0001f51d":                                         {},
+	"\U0001f51e":                                         {},
+	"\U0001f51f":                                         {},
+	"\U0001f520":                                         {},
+	"\U0001f521":                                         {},
+	"\U0001f522":                                         {},
+	"\U0001f523":                                         {},
+	"\U0001f524":                                         {},
+	"\U0001f525":                                         {},
+	"\U0001f526":                                         {},
+	"\U0001f527":                                         {},
+	"\U0001f528":                                         {},
+	"\U0001f529":                                         {},
+	"\U0001f52a":                                         {},
+	"\U0001f52b":                                         {},
+	"\U0001f52c":                                         {},
+	"\U0001f52d":                                         {},
+	"\U0001f52e":                                         {},
+	"\U0001f52f":                                         {},
+	"\U0001f530":                                         {},
+	"\U0001f531":                                         {},
+	"\U0001f532":                                         {},
+	"\U0001f533":                                         {},
+	"\U0001f534":                                         {},
+	"\U0001f535":                                         {},
+	"\U0001f536":                                         {},
+	"\U0001f537":                                         {},
+	"\U0001f538":                                         {},
+	"\U0001f539":                                         {},
+	"\U0001f53a":                                         {},
+	"\U0001f53b":                                         {},
+	"\U0001f53c":                                         {},
+	"\U0001f53d":                                         {},
+	"\U0001f549":                                         {},
+	"\U0001f549\ufe0f":                                   {},
+	"\U0001f54a":                                         {},
+	"\U0001f54a\ufe0f":                                   {},
+	"\U0001f54b":                                         {},
+	"\U0001f54c":                                         {},
+	"\U0001f54d":                                         {},
+	"\U0001f54e":                                         {},
+	"\U0001f550":                                         {},
+	"\U0001f551":                                         {},
+	"\U0001f552":                                         {},
+	"\U0001f553":                                         {},
+	"\U0001f554":                                         {},
+	"\U0001f555":                                         {},
+	"\U0001f556":                                         {},
+	"\U0001f557":                                         {},
+	"\U0001f558":                                         {},
+	"\U0001f559":                                         {},
+	"\U0001f55a":                                         {},
+	"\U0001f55b":                                         {},
+	"\U0001f55c":                                         {},
+	"\U0001f55d":                                         {},
+	"\U0001f55e":                                         {},
+	"\U0001f55f":                                         {},
+	"\U0001f560":                                         {},
+	"\U0001f561":                                         {},
+	"\U0001f562":                                         {},
+	"\U0001f563":                                         {},
+	"\U0001f564":                                         {},
+	"\U0001f565":                                         {},
+	"\U0001f566":                                         {},
+	"\U0001f567":                                         {},
+	"\U0001f56f":                                         {},
+	"\U0001f56f\ufe0f":                                   {},
+	"\U0001f570":                                         {},
+	"\U0001f570\ufe0f":                                   {},
+	"\U0001f573":                                         {},
+	"\U0001f573\ufe0f":                                   {},
+	"\U0001f574":                                         {},
+	"\U0001f574\ufe0f":                                   {},
+	"\U0001f574\U0001f3fb":                               {},
+	"\U0001f574\U0001f3fc":                               {},
+	"\U0001f574\U0001f3fd":                               {},
+	"\U0001f574\U0001f3fe":                               {},
+	"\U0001f574\U0001f3ff":                               {},
+	"\U0001f575":                                         {},
+	"\U0001f575\u200d\u2640":                             {},
+	"\U0001f575\u200d\u2640\ufe0f":                       {},
+	"\U0001f575\u200d\u2642":                             {},
+	"\U0001f575\u200d\u2642\ufe0f":                       {},
+	"\U0001f575\ufe0f":                                   {},
+	"\U0001f575\ufe0f\u200d\u2640":                       {},
+	"\U0001f575\ufe0f\u200d\u2640\ufe0f":                 {},
+	"\U0001f575\ufe0f\u200d\u2642":                       {},
+	"\U0001f575\ufe0f\u200d\u2642\ufe0f":                 {},
+	"\U0001f575\U0001f3fb":                               {},
+	"\U0001f575\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f575\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f575\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f575\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f575\U0001f3fc":                               {},
+	"\U0001f575\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f575\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f575\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f575\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f575\U0001f3fd":                               {},
+	"\U0001f575\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f575\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f575\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f575\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f575\U0001f3fe":                               {},
+	"\U0001f575\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f575\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f575\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f575\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f575\U0001f3ff":                               {},
+	"\U0001f575\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f575\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f575\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f575\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f576":                                         {},
+	"\U0001f576\ufe0f":                                   {},
+	"\U0001f577":                                         {},
+	"\U0001f577\ufe0f":                                   {},
+	"\U0001f578":                                         {},
+	"\U0001f578\ufe0f":                                   {},
+	"\U0001f579":                                         {},
+	"\U0001f579\ufe0f":                                   {},
+	"\U0001f57a":                                         {},
+	"\U0001f57a\U0001f3fb":                               {},
+	"\U0001f57a\U0001f3fc":                               {},
+	"\U0001f57a\U0001f3fd":                               {},
+	"\U0001f57a\U0001f3fe":                               {},
+	"\U0001f57a\U0001f3ff":                               {},
+	"\U0001f587":                                         {},
+	"\U0001f587\ufe0f":                                   {},
+	"\U0001f58a":                                         {},
+	"\U0001f58a\ufe0f":                                   {},
+	"\U0001f58b":                                         {},
+	"\U0001f58b\ufe0f":                                   {},
+	"\U0001f58c":                                         {},
+	"\U0001f58c\ufe0f":                                   {},
+	"\U0001f58d":                                         {},
+	"\U0001f58d\ufe0f":                                   {},
+	"\U0001f590":                                         {},
+	"\U0001f590\ufe0f":                                   {},
+	"\U0001f590\U0001f3fb":                               {},
+	"\U0001f590\U0001f3fc":                               {},
+	"\U0001f590\U0001f3fd":                               {},
+	"\U0001f590\U0001f3fe":                               {},
+	"\U0001f590\U0001f3ff":                               {},
+	"\U0001f595":                                         {},
+	"\U0001f595\U0001f3fb":                               {},
+	"\U0001f595\U0001f3fc":                               {},
+	"\U0001f595\U0001f3fd":                               {},
+	"\U0001f595\U0001f3fe":                               {},
+	"\U0001f595\U0001f3ff":                               {},
+	"\U0001f596":                                         {},
+	"\U0001f596\U0001f3fb":                               {},
+	"\U0001f596\U0001f3fc":                               {},
+	"\U0001f596\U0001f3fd":                               {},
+	"\U0001f596\U0001f3fe":                               {},
+	"\U0001f596\U0001f3ff":                               {},
+	"\U0001f5a4":                                         {},
+	"\U0001f5a5":                                         {},
+	"\U0001f5a5\ufe0f":                                   {},
+	"\U0001f5a8":                                         {},
+	"\U0001f5a8\ufe0f":                                   {},
+	"\U0001f5b1":                                         {},
+	"\U0001f5b1\ufe0f":                                   {},
+	"\U0001f5b2":                                         {},
+	"\U0001f5b2\ufe0f":                                   {},
+	"\U0001f5bc":                                         {},
+	"\U0001f5bc\ufe0f":                                   {},
+	"\U0001f5c2":                                         {},
+	"\U0001f5c2\ufe0f":                                   {},
+	"\U0001f5c3":                                         {},
+	"\U0001f5c3\ufe0f":                                   {},
+	"\U0001f5c4":                                         {},
+	"\U0001f5c4\ufe0f":                                   {},
+	"\U0001f5d1":                                         {},
+	"\U0001f5d1\ufe0f":                                   {},
+	"\U0001f5d2":                                         {},
+	"\U0001f5d2\ufe0f":                                   {},
+	"\U0001f5d3":                                         {},
+	"\U0001f5d3\ufe0f":                                   {},
+	"\U0001f5dc":                                         {},
+	"\U0001f5dc\ufe0f":                                   {},
+	"\U0001f5dd":                                         {},
+	"\U0001f5dd\ufe0f":                                   {},
+	"\U0001f5de":                                         {},
+	"\U0001f5de\ufe0f":                                   {},
+	"\U0001f5e1":                                         {},
+	"\U0001f5e1\ufe0f":                                   {},
+	"\U0001f5e3":                                         {},
+	"\U0001f5e3\ufe0f":                                   {},
+	"\U0001f5e8":                                         {},
+	"\U0001f5e8\ufe0f":                                   {},
+	"\U0001f5ef":                                         {},
+	"\U0001f5ef\ufe0f":                                   {},
+	"\U0001f5f3":                                         {},
+	"\U0001f5f3\ufe0f":                                   {},
+	"\U0001f5fa":                                         {},
+	"\U0001f5fa\ufe0f":                                   {},
+	"\U0001f5fb":                                         {},
+	"\U0001f5fc":                                         {},
+	"\U0001f5fd":                                         {},
+	"\U0001f5fe":                                         {},
+	"\U0001f5ff":                                         {},
+	"\U0001f600":                                         {},
+	"\U0001f601":                                         {},
+	"\U0001f602":                                         {},
+	"\U0001f603":                                         {},
+	"\U0001f604":                                         {},
+	"\U0001f605":                                         {},
+	"\U0001f606":                                         {},
+	"\U0001f607":                                         {},
+	"\U0001f608":                                         {},
+	"\U0001f609":                                         {},
+	"\U0001f60a":                                         {},
+	"\U0001f60b":                                         {},
+	"\U0001f60c":                                         {},
+	"\U0001f60d":                                         {},
+	"\U0001f60e":                                         {},
+	"\U0001f60f":                                         {},
+	"\U0001f610":                                         {},
+	"\U0001f611":                                         {},
+	"\U0001f612":                                         {},
+	"\U0001f613":                                         {},
+	"\U0001f614":                                         {},
+	"\U0001f615":                                         {},
+	"\U0001f616":                                         {},
+	"\U0001f617":                                         {},
+	"\U0001f618":                                         {},
+	"\U0001f619":                                         {},
+	"\U0001f61a":                                         {},
+	"\U0001f61b":                                         {},
+	"\U0001f61c":                                         {},
+	"\U0001f61d":                                         {},
+	"\U0001f61e":                                         {},
+	"\U0001f61f":                                         {},
+	"\U0001f620":                                         {},
+	"\U0001f621":                                         {},
+	"\U0001f622":                                         {},
+	"\U0001f623":                                         {},
+	"\U0001f624":                                         {},
+	"\U0001f625":                                         {},
+	"\U0001f626":                                         {},
+	"\U0001f627":                                         {},
+	"\U0001f628":                                         {},
+	"\U0001f629":                                         {},
+	"\U0001f62a":                                         {},
+	"\U0001f62b":                                         {},
+	"\U0001f62c":                                         {},
+	"\U0001f62d":                                         {},
+	"\U0001f62e":                                         {},
+	"\U0001f62e\u200d\U0001f4a8":                         {},
+	"\U0001f62f":                                         {},
+	"\U0001f630":                                         {},
+	"\U0001f631":                                         {},
+	"\U0001f632":                                         {},
+	"\U0001f633":                                         {},
+	"\U0001f634":                                         {},
+	"\U0001f635":                                         {},
+	"\U0001f635\u200d\U0001f4ab":                         {},
+	"\U0001f636":                                         {},
+	"\U0001f636\u200d\U0001f32b":                         {},
+	"\U0001f636\u200d\U0001f32b\ufe0f":                   {},
+	"\U0001f637":                                         {},
+	"\U0001f638":                                         {},
+	"\U0001f639":                                         {},
+	"\U0001f63a":                                         {},
+	"\U0001f63b":                                         {},
+	"\U0001f63c":                                         {},
+	"\U0001f63d":                                         {},
+	"\U0001f63e":                                         {},
+	"\U0001f63f":                                         {},
+	"\U0001f640":                                         {},
+	"\U0001f641":                                         {},
+	"\U0001f642":                                         {},
+	"\U0001f642\u200d\u2194":                             {},
+	"\U0001f642\u200d\u2194\ufe0f":                       {},
+	"\U0001f642\u200d\u2195":                             {},
+	"\U0001f642\u200d\u2195\ufe0f":                       {},
+	"\U0001f643":                                         {},
+	"\U0001f644":                                         {},
+	"\U0001f645":                                         {},
+	"\U0001f645\u200d\u2640":                             {},
+	"\U0001f645\u200d\u2640\ufe0f":                       {},
+	"\U0001f645\u200d\u2642":                             {},
+	"\U0001f645\u200d\u2642\ufe0f":                       {},
+	"\U0001f645\U0001f3fb":                               {},
+	"\U0001f645\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f645\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f645\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f645\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f645\U0001f3fc":                               {},
+	"\U0001f645\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f645\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f645\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f645\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f645\U0001f3fd":                               {},
+	"\U0001f645\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f645\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f645\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f645\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f645\U0001f3fe":                               {},
+	"\U0001f645\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f645\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f645\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f645\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f645\U0001f3ff":                               {},
+	"\U0001f645\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f645\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f645\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f645\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f646":                                         {},
+	"\U0001f646\u200d\u2640":                             {},
+	"\U0001f646\u200d\u2640\ufe0f":                       {},
+	"\U0001f646\u200d\u2642":                             {},
+	"\U0001f646\u200d\u2642\ufe0f":                       {},
+	"\U0001f646\U0001f3fb":                               {},
+	"\U0001f646\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f646\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f646\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f646\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f646\U0001f3fc":                               {},
+	"\U0001f646\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f646\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f646\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f646\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f646\U0001f3fd":                               {},
+	"\U0001f646\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f646\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f646\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f646\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f646\U0001f3fe":                               {},
+	"\U0001f646\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f646\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f646\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f646\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f646\U0001f3ff":                               {},
+	"\U0001f646\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f646\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f646\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f646\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f647":                                         {},
+	"\U0001f647\u200d\u2640":                             {},
+	"\U0001f647\u200d\u2640\ufe0f":                       {},
+	"\U0001f647\u200d\u2642":                             {},
+	"\U0001f647\u200d\u2642\ufe0f":                       {},
+	"\U0001f647\U0001f3fb":                               {},
+	"\U0001f647\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f647\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f647\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f647\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f647\U0001f3fc":                               {},
+	"\U0001f647\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f647\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f647\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f647\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f647\U0001f3fd":                               {},
+	"\U0001f647\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f647\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f647\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f647\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f647\U0001f3fe":                               {},
+	"\U0001f647\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f647\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f647\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f647\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f647\U0001f3ff":                               {},
+	"\U0001f647\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f647\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f647\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f647\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f648":                                         {},
+	"\U0001f649":                                         {},
+	"\U0001f64a":                                         {},
+	"\U0001f64b":                                         {},
+	"\U0001f64b\u200d\u2640":                             {},
+	"\U0001f64b\u200d\u2640\ufe0f":                       {},
+	"\U0001f64b\u200d\u2642":                             {},
+	"\U0001f64b\u200d\u2642\ufe0f":                       {},
+	"\U0001f64b\U0001f3fb":                               {},
+	"\U0001f64b\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f64b\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f64b\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f64b\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f64b\U0001f3fc":                               {},
+	"\U0001f64b\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f64b\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f64b\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f64b\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f64b\U0001f3fd":                               {},
+	"\U0001f64b\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f64b\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f64b\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f64b\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f64b\U0001f3fe":                               {},
+	"\U0001f64b\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f64b\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f64b\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f64b\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f64b\U0001f3ff":                               {},
+	"\U0001f64b\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f64b\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f64b\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f64b\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f64c":                                         {},
+	"\U0001f64c\U0001f3fb":                               {},
+	"\U0001f64c\U0001f3fc":                               {},
+	"\U0001f64c\U0001f3fd":                               {},
+	"\U0001f64c\U0001f3fe":                               {},
+	"\U0001f64c\U0001f3ff":                               {},
+	"\U0001f64d":                                         {},
+	"\U0001f64d\u200d\u2640":                             {},
+	"\U0001f64d\u200d\u2640\ufe0f":                       {},
+	"\U0001f64d\u200d\u2642":                             {},
+	"\U0001f64d\u200d\u2642\ufe0f":                       {},
+	"\U0001f64d\U0001f3fb":                               {},
+	"\U0001f64d\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f64d\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f64d\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f64d\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f64d\U0001f3fc":                               {},
+	"\U0001f64d\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f64d\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f64d\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f64d\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f64d\U0001f3fd":                               {},
+	"\U0001f64d\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f64d\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f64d\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f64d\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f64d\U0001f3fe":                               {},
+	"\U0001f64d\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f64d\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f64d\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f64d\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f64d\U0001f3ff":                               {},
+	"\U0001f64d\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f64d\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f64d\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f64d\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f64e":                                         {},
+	"\U0001f64e\u200d\u2640":                             {},
+	"\U0001f64e\u200d\u2640\ufe0f":                       {},
+	"\U0001f64e\u200d\u2642":                             {},
+	"\U0001f64e\u200d\u2642\ufe0f":                       {},
+	"\U0001f64e\U0001f3fb":                               {},
+	"\U0001f64e\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f64e\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f64e\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f64e\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f64e\U0001f3fc":                               {},
+	"\U0001f64e\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f64e\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f64e\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f64e\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f64e\U0001f3fd":                               {},
+	"\U0001f64e\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f64e\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f64e\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f64e\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f64e\U0001f3fe":                               {},
+	"\U0001f64e\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f64e\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f64e\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f64e\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f64e\U0001f3ff":                               {},
+	"\U0001f64e\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f64e\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f64e\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f64e\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f64f":                                         {},
+	"\U0001f64f\U0001f3fb":                               {},
+	"\U0001f64f\U0001f3fc":                               {},
+	"\U0001f64f\U0001f3fd":                               {},
+	"\U0001f64f\U0001f3fe":                               {},
+	"\U0001f64f\U0001f3ff":                               {},
+	"\U0001f680":                                         {},
+	"\U0001f681":                                         {},
+	"\U0001f682":                                         {},
+	"\U0001f683":                                         {},
+	"\U0001f684":                                         {},
+	"\U0001f685":                                         {},
+	"\U0001f686":                                         {},
+	"\U0001f687":                                         {},
+	"\U0001f688":                                         {},
+	"\U0001f689":                                         {},
+	"\U0001f68a":                                         {},
+	"\U0001f68b":                                         {},
+	"\U0001f68c":                                         {},
+	"\U0001f68d":                                         {},
+	"\U0001f68e":                                         {},
+	"\U0001f68f":                                         {},
+	"\U0001f690":                                         {},
+	"\U0001f691":                                         {},
+	"\U0001f692":                                         {},
+	"\U0001f693":                                         {},
+	"\U0001f694":                                         {},
+	"\U0001f695":                                         {},
+	"\U0001f696":                                         {},
+	"\U0001f697":                                         {},
+	"\U0001f698":                                         {},
+	"\U0001f699":                                         {},
+	"\U0001f69a":                                         {},
+	"\U0001f69b":                                         {},
+	"\U0001f69c":                                         {},
+	"\U0001f69d":                                         {},
+	"\U0001f69e":                                         {},
+	"\U0001f69f":                                         {},
+	"\U0001f6a0":                                         {},
+	"\U0001f6a1":                                         {},
+	"\U0001f6a2":                                         {},
+	"\U0001f6a3":                                         {},
+	"\U0001f6a3\u200d\u2640":                             {},
+	"\U0001f6a3\u200d\u2640\ufe0f":                       {},
+	"\U0001f6a3\u200d\u2642":                             {},
+	"\U0001f6a3\u200d\u2642\ufe0f":                       {},
+	"\U0001f6a3\U0001f3fb":                               {},
+	"\U0001f6a3\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f6a3\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f6a3\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f6a3\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f6a3\U0001f3fc":                               {},
+	"\U0001f6a3\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f6a3\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f6a3\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f6a3\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f6a3\U0001f3fd":                               {},
+	"\U0001f6a3\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f6a3\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f6a3\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f6a3\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f6a3\U0001f3fe":                               {},
+	"\U0001f6a3\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f6a3\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f6a3\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f6a3\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f6a3\U0001f3ff":                               {},
+	"\U0001f6a3\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f6a3\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f6a3\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f6a3\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f6a4":                                         {},
+	"\U0001f6a5":                                         {},
+	"\U0001f6a6":                                         {},
+	"\U0001f6a7":                                         {},
+	"\U0001f6a8":                                         {},
+	"\U0001f6a9":                                         {},
+	"\U0001f6aa":                                         {},
+	"\U0001f6ab":                                         {},
+	"\U0001f6ac":                                         {},
+	"\U0001f6ad":                                         {},
+	"\U0001f6ae":                                         {},
+	"\U0001f6af":                                         {},
+	"\U0001f6b0":                                         {},
+	"\U0001f6b1":                                         {},
+	"\U0001f6b2":                                         {},
+	"\U0001f6b3":                                         {},
+	"\U0001f6b4":                                         {},
+	"\U0001f6b4\u200d\u2640":                             {},
+	"\U0001f6b4\u200d\u2640\ufe0f":                       {},
+	"\U0001f6b4\u200d\u2642":                             {},
+	"\U0001f6b4\u200d\u2642\ufe0f":                       {},
+	"\U0001f6b4\U0001f3fb":                               {},
+	"\U0001f6b4\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f6b4\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f6b4\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f6b4\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f6b4\U0001f3fc":                               {},
+	"\U0001f6b4\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f6b4\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f6b4\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f6b4\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f6b4\U0001f3fd":                               {},
+	"\U0001f6b4\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f6b4\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f6b4\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f6b4\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f6b4\U0001f3fe":                               {},
+	"\U0001f6b4\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f6b4\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f6b4\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f6b4\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f6b4\U0001f3ff":                               {},
+	"\U0001f6b4\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f6b4\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f6b4\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f6b4\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f6b5":                                         {},
+	"\U0001f6b5\u200d\u2640":                             {},
+	"\U0001f6b5\u200d\u2640\ufe0f":                       {},
+	"\U0001f6b5\u200d\u2642":                             {},
+	"\U0001f6b5\u200d\u2642\ufe0f":                       {},
+	"\U0001f6b5\U0001f3fb":                               {},
+	"\U0001f6b5\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f6b5\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f6b5\U0001f3fb\u200d\u2642":                   {},
+	"\U0001f6b5\U0001f3fb\u200d\u2642\ufe0f":             {},
+	"\U0001f6b5\U0001f3fc":                               {},
+	"\U0001f6b5\U0001f3fc\u200d\u2640":                   {},
+	"\U0001f6b5\U0001f3fc\u200d\u2640\ufe0f":             {},
+	"\U0001f6b5\U0001f3fc\u200d\u2642":                   {},
+	"\U0001f6b5\U0001f3fc\u200d\u2642\ufe0f":             {},
+	"\U0001f6b5\U0001f3fd":                               {},
+	"\U0001f6b5\U0001f3fd\u200d\u2640":                   {},
+	"\U0001f6b5\U0001f3fd\u200d\u2640\ufe0f":             {},
+	"\U0001f6b5\U0001f3fd\u200d\u2642":                   {},
+	"\U0001f6b5\U0001f3fd\u200d\u2642\ufe0f":             {},
+	"\U0001f6b5\U0001f3fe":                               {},
+	"\U0001f6b5\U0001f3fe\u200d\u2640":                   {},
+	"\U0001f6b5\U0001f3fe\u200d\u2640\ufe0f":             {},
+	"\U0001f6b5\U0001f3fe\u200d\u2642":                   {},
+	"\U0001f6b5\U0001f3fe\u200d\u2642\ufe0f":             {},
+	"\U0001f6b5\U0001f3ff":                               {},
+	"\U0001f6b5\U0001f3ff\u200d\u2640":                   {},
+	"\U0001f6b5\U0001f3ff\u200d\u2640\ufe0f":             {},
+	"\U0001f6b5\U0001f3ff\u200d\u2642":                   {},
+	"\U0001f6b5\U0001f3ff\u200d\u2642\ufe0f":             {},
+	"\U0001f6b6":                                         {},
+	"\U0001f6b6\u200d\u2640":                             {},
+	"\U0001f6b6\u200d\u2640\u200d\u27a1":                 {},
+	"\U0001f6b6\u200d\u2640\u200d\u27a1\ufe0f":           {},
+	"\U0001f6b6\u200d\u2640\ufe0f":                       {},
+	"\U0001f6b6\u200d\u2640\ufe0f\u200d\u27a1":           {},
+	"\U0001f6b6\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":     {},
+	"\U0001f6b6\u200d\u2642":                             {},
+	"\U0001f6b6\u200d\u2642\u200d\u27a1":                 {},
+	"\U0001f6b6\u200d\u2642\u200d\u27a1\ufe0f":           {},
+	"\U0001f6b6\u200d\u2642\ufe0f":                       {},
+	"\U0001f6b6\u200d\u2642\ufe0f\u200d\u27a1":           {},
+	"\U0001f6b6\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":     {},
+	"\U0001f6b6\u200d\u27a1":                             {},
+	"\U0001f6b6\u200d\u27a1\ufe0f":                       {},
+	"\U0001f6b6\U0001f3fb":                               {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640":                   {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640\ufe0f":             {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1": {},
+	"\U0001f6b6\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fb\u200d\u27a1":                         {},
+	"\U0001f6b6\U0001f3fb\u200d\u27a1\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fc":                                     {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fc\u200d\u27a1":                         {},
+	"\U0001f6b6\U0001f3fc\u200d\u27a1\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fd":                                     {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fd\u200d\u27a1":                         {},
+	"\U0001f6b6\U0001f3fd\u200d\u27a1\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fe":                                     {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3fe\u200d\u27a1":                         {},
+	"\U0001f6b6\U0001f3fe\u200d\u27a1\ufe0f":                   {},
+	"\U0001f6b6\U0001f3ff":                                     {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642\u200d\u27a1":             {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642\u200d\u27a1\ufe0f":       {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1":       {},
+	"\U0001f6b6\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1\ufe0f": {},
+	"\U0001f6b6\U0001f3ff\u200d\u27a1":                         {},
+	"\U0001f6b6\U0001f3ff\u200d\u27a1\ufe0f":                   {},
+	"\U0001f6b7":                                               {},
+	"\U0001f6b8":                                               {},
+	"\U0001f6b9":                                               {},
+	"\U0001f6ba":                                               {},
+	"\U0001f6bb":                                               {},
+	"\U0001f6bc":                                               {},
+	"\U0001f6bd":                                               {},
+	"\U0001f6be":                                               {},
+	"\U0001f6bf":                                               {},
+	"\U0001f6c0":                                               {},
+	"\U0001f6c0\U0001f3fb":                                     {},
+	"\U0001f6c0\U0001f3fc":                                     {},
+	"\U0001f6c0\U0001f3fd":                                     {},
+	"\U0001f6c0\U0001f3fe":                                     {},
+	"\U0001f6c0\U0001f3ff":                                     {},
+	"\U0001f6c1":                                               {},
+	"\U0001f6c2":                                               {},
+	"\U0001f6c3":                                               {},
+	"\U0001f6c4":                                               {},
+	"\U0001f6c5":                                               {},
+	"\U0001f6cb":                                               {},
+	"\U0001f6cb\ufe0f":                                         {},
+	"\U0001f6cc":                                               {},
+	"\U0001f6cc\U0001f3fb":                                     {},
+	"\U0001f6cc\U0001f3fc":                                     {},
+	"\U0001f6cc\U0001f3fd":                                     {},
+	"\U0001f6cc\U0001f3fe":                                     {},
+	"\U0001f6cc\U0001f3ff":                                     {},
+	"\U0001f6cd":                                               {},
+	"\U0001f6cd\ufe0f":                                         {},
+	"\U0001f6ce":                                               {},
+	"\U0001f6ce\ufe0f":                                         {},
+	"\U0001f6cf":                                               {},
+	"\U0001f6cf\ufe0f":                                         {},
+	"\U0001f6d0":                                               {},
+	"\U0001f6d1":                                               {},
+	"\U0001f6d2":                                               {},
+	"\U0001f6d5":                                               {},
+	"\U0001f6d6":                                               {},
+	"\U0001f6d7":                                               {},
+	"\U0001f6dc":                                               {},
+	"\U0001f6dd":                                               {},
+	"\U0001f6de":                                               {},
+	"\U0001f6df":                                               {},
+	"\U0001f6e0":                                               {},
+	"\U0001f6e0\ufe0f":                                         {},
+	"\U0001f6e1":                                               {},
+	"\U0001f6e1\ufe0f":                                         {},
+	"\U0001f6e2":                                               {},
+	"\U0001f6e2\ufe0f":                                         {},
+	"\U0001f6e3":                                               {},
+	"\U0001f6e3\ufe0f":                                         {},
+	"\U0001f6e4":                                               {},
+	"\U0001f6e4\ufe0f":                                         {},
+	"\U0001f6e5":                                               {},
+	"\U0001f6e5\ufe0f":                                         {},
+	"\U0001f6e9":                                               {},
+	"\U0001f6e9\ufe0f":                                         {},
+	"\U0001f6eb":                                               {},
+	"\U0001f6ec":                                               {},
+	"\U0001f6f0":                                               {},
+	"\U0001f6f0\ufe0f":                                         {},
+	"\U0001f6f3":                                               {},
+	"\U0001f6f3\ufe0f":                                         {},
+	"\U0001f6f4":                                               {},
+	"\U0001f6f5":                                               {},
+	"\U0001f6f6":                                               {},
+	"\U0001f6f7":                                               {},
+	"\U0001f6f8":                                               {},
+	"\U0001f6f9":                                               {},
+	"\U0001f6fa":                                               {},
+	"\U0001f6fb":                                               {},
+	"\U0001f6fc":                                               {},
+	"\U0001f7e0":                                               {},
+	"\U0001f7e1":                                               {},
+	"\U0001f7e2":                                               {},
+	"\U0001f7e3":                                               {},
+	"\U0001f7e4":                                               {},
+	"\U0001f7e5":                                               {},
+	"\U0001f7e6":                                               {},
+	"\U0001f7e7":                                               {},
+	"\U0001f7e8":                                               {},
+	"\U0001f7e9":                                               {},
+	"\U0001f7ea":                                               {},
+	"\U0001f7eb":                                               {},
+	"\U0001f7f0":                                               {},
+	"\U0001f90c":                                               {},
+	"\U0001f90c\U0001f3fb":                                     {},
+	"\U0001f90c\U0001f3fc":                                     {},
+	"\U0001f90c\U0001f3fd":                                     {},
+	"\U0001f90c\U0001f3fe":                                     {},
+	"\U0001f90c\U0001f3ff":                                     {},
+	"\U0001f90d":                                               {},
+	"\U0001f90e":                                               {},
+	"\U0001f90f":                                               {},
+	"\U0001f90f\U0001f3fb":                                     {},
+	"\U0001f90f\U0001f3fc":                                     {},
+	"\U0001f90f\U0001f3fd":                                     {},
+	"\U0001f90f\U0001f3fe":                                     {},
+	"\U0001f90f\U0001f3ff":                                     {},
+	"\U0001f910":                                               {},
+	"\U0001f911":                                               {},
+	"\U0001f912":                                               {},
+	"\U0001f913":                                               {},
+	"\U0001f914":                                               {},
+	"\U0001f915":                                               {},
+	"\U0001f916":                                               {},
+	"\U0001f917":                                               {},
+	"\U0001f918":                                               {},
+	"\U0001f918\U0001f3fb":                                     {},
+	"\U0001f918\U0001f3fc":                                     {},
+	"\U0001f918\U0001f3fd":                                     {},
+	"\U0001f918\U0001f3fe":                                     {},
+	"\U0001f918\U0001f3ff":                                     {},
+	"\U0001f919":                                               {},
+	"\U0001f919\U0001f3fb":                                     {},
+	"\U0001f919\U0001f3fc":                                     {},
+	"\U0001f919\U0001f3fd":                                     {},
+	"\U0001f919\U0001f3fe":                                     {},
+	"\U0001f919\U0001f3ff":                                     {},
+	"\U0001f91a":                                               {},
+	"\U0001f91a\U0001f3fb":                                     {},
+	"\U0001f91a\U0001f3fc":                                     {},
+	"\U0001f91a\U0001f3fd":                                     {},
+	"\U0001f91a\U0001f3fe":                                     {},
+	"\U0001f91a\U0001f3ff":                                     {},
+	"\U0001f91b":                                               {},
+	"\U0001f91b\U0001f3fb":                                     {},
+	"\U0001f91b\U0001f3fc":                                     {},
+	"\U0001f91b\U0001f3fd":                                     {},
+	"\U0001f91b\U0001f3fe":                                     {},
+	"\U0001f91b\U0001f3ff":                                     {},
+	"\U0001f91c":                                               {},
+	"\U0001f91c\U0001f3fb":                                     {},
+	"\U0001f91c\U0001f3fc":                                     {},
+	"\U0001f91c\U0001f3fd":                                     {},
+	"\U0001f91c\U0001f3fe":                                     {},
+	"\U0001f91c\U0001f3ff":                                     {},
+	"\U0001f91d":                                               {},
+	"\U0001f91d\U0001f3fb":                                     {},
+	"\U0001f91d\U0001f3fc":                                     {},
+	"\U0001f91d\U0001f3fd":                                     {},
+	"\U0001f91d\U0001f3fe":                                     {},
+	"\U0001f91d\U0001f3ff":                                     {},
+	"\U0001f91e":                                               {},
+	"\U0001f91e\U0001f3fb":                                     {},
+	"\U0001f91e\U0001f3fc":                                     {},
+	"\U0001f91e\U0001f3fd":                                     {},
+	"\U0001f91e\U0001f3fe":                                     {},
+	"\U0001f91e\U0001f3ff":                                     {},
+	"\U0001f91f":                                               {},
+	"\U0001f91f\U0001f3fb":                                     {},
+	"\U0001f91f\U0001f3fc":                                     {},
+	"\U0001f91f\U0001f3fd":                                     {},
+	"\U0001f91f\U0001f3fe":                                     {},
+	"\U0001f91f\U0001f3ff":                                     {},
+	"\U0001f920":                                               {},
+	"\U0001f921":                                               {},
+	"\U0001f922":                                               {},
+	"\U0001f923":                                               {},
+	"\U0001f924":                                               {},
+	"\U0001f925":                                               {},
+	"\U0001f926":                                               {},
+	"\U0001f926\u200d\u2640":                                   {},
+	"\U0001f926\u200d\u2640\ufe0f":                             {},
+	"\U0001f926\u200d\u2642":                                   {},
+	"\U0001f926\u200d\u2642\ufe0f":                             {},
+	"\U0001f926\U0001f3fb":                                     {},
+	"\U0001f926\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f926\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f926\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f926\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f926\U0001f3fc":                                     {},
+	"\U0001f926\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f926\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f926\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f926\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f926\U0001f3fd":                                     {},
+	"\U0001f926\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f926\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f926\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f926\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f926\U0001f3fe":                                     {},
+	"\U0001f926\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f926\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f926\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f926\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f926\U0001f3ff":                                     {},
+	"\U0001f926\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f926\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f926\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f926\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f927":                                               {},
+	"\U0001f928":                                               {},
+	"\U0001f929":                                               {},
+	"\U0001f92a":                                               {},
+	"\U0001f92b":                                               {},
+	"\U0001f92c":                                               {},
+	"\U0001f92d":                                               {},
+	"\U0001f92e":                                               {},
+	"\U0001f92f":                                               {},
+	"\U0001f930":                                               {},
+	"\U0001f930\U0001f3fb":                                     {},
+	"\U0001f930\U0001f3fc":                                     {},
+	"\U0001f930\U0001f3fd":                                     {},
+	"\U0001f930\U0001f3fe":                                     {},
+	"\U0001f930\U0001f3ff":                                     {},
+	"\U0001f931":                                               {},
+	"\U0001f931\U0001f3fb":                                     {},
+	"\U0001f931\U0001f3fc":                                     {},
+	"\U0001f931\U0001f3fd":                                     {},
+	"\U0001f931\U0001f3fe":                                     {},
+	"\U0001f931\U0001f3ff":                                     {},
+	"\U0001f932":                                               {},
+	"\U0001f932\U0001f3fb":                                     {},
+	"\U0001f932\U0001f3fc":                                     {},
+	"\U0001f932\U0001f3fd":                                     {},
+	"\U0001f932\U0001f3fe":                                     {},
+	"\U0001f932\U0001f3ff":                                     {},
+	"\U0001f933":                                               {},
+	"\U0001f933\U0001f3fb":                                     {},
+	"\U0001f933\U0001f3fc":                                     {},
+	"\U0001f933\U0001f3fd":                                     {},
+	"\U0001f933\U0001f3fe":                                     {},
+	"\U0001f933\U0001f3ff":                                     {},
+	"\U0001f934":                                               {},
+	"\U0001f934\U0001f3fb":                                     {},
+	"\U0001f934\U0001f3fc":                                     {},
+	"\U0001f934\U0001f3fd":                                     {},
+	"\U0001f934\U0001f3fe":                                     {},
+	"\U0001f934\U0001f3ff":                                     {},
+	"\U0001f935":                                               {},
+	"\U0001f935\u200d\u2640":                                   {},
+	"\U0001f935\u200d\u2640\ufe0f":                             {},
+	"\U0001f935\u200d\u2642":                                   {},
+	"\U0001f935\u200d\u2642\ufe0f":                             {},
+	"\U0001f935\U0001f3fb":                                     {},
+	"\U0001f935\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f935\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f935\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f935\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f935\U0001f3fc":                                     {},
+	"\U0001f935\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f935\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f935\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f935\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f935\U0001f3fd":                                     {},
+	"\U0001f935\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f935\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f935\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f935\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f935\U0001f3fe":                                     {},
+	"\U0001f935\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f935\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f935\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f935\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f935\U0001f3ff":                                     {},
+	"\U0001f935\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f935\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f935\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f935\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f936":                                               {},
+	"\U0001f936\U0001f3fb":                                     {},
+	"\U0001f936\U0001f3fc":                                     {},
+	"\U0001f936\U0001f3fd":                                     {},
+	"\U0001f936\U0001f3fe":                                     {},
+	"\U0001f936\U0001f3ff":                                     {},
+	"\U0001f937":                                               {},
+	"\U0001f937\u200d\u2640":                                   {},
+	"\U0001f937\u200d\u2640\ufe0f":                             {},
+	"\U0001f937\u200d\u2642":                                   {},
+	"\U0001f937\u200d\u2642\ufe0f":                             {},
+	"\U0001f937\U0001f3fb":                                     {},
+	"\U0001f937\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f937\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f937\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f937\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f937\U0001f3fc":                                     {},
+	"\U0001f937\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f937\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f937\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f937\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f937\U0001f3fd":                                     {},
+	"\U0001f937\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f937\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f937\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f937\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f937\U0001f3fe":                                     {},
+	"\U0001f937\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f937\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f937\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f937\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f937\U0001f3ff":                                     {},
+	"\U0001f937\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f937\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f937\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f937\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f938":                                               {},
+	"\U0001f938\u200d\u2640":                                   {},
+	"\U0001f938\u200d\u2640\ufe0f":                             {},
+	"\U0001f938\u200d\u2642":                                   {},
+	"\U0001f938\u200d\u2642\ufe0f":                             {},
+	"\U0001f938\U0001f3fb":                                     {},
+	"\U0001f938\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f938\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f938\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f938\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f938\U0001f3fc":                                     {},
+	"\U0001f938\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f938\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f938\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f938\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f938\U0001f3fd":                                     {},
+	"\U0001f938\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f938\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f938\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f938\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f938\U0001f3fe":                                     {},
+	"\U0001f938\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f938\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f938\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f938\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f938\U0001f3ff":                                     {},
+	"\U0001f938\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f938\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f938\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f938\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f939":                                               {},
+	"\U0001f939\u200d\u2640":                                   {},
+	"\U0001f939\u200d\u2640\ufe0f":                             {},
+	"\U0001f939\u200d\u2642":                                   {},
+	"\U0001f939\u200d\u2642\ufe0f":                             {},
+	"\U0001f939\U0001f3fb":                                     {},
+	"\U0001f939\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f939\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f939\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f939\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f939\U0001f3fc":                                     {},
+	"\U0001f939\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f939\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f939\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f939\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f939\U0001f3fd":                                     {},
+	"\U0001f939\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f939\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f939\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f939\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f939\U0001f3fe":                                     {},
+	"\U0001f939\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f939\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f939\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f939\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f939\U0001f3ff":                                     {},
+	"\U0001f939\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f939\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f939\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f939\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f93a":                                               {},
+	"\U0001f93c":                                               {},
+	"\U0001f93c\u200d\u2640":                                   {},
+	"\U0001f93c\u200d\u2640\ufe0f":                             {},
+	"\U0001f93c\u200d\u2642":                                   {},
+	"\U0001f93c\u200d\u2642\ufe0f":                             {},
+	"\U0001f93d":                                               {},
+	"\U0001f93d\u200d\u2640":                                   {},
+	"\U0001f93d\u200d\u2640\ufe0f":                             {},
+	"\U0001f93d\u200d\u2642":                                   {},
+	"\U0001f93d\u200d\u2642\ufe0f":                             {},
+	"\U0001f93d\U0001f3fb":                                     {},
+	"\U0001f93d\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f93d\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f93d\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f93d\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f93d\U0001f3fc":                                     {},
+	"\U0001f93d\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f93d\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f93d\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f93d\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f93d\U0001f3fd":                                     {},
+	"\U0001f93d\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f93d\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f93d\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f93d\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f93d\U0001f3fe":                                     {},
+	"\U0001f93d\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f93d\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f93d\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f93d\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f93d\U0001f3ff":                                     {},
+	"\U0001f93d\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f93d\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f93d\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f93d\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f93e":                                               {},
+	"\U0001f93e\u200d\u2640":                                   {},
+	"\U0001f93e\u200d\u2640\ufe0f":                             {},
+	"\U0001f93e\u200d\u2642":                                   {},
+	"\U0001f93e\u200d\u2642\ufe0f":                             {},
+	"\U0001f93e\U0001f3fb":                                     {},
+	"\U0001f93e\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f93e\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f93e\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f93e\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f93e\U0001f3fc":                                     {},
+	"\U0001f93e\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f93e\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f93e\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f93e\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f93e\U0001f3fd":                                     {},
+	"\U0001f93e\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f93e\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f93e\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f93e\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f93e\U0001f3fe":                                     {},
+	"\U0001f93e\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f93e\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f93e\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f93e\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f93e\U0001f3ff":                                     {},
+	"\U0001f93e\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f93e\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f93e\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f93e\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f93f":                                               {},
+	"\U0001f940":                                               {},
+	"\U0001f941":                                               {},
+	"\U0001f942":                                               {},
+	"\U0001f943":                                               {},
+	"\U0001f944":                                               {},
+	"\U0001f945":                                               {},
+	"\U0001f947":                                               {},
+	"\U0001f948":                                               {},
+	"\U0001f949":                                               {},
+	"\U0001f94a":                                               {},
+	"\U0001f94b":                                               {},
+	"\U0001f94c":                                               {},
+	"\U0001f94d":                                               {},
+	"\U0001f94e":                                               {},
+	"\U0001f94f":                                               {},
+	"\U0001f950":                                               {},
+	"\U0001f951":                                               {},
+	"\U0001f952":                                               {},
+	"\U0001f953":                                               {},
+	"\U0001f954":                                               {},
+	"\U0001f955":                                               {},
+	"\U0001f956":                                               {},
+	"\U0001f957":                                               {},
+	"\U0001f958":                                               {},
+	"\U0001f959":                                               {},
+	"\U0001f95a":                                               {},
+	"\U0001f95b":                                               {},
+	"\U0001f95c":                                               {},
+	"\U0001f95d":                                               {},
+	"\U0001f95e":                                               {},
+	"\U0001f95f":                                               {},
+	"\U0001f960":                                               {},
+	"\U0001f961":                                               {},
+	"\U0001f962":                                               {},
+	"\U0001f963":                                               {},
+	"\U0001f964":                                               {},
+	"\U0001f965":                                               {},
+	"\U0001f966":                                               {},
+	"\U0001f967":                                               {},
+	"\U0001f968":                                               {},
+	"\U0001f969":                                               {},
+	"\U0001f96a":                                               {},
+	"\U0001f96b":                                               {},
+	"\U0001f96c":                                               {},
+	"\U0001f96d":                                               {},
+	"\U0001f96e":                                               {},
+	"\U0001f96f":                                               {},
+	"\U0001f970":                                               {},
+	"\U0001f971":                                               {},
+	"\U0001f972":                                               {},
+	"\U0001f973":                                               {},
+	"\U0001f974":                                               {},
+	"\U0001f975":                                               {},
+	"\U0001f976":                                               {},
+	"\U0001f977":                                               {},
+	"\U0001f977\U0001f3fb":                                     {},
+	"\U0001f977\U0001f3fc":                                     {},
+	"\U0001f977\U0001f3fd":                                     {},
+	"\U0001f977\U0001f3fe":                                     {},
+	"\U0001f977\U0001f3ff":                                     {},
+	"\U0001f978":                                               {},
+	"\U0001f979":                                               {},
+	"\U0001f97a":                                               {},
+	"\U0001f97b":                                               {},
+	"\U0001f97c":                                               {},
+	"\U0001f97d":                                               {},
+	"\U0001f97e":                                               {},
+	"\U0001f97f":                                               {},
+	"\U0001f980":                                               {},
+	"\U0001f981":                                               {},
+	"\U0001f982":                                               {},
+	"\U0001f983":                                               {},
+	"\U0001f984":                                               {},
+	"\U0001f985":                                               {},
+	"\U0001f986":                                               {},
+	"\U0001f987":                                               {},
+	"\U0001f988":                                               {},
+	"\U0001f989":                                               {},
+	"\U0001f98a":                                               {},
+	"\U0001f98b":                                               {},
+	"\U0001f98c":                                               {},
+	"\U0001f98d":                                               {},
+	"\U0001f98e":                                               {},
+	"\U0001f98f":                                               {},
+	"\U0001f990":                                               {},
+	"\U0001f991":                                               {},
+	"\U0001f992":                                               {},
+	"\U0001f993":                                               {},
+	"\U0001f994":                                               {},
+	"\U0001f995":                                               {},
+	"\U0001f996":                                               {},
+	"\U0001f997":                                               {},
+	"\U0001f998":                                               {},
+	"\U0001f999":                                               {},
+	"\U0001f99a":                                               {},
+	"\U0001f99b":                                               {},
+	"\U0001f99c":                                               {},
+	"\U0001f99d":                                               {},
+	"\U0001f99e":                                               {},
+	"\U0001f99f":                                               {},
+	"\U0001f9a0":                                               {},
+	"\U0001f9a1":                                               {},
+	"\U0001f9a2":                                               {},
+	"\U0001f9a3":                                               {},
+	"\U0001f9a4":                                               {},
+	"\U0001f9a5":                                               {},
+	"\U0001f9a6":                                               {},
+	"\U0001f9a7":                                               {},
+	"\U0001f9a8":                                               {},
+	"\U0001f9a9":                                               {},
+	"\U0001f9aa":                                               {},
+	"\U0001f9ab":                                               {},
+	"\U0001f9ac":                                               {},
+	"\U0001f9ad":                                               {},
+	"\U0001f9ae":                                               {},
+	"\U0001f9af":                                               {},
+	"\U0001f9b0":                                               {},
+	"\U0001f9b1":                                               {},
+	"\U0001f9b2":                                               {},
+	"\U0001f9b3":                                               {},
+	"\U0001f9b4":                                               {},
+	"\U0001f9b5":                                               {},
+	"\U0001f9b5\U0001f3fb":                                     {},
+	"\U0001f9b5\U0001f3fc":                                     {},
+	"\U0001f9b5\U0001f3fd":                                     {},
+	"\U0001f9b5\U0001f3fe":                                     {},
+	"\U0001f9b5\U0001f3ff":                                     {},
+	"\U0001f9b6":                                               {},
+	"\U0001f9b6\U0001f3fb":                                     {},
+	"\U0001f9b6\U0001f3fc":                                     {},
+	"\U0001f9b6\U0001f3fd":                                     {},
+	"\U0001f9b6\U0001f3fe":                                     {},
+	"\U0001f9b6\U0001f3ff":                                     {},
+	"\U0001f9b7":                                               {},
+	"\U0001f9b8":                                               {},
+	"\U0001f9b8\u200d\u2640":                                   {},
+	"\U0001f9b8\u200d\u2640\ufe0f":                             {},
+	"\U0001f9b8\u200d\u2642":                                   {},
+	"\U0001f9b8\u200d\u2642\ufe0f":                             {},
+	"\U0001f9b8\U0001f3fb":                                     {},
+	"\U0001f9b8\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f9b8\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f9b8\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fc":                                     {},
+	"\U0001f9b8\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f9b8\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f9b8\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fd":                                     {},
+	"\U0001f9b8\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f9b8\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f9b8\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fe":                                     {},
+	"\U0001f9b8\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f9b8\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b8\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f9b8\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b8\U0001f3ff":                                     {},
+	"\U0001f9b8\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f9b8\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b8\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f9b8\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b9":                                               {},
+	"\U0001f9b9\u200d\u2640":                                   {},
+	"\U0001f9b9\u200d\u2640\ufe0f":                             {},
+	"\U0001f9b9\u200d\u2642":                                   {},
+	"\U0001f9b9\u200d\u2642\ufe0f":                             {},
+	"\U0001f9b9\U0001f3fb":                                     {},
+	"\U0001f9b9\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f9b9\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f9b9\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fc":                                     {},
+	"\U0001f9b9\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f9b9\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f9b9\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fd":                                     {},
+	"\U0001f9b9\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f9b9\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f9b9\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fe":                                     {},
+	"\U0001f9b9\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f9b9\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b9\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f9b9\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f9b9\U0001f3ff":                                     {},
+	"\U0001f9b9\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f9b9\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f9b9\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f9b9\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f9ba":                                               {},
+	"\U0001f9bb":                                               {},
+	"\U0001f9bb\U0001f3fb":                                     {},
+	"\U0001f9bb\U0001f3fc":                                     {},
+	"\U0001f9bb\U0001f3fd":                                     {},
+	"\U0001f9bb\U0001f3fe":                                     {},
+	"\U0001f9bb\U0001f3ff":                                     {},
+	"\U0001f9bc":                                               {},
+	"\U0001f9bd":                                               {},
+	"\U0001f9be":                                               {},
+	"\U0001f9bf":                                               {},
+	"\U0001f9c0":                                               {},
+	"\U0001f9c1":                                               {},
+	"\U0001f9c2":                                               {},
+	"\U0001f9c3":                                               {},
+	"\U0001f9c4":                                               {},
+	"\U0001f9c5":                                               {},
+	"\U0001f9c6":                                               {},
+	"\U0001f9c7":                                               {},
+	"\U0001f9c8":                                               {},
+	"\U0001f9c9":                                               {},
+	"\U0001f9ca":                                               {},
+	"\U0001f9cb":                                               {},
+	"\U0001f9cc":                                               {},
+	"\U0001f9cd":                                               {},
+	"\U0001f9cd\u200d\u2640":                                   {},
+	"\U0001f9cd\u200d\u2640\ufe0f":                             {},
+	"\U0001f9cd\u200d\u2642":                                   {},
+	"\U0001f9cd\u200d\u2642\ufe0f":                             {},
+	"\U0001f9cd\U0001f3fb":                                     {},
+	"\U0001f9cd\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f9cd\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fb\u200d\u2642":                         {},
+	"\U0001f9cd\U0001f3fb\u200d\u2642\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fc":                                     {},
+	"\U0001f9cd\U0001f3fc\u200d\u2640":                         {},
+	"\U0001f9cd\U0001f3fc\u200d\u2640\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fc\u200d\u2642":                         {},
+	"\U0001f9cd\U0001f3fc\u200d\u2642\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fd":                                     {},
+	"\U0001f9cd\U0001f3fd\u200d\u2640":                         {},
+	"\U0001f9cd\U0001f3fd\u200d\u2640\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fd\u200d\u2642":                         {},
+	"\U0001f9cd\U0001f3fd\u200d\u2642\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fe":                                     {},
+	"\U0001f9cd\U0001f3fe\u200d\u2640":                         {},
+	"\U0001f9cd\U0001f3fe\u200d\u2640\ufe0f":                   {},
+	"\U0001f9cd\U0001f3fe\u200d\u2642":                         {},
+	"\U0001f9cd\U0001f3fe\u200d\u2642\ufe0f":                   {},
+	"\U0001f9cd\U0001f3ff":                                     {},
+	"\U0001f9cd\U0001f3ff\u200d\u2640":                         {},
+	"\U0001f9cd\U0001f3ff\u200d\u2640\ufe0f":                   {},
+	"\U0001f9cd\U0001f3ff\u200d\u2642":                         {},
+	"\U0001f9cd\U0001f3ff\u200d\u2642\ufe0f":                   {},
+	"\U0001f9ce":                                               {},
+	"\U0001f9ce\u200d\u2640":                                   {},
+	"\U0001f9ce\u200d\u2640\u200d\u27a1":                       {},
+	"\U0001f9ce\u200d\u2640\u200d\u27a1\ufe0f":                 {},
+	"\U0001f9ce\u200d\u2640\ufe0f":                             {},
+	"\U0001f9ce\u200d\u2640\ufe0f\u200d\u27a1":                 {},
+	"\U0001f9ce\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":           {},
+	"\U0001f9ce\u200d\u2642":                                   {},
+	"\U0001f9ce\u200d\u2642\u200d\u27a1":                       {},
+	"\U0001f9ce\u200d\u2642\u200d\u27a1\ufe0f":                 {},
+	"\U0001f9ce\u200d\u2642\ufe0f":                             {},
+	"\U0001f9ce\u200d\u2642\ufe0f\u200d\u27a1":                 {},
+	"\U0001f9ce\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":           {},
+	"\U0001f9ce\u200d\u27a1":                                   {},
+	"\U0001f9ce\u200d\u27a1\ufe0f":                             {},
+	"\U0001f9ce\U0001f3fb":                                     {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640":                         {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640\u200d\u27a1":             {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640\u200d\u27a1\ufe0f":       {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640\ufe0f":                   {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1":       {},
+	"\U0001f9ce\U0001f3fb\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642":                                                 {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fb\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fb\u200d\u27a1":                                                 {},
+	"\U0001f9ce\U0001f3fb\u200d\u27a1\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fc":                                                             {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640":                                                 {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fc\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642":                                                 {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fc\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fc\u200d\u27a1":                                                 {},
+	"\U0001f9ce\U0001f3fc\u200d\u27a1\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fd":                                                             {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640":                                                 {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fd\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642":                                                 {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fd\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fd\u200d\u27a1":                                                 {},
+	"\U0001f9ce\U0001f3fd\u200d\u27a1\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fe":                                                             {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640":                                                 {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fe\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642":                                                 {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3fe\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3fe\u200d\u27a1":                                                 {},
+	"\U0001f9ce\U0001f3fe\u200d\u27a1\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3ff":                                                             {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640":                                                 {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3ff\u200d\u2640\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642":                                                 {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642\u200d\u27a1":                                     {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642\u200d\u27a1\ufe0f":                               {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1":                               {},
+	"\U0001f9ce\U0001f3ff\u200d\u2642\ufe0f\u200d\u27a1\ufe0f":                         {},
+	"\U0001f9ce\U0001f3ff\u200d\u27a1":                                                 {},
+	"\U0001f9ce\U0001f3ff\u200d\u27a1\ufe0f":                                           {},
+	"\U0001f9cf":                                                                       {},
+	"\U0001f9cf\u200d\u2640":                                                           {},
+	"\U0001f9cf\u200d\u2640\ufe0f":                                                     {},
+	"\U0001f9cf\u200d\u2642":                                                           {},
+	"\U0001f9cf\u200d\u2642\ufe0f":                                                     {},
+	"\U0001f9cf\U0001f3fb":                                                             {},
+	"\U0001f9cf\U0001f3fb\u200d\u2640":                                                 {},
+	"\U0001f9cf\U0001f3fb\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fb\u200d\u2642":                                                 {},
+	"\U0001f9cf\U0001f3fb\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fc":                                                             {},
+	"\U0001f9cf\U0001f3fc\u200d\u2640":                                                 {},
+	"\U0001f9cf\U0001f3fc\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fc\u200d\u2642":                                                 {},
+	"\U0001f9cf\U0001f3fc\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fd":                                                             {},
+	"\U0001f9cf\U0001f3fd\u200d\u2640":                                                 {},
+	"\U0001f9cf\U0001f3fd\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fd\u200d\u2642":                                                 {},
+	"\U0001f9cf\U0001f3fd\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fe":                                                             {},
+	"\U0001f9cf\U0001f3fe\u200d\u2640":                                                 {},
+	"\U0001f9cf\U0001f3fe\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3fe\u200d\u2642":                                                 {},
+	"\U0001f9cf\U0001f3fe\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3ff":                                                             {},
+	"\U0001f9cf\U0001f3ff\u200d\u2640":                                                 {},
+	"\U0001f9cf\U0001f3ff\u200d\u2640\ufe0f":                                           {},
+	"\U0001f9cf\U0001f3ff\u200d\u2642":                                                 {},
+	"\U0001f9cf\U0001f3ff\u200d\u2642\ufe0f":                                           {},
+	"\U0001f9d0":                                                                       {},
+	"\U0001f9d1":                                                                       {},
+	"\U0001f9d1\u200d\u2695":                                                           {},
+	"\U0001f9d1\u200d\u2695\ufe0f":                                                     {},
+	"\U0001f9d1\u200d\u2696":                                                           {},
+	"\U0001f9d1\u200d\u2696\ufe0f":                                                     {},
+	"\U0001f9d1\u200d\u2708":                                                           {},
+	"\U0001f9d1\u200d\u2708\ufe0f":                                                     {},
+	"\U0001f9d1\u200d\U0001f33e":                                                       {},
+	"\U0001f9d1\u200d\U0001f373":                                                       {},
+	"\U0001f9d1\u200d\U0001f37c":                                                       {},
+	"\U0001f9d1\u200d\U0001f384":                                                       {},
+	"\U0001f9d1\u200d\U0001f393":                                                       {},
+	"\U0001f9d1\u200d\U0001f3a4":                                                       {},
+	"\U0001f9d1\u200d\U0001f3a8":                                                       {},
+	"\U0001f9d1\u200d\U0001f3eb":                                                       {},
+	"\U0001f9d1\u200d\U0001f3ed":                                                       {},
+	"\U0001f9d1\u200d\U0001f4bb":                                                       {},
+	"\U0001f9d1\u200d\U0001f4bc":                                                       {},
+	"\U0001f9d1\u200d\U0001f527":                                                       {},
+	"\U0001f9d1\u200d\U0001f52c":                                                       {},
+	"\U0001f9d1\u200d\U0001f680":                                                       {},
+	"\U0001f9d1\u200d\U0001f692":                                                       {},
+	"\U0001f9d1\u200d\U0001f91d\u200d\U0001f9d1":                                       {},
+	"\U0001f9d1\u200d\U0001f9af":                                                       {},
+	"\U0001f9d1\u200d\U0001f9af\u200d\u27a1":                                           {},
+	"\U0001f9d1\u200d\U0001f9af\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f9d1\u200d\U0001f9b0":                                                       {},
+	"\U0001f9d1\u200d\U0001f9b1":                                                       {},
+	"\U0001f9d1\u200d\U0001f9b2":                                                       {},
+	"\U0001f9d1\u200d\U0001f9b3":                                                       {},
+	"\U0001f9d1\u200d\U0001f9bc":                                                       {},
+	"\U0001f9d1\u200d\U0001f9bc\u200d\u27a1":                                           {},
+	"\U0001f9d1\u200d\U0001f9bc\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f9d1\u200d\U0001f9bd":                                                       {},
+	"\U0001f9d1\u200d\U0001f9bd\u200d\u27a1":                                           {},
+	"\U0001f9d1\u200d\U0001f9bd\u200d\u27a1\ufe0f":                                     {},
+	"\U0001f9d1\u200d\U0001f9d1\u200d\U0001f9d2":                                       {},
+	"\U0001f9d1\u200d\U0001f9d1\u200d\U0001f9d2\u200d\U0001f9d2":                       {},
+	"\U0001f9d1\u200d\U0001f9d2":                                                       {},
+	"\U0001f9d1\u200d\U0001f9d2\u200d\U0001f9d2":                                       {},
+	"\U0001f9d1\U0001f3fb":                                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\u2695":                                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2695\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fb\u200d\u2696":                                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2696\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fb\u200d\u2708":                                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2708\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc":       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd":       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe":       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff":       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f9d1\U0001f3fc":                       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f9d1\U0001f3fd":                       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f9d1\U0001f3fe":                       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\u200d\U0001f9d1\U0001f3ff":                       {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc": {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd": {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe": {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff": {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fc":                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fd":                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fe":                 {},
+	"\U0001f9d1\U0001f3fb\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3ff":                 {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f33e":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f373":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f37c":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f384":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f393":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f3a4":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f3a8":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f3eb":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f3ed":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f4bb":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f4bc":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f527":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f52c":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f680":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f692":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fb":                   {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fc":                   {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fd":                   {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fe":                   {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f91d\u200d\U0001f9d1\U0001f3ff":                   {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9af":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9b0":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9b1":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9b2":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9b3":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bc":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bd":                                             {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fb\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fc":                                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\u2695":                                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2695\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fc\u200d\u2696":                                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2696\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fc\u200d\u2708":                                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2708\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb":       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd":       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe":       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff":       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f9d1\U0001f3fb":                       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f9d1\U0001f3fd":                       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f9d1\U0001f3fe":                       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\u200d\U0001f9d1\U0001f3ff":                       {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb": {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd": {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe": {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff": {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fb":                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fd":                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fe":                 {},
+	"\U0001f9d1\U0001f3fc\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3ff":                 {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f33e":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f373":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f37c":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f384":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f393":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f3a4":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f3a8":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f3eb":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f3ed":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f4bb":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f4bc":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f527":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f52c":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f680":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f692":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fb":                   {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fc":                   {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fd":                   {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fe":                   {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f91d\u200d\U0001f9d1\U0001f3ff":                   {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9af":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9b0":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9b1":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9b2":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9b3":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bc":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bd":                                             {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fc\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fd":                                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\u2695":                                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2695\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fd\u200d\u2696":                                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2696\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fd\u200d\u2708":                                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2708\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb":       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc":       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe":       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff":       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f9d1\U0001f3fb":                       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f9d1\U0001f3fc":                       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f9d1\U0001f3fe":                       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\u200d\U0001f9d1\U0001f3ff":                       {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb": {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc": {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe": {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff": {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fb":                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fc":                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fe":                 {},
+	"\U0001f9d1\U0001f3fd\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3ff":                 {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f33e":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f373":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f37c":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f384":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f393":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f3a4":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f3a8":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f3eb":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f3ed":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f4bb":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f4bc":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f527":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f52c":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f680":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f692":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fb":                   {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fc":                   {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fd":                   {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fe":                   {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f91d\u200d\U0001f9d1\U0001f3ff":                   {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9af":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9b0":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9b1":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9b2":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9b3":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bc":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bd":                                             {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fd\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fe":                                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\u2695":                                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2695\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fe\u200d\u2696":                                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2696\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fe\u200d\u2708":                                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2708\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb":       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc":       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd":       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff":       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f9d1\U0001f3fb":                       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f9d1\U0001f3fc":                       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f9d1\U0001f3fd":                       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\u200d\U0001f9d1\U0001f3ff":                       {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb": {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc": {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd": {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3ff": {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fb":                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fc":                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fd":                 {},
+	"\U0001f9d1\U0001f3fe\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3ff":                 {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f33e":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f373":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f37c":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f384":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f393":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f3a4":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f3a8":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f3eb":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f3ed":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f4bb":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f4bc":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f527":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f52c":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f680":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f692":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fb":                   {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fc":                   {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fd":                   {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fe":                   {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f91d\u200d\U0001f9d1\U0001f3ff":                   {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9af":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9b0":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9b1":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9b2":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9b3":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bc":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bd":                                             {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3fe\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3ff":                                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\u2695":                                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2695\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3ff\u200d\u2696":                                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2696\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3ff\u200d\u2708":                                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2708\ufe0f":                                           {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb":       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc":       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd":       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe":       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f9d1\U0001f3fb":                       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f9d1\U0001f3fc":                       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f9d1\U0001f3fd":                       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\u200d\U0001f9d1\U0001f3fe":                       {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fb": {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fc": {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fd": {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f9d1\U0001f3fe": {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fb":                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fc":                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fd":                 {},
+	"\U0001f9d1\U0001f3ff\u200d\u2764\ufe0f\u200d\U0001f9d1\U0001f3fe":                 {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f33e":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f373":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f37c":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f384":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f393":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f3a4":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f3a8":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f3eb":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f3ed":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f4bb":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f4bc":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f527":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f52c":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f680":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f692":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fb":                   {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fc":                   {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fd":                   {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f91d\u200d\U0001f9d1\U0001f3fe":                   {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f91d\u200d\U0001f9d1\U0001f3ff":                   {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9af":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9af\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9af\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9b0":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9b1":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9b2":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9b3":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bc":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bc\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bc\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bd":                                             {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bd\u200d\u27a1":                                 {},
+	"\U0001f9d1\U0001f3ff\u200d\U0001f9bd\u200d\u27a1\ufe0f":                           {},
+	"\U0001f9d2":                                     {},
+	"\U0001f9d2\U0001f3fb":                           {},
+	"\U0001f9d2\U0001f3fc":                           {},
+	"\U0001f9d2\U0001f3fd":                           {},
+	"\U0001f9d2\U0001f3fe":                           {},
+	"\U0001f9d2\U0001f3ff":                           {},
+	"\U0001f9d3":                                     {},
+	"\U0001f9d3\U0001f3fb":                           {},
+	"\U0001f9d3\U0001f3fc":                           {},
+	"\U0001f9d3\U0001f3fd":                           {},
+	"\U0001f9d3\U0001f3fe":                           {},
+	"\U0001f9d3\U0001f3ff":                           {},
+	"\U0001f9d4":                                     {},
+	"\U0001f9d4\u200d\u2640":                         {},
+	"\U0001f9d4\u200d\u2640\ufe0f":                   {},
+	"\U0001f9d4\u200d\u2642":                         {},
+	"\U0001f9d4\u200d\u2642\ufe0f":                   {},
+	"\U0001f9d4\U0001f3fb":                           {},
+	"\U0001f9d4\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9d4\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9d4\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9d4\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9d4\U0001f3fc":                           {},
+	"\U0001f9d4\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9d4\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9d4\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9d4\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9d4\U0001f3fd":                           {},
+	"\U0001f9d4\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9d4\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9d4\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9d4\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9d4\U0001f3fe":                           {},
+	"\U0001f9d4\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9d4\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9d4\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9d4\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9d4\U0001f3ff":                           {},
+	"\U0001f9d4\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9d4\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9d4\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9d4\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9d5":                                     {},
+	"\U0001f9d5\U0001f3fb":                           {},
+	"\U0001f9d5\U0001f3fc":                           {},
+	"\U0001f9d5\U0001f3fd":                           {},
+	"\U0001f9d5\U0001f3fe":                           {},
+	"\U0001f9d5\U0001f3ff":                           {},
+	"\U0001f9d6":                                     {},
+	"\U0001f9d6\u200d\u2640":                         {},
+	"\U0001f9d6\u200d\u2640\ufe0f":                   {},
+	"\U0001f9d6\u200d\u2642":                         {},
+	"\U0001f9d6\u200d\u2642\ufe0f":                   {},
+	"\U0001f9d6\U0001f3fb":                           {},
+	"\U0001f9d6\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9d6\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9d6\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9d6\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9d6\U0001f3fc":                           {},
+	"\U0001f9d6\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9d6\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9d6\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9d6\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9d6\U0001f3fd":                           {},
+	"\U0001f9d6\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9d6\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9d6\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9d6\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9d6\U0001f3fe":                           {},
+	"\U0001f9d6\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9d6\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9d6\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9d6\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9d6\U0001f3ff":                           {},
+	"\U0001f9d6\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9d6\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9d6\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9d6\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9d7":                                     {},
+	"\U0001f9d7\u200d\u2640":                         {},
+	"\U0001f9d7\u200d\u2640\ufe0f":                   {},
+	"\U0001f9d7\u200d\u2642":                         {},
+	"\U0001f9d7\u200d\u2642\ufe0f":                   {},
+	"\U0001f9d7\U0001f3fb":                           {},
+	"\U0001f9d7\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9d7\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9d7\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9d7\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9d7\U0001f3fc":                           {},
+	"\U0001f9d7\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9d7\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9d7\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9d7\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9d7\U0001f3fd":                           {},
+	"\U0001f9d7\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9d7\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9d7\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9d7\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9d7\U0001f3fe":                           {},
+	"\U0001f9d7\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9d7\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9d7\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9d7\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9d7\U0001f3ff":                           {},
+	"\U0001f9d7\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9d7\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9d7\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9d7\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9d8":                                     {},
+	"\U0001f9d8\u200d\u2640":                         {},
+	"\U0001f9d8\u200d\u2640\ufe0f":                   {},
+	"\U0001f9d8\u200d\u2642":                         {},
+	"\U0001f9d8\u200d\u2642\ufe0f":                   {},
+	"\U0001f9d8\U0001f3fb":                           {},
+	"\U0001f9d8\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9d8\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9d8\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9d8\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9d8\U0001f3fc":                           {},
+	"\U0001f9d8\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9d8\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9d8\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9d8\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9d8\U0001f3fd":                           {},
+	"\U0001f9d8\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9d8\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9d8\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9d8\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9d8\U0001f3fe":                           {},
+	"\U0001f9d8\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9d8\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9d8\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9d8\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9d8\U0001f3ff":                           {},
+	"\U0001f9d8\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9d8\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9d8\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9d8\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9d9":                                     {},
+	"\U0001f9d9\u200d\u2640":                         {},
+	"\U0001f9d9\u200d\u2640\ufe0f":                   {},
+	"\U0001f9d9\u200d\u2642":                         {},
+	"\U0001f9d9\u200d\u2642\ufe0f":                   {},
+	"\U0001f9d9\U0001f3fb":                           {},
+	"\U0001f9d9\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9d9\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9d9\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9d9\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9d9\U0001f3fc":                           {},
+	"\U0001f9d9\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9d9\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9d9\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9d9\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9d9\U0001f3fd":                           {},
+	"\U0001f9d9\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9d9\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9d9\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9d9\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9d9\U0001f3fe":                           {},
+	"\U0001f9d9\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9d9\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9d9\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9d9\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9d9\U0001f3ff":                           {},
+	"\U0001f9d9\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9d9\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9d9\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9d9\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9da":                                     {},
+	"\U0001f9da\u200d\u2640":                         {},
+	"\U0001f9da\u200d\u2640\ufe0f":                   {},
+	"\U0001f9da\u200d\u2642":                         {},
+	"\U0001f9da\u200d\u2642\ufe0f":                   {},
+	"\U0001f9da\U0001f3fb":                           {},
+	"\U0001f9da\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9da\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9da\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9da\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9da\U0001f3fc":                           {},
+	"\U0001f9da\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9da\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9da\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9da\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9da\U0001f3fd":                           {},
+	"\U0001f9da\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9da\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9da\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9da\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9da\U0001f3fe":                           {},
+	"\U0001f9da\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9da\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9da\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9da\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9da\U0001f3ff":                           {},
+	"\U0001f9da\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9da\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9da\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9da\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9db":                                     {},
+	"\U0001f9db\u200d\u2640":                         {},
+	"\U0001f9db\u200d\u2640\ufe0f":                   {},
+	"\U0001f9db\u200d\u2642":                         {},
+	"\U0001f9db\u200d\u2642\ufe0f":                   {},
+	"\U0001f9db\U0001f3fb":                           {},
+	"\U0001f9db\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9db\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9db\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9db\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9db\U0001f3fc":                           {},
+	"\U0001f9db\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9db\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9db\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9db\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9db\U0001f3fd":                           {},
+	"\U0001f9db\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9db\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9db\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9db\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9db\U0001f3fe":                           {},
+	"\U0001f9db\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9db\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9db\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9db\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9db\U0001f3ff":                           {},
+	"\U0001f9db\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9db\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9db\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9db\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9dc":                                     {},
+	"\U0001f9dc\u200d\u2640":                         {},
+	"\U0001f9dc\u200d\u2640\ufe0f":                   {},
+	"\U0001f9dc\u200d\u2642":                         {},
+	"\U0001f9dc\u200d\u2642\ufe0f":                   {},
+	"\U0001f9dc\U0001f3fb":                           {},
+	"\U0001f9dc\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9dc\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9dc\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9dc\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9dc\U0001f3fc":                           {},
+	"\U0001f9dc\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9dc\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9dc\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9dc\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9dc\U0001f3fd":                           {},
+	"\U0001f9dc\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9dc\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9dc\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9dc\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9dc\U0001f3fe":                           {},
+	"\U0001f9dc\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9dc\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9dc\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9dc\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9dc\U0001f3ff":                           {},
+	"\U0001f9dc\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9dc\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9dc\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9dc\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9dd":                                     {},
+	"\U0001f9dd\u200d\u2640":                         {},
+	"\U0001f9dd\u200d\u2640\ufe0f":                   {},
+	"\U0001f9dd\u200d\u2642":                         {},
+	"\U0001f9dd\u200d\u2642\ufe0f":                   {},
+	"\U0001f9dd\U0001f3fb":                           {},
+	"\U0001f9dd\U0001f3fb\u200d\u2640":               {},
+	"\U0001f9dd\U0001f3fb\u200d\u2640\ufe0f":         {},
+	"\U0001f9dd\U0001f3fb\u200d\u2642":               {},
+	"\U0001f9dd\U0001f3fb\u200d\u2642\ufe0f":         {},
+	"\U0001f9dd\U0001f3fc":                           {},
+	"\U0001f9dd\U0001f3fc\u200d\u2640":               {},
+	"\U0001f9dd\U0001f3fc\u200d\u2640\ufe0f":         {},
+	"\U0001f9dd\U0001f3fc\u200d\u2642":               {},
+	"\U0001f9dd\U0001f3fc\u200d\u2642\ufe0f":         {},
+	"\U0001f9dd\U0001f3fd":                           {},
+	"\U0001f9dd\U0001f3fd\u200d\u2640":               {},
+	"\U0001f9dd\U0001f3fd\u200d\u2640\ufe0f":         {},
+	"\U0001f9dd\U0001f3fd\u200d\u2642":               {},
+	"\U0001f9dd\U0001f3fd\u200d\u2642\ufe0f":         {},
+	"\U0001f9dd\U0001f3fe":                           {},
+	"\U0001f9dd\U0001f3fe\u200d\u2640":               {},
+	"\U0001f9dd\U0001f3fe\u200d\u2640\ufe0f":         {},
+	"\U0001f9dd\U0001f3fe\u200d\u2642":               {},
+	"\U0001f9dd\U0001f3fe\u200d\u2642\ufe0f":         {},
+	"\U0001f9dd\U0001f3ff":                           {},
+	"\U0001f9dd\U0001f3ff\u200d\u2640":               {},
+	"\U0001f9dd\U0001f3ff\u200d\u2640\ufe0f":         {},
+	"\U0001f9dd\U0001f3ff\u200d\u2642":               {},
+	"\U0001f9dd\U0001f3ff\u200d\u2642\ufe0f":         {},
+	"\U0001f9de":                                     {},
+	"\U0001f9de\u200d\u2640":                         {},
+	"\U0001f9de\u200d\u2640\ufe0f":                   {},
+	"\U0001f9de\u200d\u2642":                         {},
+	"\U0001f9de\u200d\u2642\ufe0f":                   {},
+	"\U0001f9df":                                     {},
+	"\U0001f9df\u200d\u2640":                         {},
+	"\U0001f9df\u200d\u2640\ufe0f":                   {},
+	"\U0001f9df\u200d\u2642":                         {},
+	"\U0001f9df\u200d\u2642\ufe0f":                   {},
+	"\U0001f9e0":                                     {},
+	"\U0001f9e1":                                     {},
+	"\U0001f9e2":                                     {},
+	"\U0001f9e3":                                     {},
+	"\U0001f9e4":                                     {},
+	"\U0001f9e5":                                     {},
+	"\U0001f9e6":                                     {},
+	"\U0001f9e7":                                     {},
+	"\U0001f9e8":                                     {},
+	"\U0001f9e9":                                     {},
+	"\U0001f9ea":                                     {},
+	"\U0001f9eb":                                     {},
+	"\U0001f9ec":                                     {},
+	"\U0001f9ed":                                     {},
+	"\U0001f9ee":                                     {},
+	"\U0001f9ef":                                     {},
+	"\U0001f9f0":                                     {},
+	"\U0001f9f1":                                     {},
+	"\U0001f9f2":                                     {},
+	"\U0001f9f3":                                     {},
+	"\U0001f9f4":                                     {},
+	"\U0001f9f5":                                     {},
+	"\U0001f9f6":                                     {},
+	"\U0001f9f7":                                     {},
+	"\U0001f9f8":                                     {},
+	"\U0001f9f9":                                     {},
+	"\U0001f9fa":                                     {},
+	"\U0001f9fb":                                     {},
+	"\U0001f9fc":                                     {},
+	"\U0001f9fd":                                     {},
+	"\U0001f9fe":                                     {},
+	"\U0001f9ff":                                     {},
+	"\U0001fa70":                                     {},
+	"\U0001fa71":                                     {},
+	"\U0001fa72":                                     {},
+	"\U0001fa73":                                     {},
+	"\U0001fa74":                                     {},
+	"\U0001fa75":                                     {},
+	"\U0001fa76":                                     {},
+	"\U0001fa77":                                     {},
+	"\U0001fa78":                                     {},
+	"\U0001fa79":                                     {},
+	"\U0001fa7a":                                     {},
+	"\U0001fa7b":                                     {},
+	"\U0001fa7c":                                     {},
+	"\U0001fa80":                                     {},
+	"\U0001fa81":                                     {},
+	"\U0001fa82":                                     {},
+	"\U0001fa83":                                     {},
+	"\U0001fa84":                                     {},
+	"\U0001fa85":                                     {},
+	"\U0001fa86":                                     {},
+	"\U0001fa87":                                     {},
+	"\U0001fa88":                                     {},
+	"\U0001fa90":                                     {},
+	"\U0001fa91":                                     {},
+	"\U0001fa92":                                     {},
+	"\U0001fa93":                                     {},
+	"\U0001fa94":                                     {},
+	"\U0001fa95":                                     {},
+	"\U0001fa96":                                     {},
+	"\U0001fa97":                                     {},
+	"\U0001fa98":                                     {},
+	"\U0001fa99":                                     {},
+	"\U0001fa9a":                                     {},
+	"\U0001fa9b":                                     {},
+	"\U0001fa9c":                                     {},
+	"\U0001fa9d":                                     {},
+	"\U0001fa9e":                                     {},
+	"\U0001fa9f":                                     {},
+	"\U0001faa0":                                     {},
+	"\U0001faa1":                                     {},
+	"\U0001faa2":                                     {},
+	"\U0001faa3":                                     {},
+	"\U0001faa4":                                     {},
+	"\U0001faa5":                                     {},
+	"\U0001faa6":                                     {},
+	"\U0001faa7":                                     {},
+	"\U0001faa8":                                     {},
+	"\U0001faa9":                                     {},
+	"\U0001faaa":                                     {},
+	"\U0001faab":                                     {},
+	"\U0001faac":                                     {},
+	"\U0001faad":                                     {},
+	"\U0001faae":                                     {},
+	"\U0001faaf":                                     {},
+	"\U0001fab0":                                     {},
+	"\U0001fab1":                                     {},
+	"\U0001fab2":                                     {},
+	"\U0001fab3":                                     {},
+	"\U0001fab4":                                     {},
+	"\U0001fab5":                                     {},
+	"\U0001fab6":                                     {},
+	"\U0001fab7":                                     {},
+	"\U0001fab8":                                     {},
+	"\U0001fab9":                                     {},
+	"\U0001faba":                                     {},
+	"\U0001fabb":                                     {},
+	"\U0001fabc":                                     {},
+	"\U0001fabd":                                     {},
+	"\U0001fabf":                                     {},
+	"\U0001fac0":                                     {},
+	"\U0001fac1":                                     {},
+	"\U0001fac2":                                     {},
+	"\U0001fac3":                                     {},
+	"\U0001fac3\U0001f3fb":                           {},
+	"\U0001fac3\U0001f3fc":                           {},
+	"\U0001fac3\U0001f3fd":                           {},
+	"\U0001fac3\U0001f3fe":                           {},
+	"\U0001fac3\U0001f3ff":                           {},
+	"\U0001fac4":                                     {},
+	"\U0001fac4\U0001f3fb":                           {},
+	"\U0001fac4\U0001f3fc":                           {},
+	"\U0001fac4\U0001f3fd":                           {},
+	"\U0001fac4\U0001f3fe":                           {},
+	"\U0001fac4\U0001f3ff":                           {},
+	"\U0001fac5":                                     {},
+	"\U0001fac5\U0001f3fb":                           {},
+	"\U0001fac5\U0001f3fc":                           {},
+	"\U0001fac5\U0001f3fd":                           {},
+	"\U0001fac5\U0001f3fe":                           {},
+	"\U0001fac5\U0001f3ff":                           {},
+	"\U0001face":                                     {},
+	"\U0001facf":                                     {},
+	"\U0001fad0":                                     {},
+	"\U0001fad1":                                     {},
+	"\U0001fad2":                                     {},
+	"\U0001fad3":                                     {},
+	"\U0001fad4":                                     {},
+	"\U0001fad5":                                     {},
+	"\U0001fad6":                                     {},
+	"\U0001fad7":                                     {},
+	"\U0001fad8":                                     {},
+	"\U0001fad9":                                     {},
+	"\U0001fada":                                     {},
+	"\U0001fadb":                                     {},
+	"\U0001fae0":                                     {},
+	"\U0001fae1":                                     {},
+	"\U0001fae2":                                     {},
+	"\U0001fae3":                                     {},
+	"\U0001fae4":                                     {},
+	"\U0001fae5":                                     {},
+	"\U0001fae6":                                     {},
+	"\U0001fae7":                                     {},
+	"\U0001fae8":                                     {},
+	"\U0001faf0":                                     {},
+	"\U0001faf0\U0001f3fb":                           {},
+	"\U0001faf0\U0001f3fc":                           {},
+	"\U0001faf0\U0001f3fd":                           {},
+	"\U0001faf0\U0001f3fe":                           {},
+	"\U0001faf0\U0001f3ff":                           {},
+	"\U0001faf1":                                     {},
+	"\U0001faf1\U0001f3fb":                           {},
+	"\U0001faf1\U0001f3fb\u200d\U0001faf2\U0001f3fc": {},
+	"\U0001faf1\U0001f3fb\u200d\U0001faf2\U0001f3fd": {},
+	"\U0001faf1\U0001f3fb\u200d\U0001faf2\U0001f3fe": {},
+	"\U0001faf1\U0001f3fb\u200d\U0001faf2\U0001f3ff": {},
+	"\U0001faf1\U0001f3fc":                           {},
+	"\U0001faf1\U0001f3fc\u200d\U0001faf2\U0001f3fb": {},
+	"\U0001faf1\U0001f3fc\u200d\U0001faf2\U0001f3fd": {},
+	"\U0001faf1\U0001f3fc\u200d\U0001faf2\U0001f3fe": {},
+	"\U0001faf1\U0001f3fc\u200d\U0001faf2\U0001f3ff": {},
+	"\U0001faf1\U0001f3fd":                           {},
+	"\U0001faf1\U0001f3fd\u200d\U0001faf2\U0001f3fb": {},
+	"\U0001faf1\U0001f3fd\u200d\U0001faf2\U0001f3fc": {},
+	"\U0001faf1\U0001f3fd\u200d\U0001faf2\U0001f3fe": {},
+	"\U0001faf1\U0001f3fd\u200d\U0001faf2\U0001f3ff": {},
+	"\U0001faf1\U0001f3fe":                           {},
+	"\U0001faf1\U0001f3fe\u200d\U0001faf2\U0001f3fb": {},
+	"\U0001faf1\U0001f3fe\u200d\U0001faf2\U0001f3fc": {},
+	"\U0001faf1\U0001f3fe\u200d\U0001faf2\U0001f3fd": {},
+	"\U0001faf1\U0001f3fe\u200d\U0001faf2\U0001f3ff": {},
+	"\U0001faf1\U0001f3ff":                           {},
+	"\U0001faf1\U0001f3ff\u200d\U0001faf2\U0001f3fb": {},
+	"\U0001faf1\U0001f3ff\u200d\U0001faf2\U0001f3fc": {},
+	"\U0001faf1\U0001f3ff\u200d\U0001faf2\U0001f3fd": {},
+	"\U0001faf1\U0001f3ff\u200d\U0001faf2\U0001f3fe": {},
+	"\U0001faf2":                                     {},
+	"\U0001faf2\U0001f3fb":                           {},
+	"\U0001faf2\U0001f3fc":                           {},
+	"\U0001faf2\U0001f3fd":                           {},
+	"\U0001faf2\U0001f3fe":                           {},
+	"\U0001faf2\U0001f3ff":                           {},
+	"\U0001faf3":                                     {},
+	"\U0001faf3\U0001f3fb":                           {},
+	"\U0001faf3\U0001f3fc":                           {},
+	"\U0001faf3\U0001f3fd":                           {},
+	"\U0001faf3\U0001f3fe":                           {},
+	"\U0001faf3\U0001f3ff":                           {},
+	"\U0001faf4":                                     {},
+	"\U0001faf4\U0001f3fb":                           {},
+	"\U0001faf4\U0001f3fc":                           {},
+	"\U0001faf4\U0001f3fd":                           {},
+	"\U0001faf4\U0001f3fe":                           {},
+	"\U0001faf4\U0001f3ff":                           {},
+	"\U0001faf5":                                     {},
+	"\U0001faf5\U0001f3fb":                           {},
+	"\U0001faf5\U0001f3fc":                           {},
+	"\U0001faf5\U0001f3fd":                           {},
+	"\U0001faf5\U0001f3fe":                           {},
+	"\U0001faf5\U0001f3ff":                           {},
+	"\U0001faf6":                                     {},
+	"\U0001faf6\U0001f3fb":                           {},
+	"\U0001faf6\U0001f3fc":                           {},
+	"\U0001faf6\U0001f3fd":                           {},
+	"\U0001faf6\U0001f3fe":                           {},
+	"\U0001faf6\U0001f3ff":                           {},
+	"\U0001faf7":                                     {},
+	"\U0001faf7\U0001f3fb":                           {},
+	"\U0001faf7\U0001f3fc":                           {},
+	"\U0001faf7\U0001f3fd":                           {},
+	"\U0001faf7\U0001f3fe":                           {},
+	"\U0001faf7\U0001f3ff":                           {},
+	"\U0001faf8":                                     {},
+	"\U0001faf8\U0001f3fb":                           {},
+	"\U0001faf8\U0001f3fc":                           {},
+	"\U0001faf8\U0001f3fd":                           {},
+	"\U0001faf8\U0001f3fe":                           {},
+	"\U0001faf8\U0001f3ff":                           {},
+}
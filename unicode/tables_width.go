@@ -0,0 +1,452 @@
+// Code generated by gen_width.go from Unicode 15.1.0. DO NOT EDIT.
+
+package unicode
+
+import "unicode"
+
+// UnicodeWidthVersion is the Unicode version from which the width tables are generated.
+const UnicodeWidthVersion = "15.1.0"
+
+// East Asian Wide (W) and Fullwidth (F) code points.
+var rangeTableWide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 0x1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 0x1},
+		{Lo: 0x2329, Hi: 0x232a, Stride: 0x1},
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 0x1},
+		{Lo: 0x23f0, Hi: 0x23f3, Stride: 0x3},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 0x1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 0x1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 0x1},
+		{Lo: 0x267f, Hi: 0x2693, Stride: 0x14},
+		{Lo: 0x26a1, Hi: 0x26aa, Stride: 0x9},
+		{Lo: 0x26ab, Hi: 0x26bd, Stride: 0x12},
+		{Lo: 0x26be, Hi: 0x26c4, Stride: 0x6},
+		{Lo: 0x26c5, Hi: 0x26ce, Stride: 0x9},
+		{Lo: 0x26d4, Hi: 0x26ea, Stride: 0x16},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 0x1},
+		{Lo: 0x26f5, Hi: 0x26fa, Stride: 0x5},
+		{Lo: 0x26fd, Hi: 0x2705, Stride: 0x8},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 0x1},
+		{Lo: 0x2728, Hi: 0x274c, Stride: 0x24},
+		{Lo: 0x274e, Hi: 0x2753, Stride: 0x5},
+		{Lo: 0x2754, Hi: 0x2755, Stride: 0x1},
+		{Lo: 0x2757, Hi: 0x2795, Stride: 0x3e},
+		{Lo: 0x2796, Hi: 0x2797, Stride: 0x1},
+		{Lo: 0x27b0, Hi: 0x27bf, Stride: 0xf},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 0x1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 0x5},
+		{Lo: 0x2e80, Hi: 0x2e99, Stride: 0x1},
+		{Lo: 0x2e9b, Hi: 0x2ef3, Stride: 0x1},
+		{Lo: 0x2f00, Hi: 0x2fd5, Stride: 0x1},
+		{Lo: 0x2ff0, Hi: 0x303e, Stride: 0x1},
+		{Lo: 0x3041, Hi: 0x3096, Stride: 0x1},
+		{Lo: 0x3099, Hi: 0x30ff, Stride: 0x1},
+		{Lo: 0x3105, Hi: 0x312f, Stride: 0x1},
+		{Lo: 0x3131, Hi: 0x318e, Stride: 0x1},
+		{Lo: 0x3190, Hi: 0x31e3, Stride: 0x1},
+		{Lo: 0x31ef, Hi: 0x321e, Stride: 0x1},
+		{Lo: 0x3220, Hi: 0x3247, Stride: 0x1},
+		{Lo: 0x3250, Hi: 0x4dbf, Stride: 0x1},
+		{Lo: 0x4e00, Hi: 0xa48c, Stride: 0x1},
+		{Lo: 0xa490, Hi: 0xa4c6, Stride: 0x1},
+		{Lo: 0xa960, Hi: 0xa97c, Stride: 0x1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 0x1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 0x1},
+		{Lo: 0xfe10, Hi: 0xfe19, Stride: 0x1},
+		{Lo: 0xfe30, Hi: 0xfe52, Stride: 0x1},
+		{Lo: 0xfe54, Hi: 0xfe66, Stride: 0x1},
+		{Lo: 0xfe68, Hi: 0xfe6b, Stride: 0x1},
+		{Lo: 0xff01, Hi: 0xff60, Stride: 0x1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 0x1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x16fe0, Hi: 0x16fe4, Stride: 0x1},
+		{Lo: 0x16ff0, Hi: 0x16ff1, Stride: 0x1},
+		{Lo: 0x17000, Hi: 0x187f7, Stride: 0x1},
+		{Lo: 0x18800, Hi: 0x18cd5, Stride: 0x1},
+		{Lo: 0x18d00, Hi: 0x18d08, Stride: 0x1},
+		{Lo: 0x1aff0, Hi: 0x1aff3, Stride: 0x1},
+		{Lo: 0x1aff5, Hi: 0x1affb, Stride: 0x1},
+		{Lo: 0x1affd, Hi: 0x1affe, Stride: 0x1},
+		{Lo: 0x1b000, Hi: 0x1b122, Stride: 0x1},
+		{Lo: 0x1b132, Hi: 0x1b150, Stride: 0x1e},
+		{Lo: 0x1b151, Hi: 0x1b152, Stride: 0x1},
+		{Lo: 0x1b155, Hi: 0x1b164, Stride: 0xf},
+		{Lo: 0x1b165, Hi: 0x1b167, Stride: 0x1},
+		{Lo: 0x1b170, Hi: 0x1b2fb, Stride: 0x1},
+		{Lo: 0x1f004, Hi: 0x1f0cf, Stride: 0xcb},
+		{Lo: 0x1f18e, Hi: 0x1f191, Stride: 0x3},
+		{Lo: 0x1f192, Hi: 0x1f19a, Stride: 0x1},
+		{Lo: 0x1f200, Hi: 0x1f202, Stride: 0x1},
+		{Lo: 0x1f210, Hi: 0x1f23b, Stride: 0x1},
+		{Lo: 0x1f240, Hi: 0x1f248, Stride: 0x1},
+		{Lo: 0x1f250, Hi: 0x1f251, Stride: 0x1},
+		{Lo: 0x1f260, Hi: 0x1f265, Stride: 0x1},
+		{Lo: 0x1f300, Hi: 0x1f320, Stride: 0x1},
+		{Lo: 0x1f32d, Hi: 0x1f335, Stride: 0x1},
+		{Lo: 0x1f337, Hi: 0x1f37c, Stride: 0x1},
+		{Lo: 0x1f37e, Hi: 0x1f393, Stride: 0x1},
+		{Lo: 0x1f3a0, Hi: 0x1f3ca, Stride: 0x1},
+		{Lo: 0x1f3cf, Hi: 0x1f3d3, Stride: 0x1},
+		{Lo: 0x1f3e0, Hi: 0x1f3f0, Stride: 0x1},
+		{Lo: 0x1f3f4, Hi: 0x1f3f8, Stride: 0x4},
+		{Lo: 0x1f3f9, Hi: 0x1f43e, Stride: 0x1},
+		{Lo: 0x1f440, Hi: 0x1f442, Stride: 0x2},
+		{Lo: 0x1f443, Hi: 0x1f4fc, Stride: 0x1},
+		{Lo: 0x1f4ff, Hi: 0x1f53d, Stride: 0x1},
+		{Lo: 0x1f54b, Hi: 0x1f54e, Stride: 0x1},
+		{Lo: 0x1f550, Hi: 0x1f567, Stride: 0x1},
+		{Lo: 0x1f57a, Hi: 0x1f595, Stride: 0x1b},
+		{Lo: 0x1f596, Hi: 0x1f5a4, Stride: 0xe},
+		{Lo: 0x1f5fb, Hi: 0x1f64f, Stride: 0x1},
+		{Lo: 0x1f680, Hi: 0x1f6c5, Stride: 0x1},
+		{Lo: 0x1f6cc, Hi: 0x1f6d0, Stride: 0x4},
+		{Lo: 0x1f6d1, Hi: 0x1f6d2, Stride: 0x1},
+		{Lo: 0x1f6d5, Hi: 0x1f6d7, Stride: 0x1},
+		{Lo: 0x1f6dc, Hi: 0x1f6df, Stride: 0x1},
+		{Lo: 0x1f6eb, Hi: 0x1f6ec, Stride: 0x1},
+		{Lo: 0x1f6f4, Hi: 0x1f6fc, Stride: 0x1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 0x1},
+		{Lo: 0x1f7f0, Hi: 0x1f90c, Stride: 0x11c},
+		{Lo: 0x1f90d, Hi: 0x1f93a, Stride: 0x1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 0x1},
+		{Lo: 0x1f947, Hi: 0x1f9ff, Stride: 0x1},
+		{Lo: 0x1fa70, Hi: 0x1fa7c, Stride: 0x1},
+		{Lo: 0x1fa80, Hi: 0x1fa88, Stride: 0x1},
+		{Lo: 0x1fa90, Hi: 0x1fabd, Stride: 0x1},
+		{Lo: 0x1fabf, Hi: 0x1fac5, Stride: 0x1},
+		{Lo: 0x1face, Hi: 0x1fadb, Stride: 0x1},
+		{Lo: 0x1fae0, Hi: 0x1fae8, Stride: 0x1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 0x1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 0x1},
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 0x1},
+	},
+}
+
+// Code points that take no columns: nonspacing and enclosing marks, format characters
+// other than the soft hyphen, and Hangul Jungseong and Jongseong.
+var rangeTableZeroWidth = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x300, Hi: 0x36f, Stride: 0x1},
+		{Lo: 0x483, Hi: 0x489, Stride: 0x1},
+		{Lo: 0x591, Hi: 0x5bd, Stride: 0x1},
+		{Lo: 0x5bf, Hi: 0x5c1, Stride: 0x2},
+		{Lo: 0x5c2, Hi: 0x5c4, Stride: 0x2},
+		{Lo: 0x5c5, Hi: 0x5c7, Stride: 0x2},
+		{Lo: 0x600, Hi: 0x605, Stride: 0x1},
+		{Lo: 0x610, Hi: 0x61a, Stride: 0x1},
+		{Lo: 0x61c, Hi: 0x64b, Stride: 0x2f},
+		{Lo: 0x64c, Hi: 0x65f, Stride: 0x1},
+		{Lo: 0x670, Hi: 0x6d6, Stride: 0x66},
+		{Lo: 0x6d7, Hi: 0x6dd, Stride: 0x1},
+		{Lo: 0x6df, Hi: 0x6e4, Stride: 0x1},
+		{Lo: 0x6e7, Hi: 0x6e8, Stride: 0x1},
+		{Lo: 0x6ea, Hi: 0x6ed, Stride: 0x1},
+		{Lo: 0x70f, Hi: 0x711, Stride: 0x2},
+		{Lo: 0x730, Hi: 0x74a, Stride: 0x1},
+		{Lo: 0x7a6, Hi: 0x7b0, Stride: 0x1},
+		{Lo: 0x7eb, Hi: 0x7f3, Stride: 0x1},
+		{Lo: 0x7fd, Hi: 0x816, Stride: 0x19},
+		{Lo: 0x817, Hi: 0x819, Stride: 0x1},
+		{Lo: 0x81b, Hi: 0x823, Stride: 0x1},
+		{Lo: 0x825, Hi: 0x827, Stride: 0x1},
+		{Lo: 0x829, Hi: 0x82d, Stride: 0x1},
+		{Lo: 0x859, Hi: 0x85b, Stride: 0x1},
+		{Lo: 0x890, Hi: 0x891, Stride: 0x1},
+		{Lo: 0x898, Hi: 0x89f, Stride: 0x1},
+		{Lo: 0x8ca, Hi: 0x902, Stride: 0x1},
+		{Lo: 0x93a, Hi: 0x93c, Stride: 0x2},
+		{Lo: 0x941, Hi: 0x948, Stride: 0x1},
+		{Lo: 0x94d, Hi: 0x951, Stride: 0x4},
+		{Lo: 0x952, Hi: 0x957, Stride: 0x1},
+		{Lo: 0x962, Hi: 0x963, Stride: 0x1},
+		{Lo: 0x981, Hi: 0x9bc, Stride: 0x3b},
+		{Lo: 0x9c1, Hi: 0x9c4, Stride: 0x1},
+		{Lo: 0x9cd, Hi: 0x9e2, Stride: 0x15},
+		{Lo: 0x9e3, Hi: 0x9fe, Stride: 0x1b},
+		{Lo: 0xa01, Hi: 0xa02, Stride: 0x1},
+		{Lo: 0xa3c, Hi: 0xa41, Stride: 0x5},
+		{Lo: 0xa42, Hi: 0xa47, Stride: 0x5},
+		{Lo: 0xa48, Hi: 0xa4b, Stride: 0x3},
+		{Lo: 0xa4c, Hi: 0xa4d, Stride: 0x1},
+		{Lo: 0xa51, Hi: 0xa70, Stride: 0x1f},
+		{Lo: 0xa71, Hi: 0xa75, Stride: 0x4},
+		{Lo: 0xa81, Hi: 0xa82, Stride: 0x1},
+		{Lo: 0xabc, Hi: 0xac1, Stride: 0x5},
+		{Lo: 0xac2, Hi: 0xac5, Stride: 0x1},
+		{Lo: 0xac7, Hi: 0xac8, Stride: 0x1},
+		{Lo: 0xacd, Hi: 0xae2, Stride: 0x15},
+		{Lo: 0xae3, Hi: 0xafa, Stride: 0x17},
+		{Lo: 0xafb, Hi: 0xaff, Stride: 0x1},
+		{Lo: 0xb01, Hi: 0xb3c, Stride: 0x3b},
+		{Lo: 0xb3f, Hi: 0xb41, Stride: 0x2},
+		{Lo: 0xb42, Hi: 0xb44, Stride: 0x1},
+		{Lo: 0xb4d, Hi: 0xb55, Stride: 0x8},
+		{Lo: 0xb56, Hi: 0xb62, Stride: 0xc},
+		{Lo: 0xb63, Hi: 0xb82, Stride: 0x1f},
+		{Lo: 0xbc0, Hi: 0xbcd, Stride: 0xd},
+		{Lo: 0xc00, Hi: 0xc04, Stride: 0x4},
+		{Lo: 0xc3c, Hi: 0xc3e, Stride: 0x2},
+		{Lo: 0xc3f, Hi: 0xc40, Stride: 0x1},
+		{Lo: 0xc46, Hi: 0xc48, Stride: 0x1},
+		{Lo: 0xc4a, Hi: 0xc4d, Stride: 0x1},
+		{Lo: 0xc55, Hi: 0xc56, Stride: 0x1},
+		{Lo: 0xc62, Hi: 0xc63, Stride: 0x1},
+		{Lo: 0xc81, Hi: 0xcbc, Stride: 0x3b},
+		{Lo: 0xcbf, Hi: 0xcc6, Stride: 0x7},
+		{Lo: 0xccc, Hi: 0xccd, Stride: 0x1},
+		{Lo: 0xce2, Hi: 0xce3, Stride: 0x1},
+		{Lo: 0xd00, Hi: 0xd01, Stride: 0x1},
+		{Lo: 0xd3b, Hi: 0xd3c, Stride: 0x1},
+		{Lo: 0xd41, Hi: 0xd44, Stride: 0x1},
+		{Lo: 0xd4d, Hi: 0xd62, Stride: 0x15},
+		{Lo: 0xd63, Hi: 0xd81, Stride: 0x1e},
+		{Lo: 0xdca, Hi: 0xdd2, Stride: 0x8},
+		{Lo: 0xdd3, Hi: 0xdd4, Stride: 0x1},
+		{Lo: 0xdd6, Hi: 0xe31, Stride: 0x5b},
+		{Lo: 0xe34, Hi: 0xe3a, Stride: 0x1},
+		{Lo: 0xe47, Hi: 0xe4e, Stride: 0x1},
+		{Lo: 0xeb1, Hi: 0xeb4, Stride: 0x3},
+		{Lo: 0xeb5, Hi: 0xebc, Stride: 0x1},
+		{Lo: 0xec8, Hi: 0xece, Stride: 0x1},
+		{Lo: 0xf18, Hi: 0xf19, Stride: 0x1},
+		{Lo: 0xf35, Hi: 0xf39, Stride: 0x2},
+		{Lo: 0xf71, Hi: 0xf7e, Stride: 0x1},
+		{Lo: 0xf80, Hi: 0xf84, Stride: 0x1},
+		{Lo: 0xf86, Hi: 0xf87, Stride: 0x1},
+		{Lo: 0xf8d, Hi: 0xf97, Stride: 0x1},
+		{Lo: 0xf99, Hi: 0xfbc, Stride: 0x1},
+		{Lo: 0xfc6, Hi: 0x102d, Stride: 0x67},
+		{Lo: 0x102e, Hi: 0x1030, Stride: 0x1},
+		{Lo: 0x1032, Hi: 0x1037, Stride: 0x1},
+		{Lo: 0x1039, Hi: 0x103a, Stride: 0x1},
+		{Lo: 0x103d, Hi: 0x103e, Stride: 0x1},
+		{Lo: 0x1058, Hi: 0x1059, Stride: 0x1},
+		{Lo: 0x105e, Hi: 0x1060, Stride: 0x1},
+		{Lo: 0x1071, Hi: 0x1074, Stride: 0x1},
+		{Lo: 0x1082, Hi: 0x1085, Stride: 0x3},
+		{Lo: 0x1086, Hi: 0x108d, Stride: 0x7},
+		{Lo: 0x109d, Hi: 0x1160, Stride: 0xc3},
+		{Lo: 0x1161, Hi: 0x11ff, Stride: 0x1},
+		{Lo: 0x135d, Hi: 0x135f, Stride: 0x1},
+		{Lo: 0x1712, Hi: 0x1714, Stride: 0x1},
+		{Lo: 0x1732, Hi: 0x1733, Stride: 0x1},
+		{Lo: 0x1752, Hi: 0x1753, Stride: 0x1},
+		{Lo: 0x1772, Hi: 0x1773, Stride: 0x1},
+		{Lo: 0x17b4, Hi: 0x17b5, Stride: 0x1},
+		{Lo: 0x17b7, Hi: 0x17bd, Stride: 0x1},
+		{Lo: 0x17c6, Hi: 0x17c9, Stride: 0x3},
+		{Lo: 0x17ca, Hi: 0x17d3, Stride: 0x1},
+		{Lo: 0x17dd, Hi: 0x180b, Stride: 0x2e},
+		{Lo: 0x180c, Hi: 0x180f, Stride: 0x1},
+		{Lo: 0x1885, Hi: 0x1886, Stride: 0x1},
+		{Lo: 0x18a9, Hi: 0x1920, Stride: 0x77},
+		{Lo: 0x1921, Hi: 0x1922, Stride: 0x1},
+		{Lo: 0x1927, Hi: 0x1928, Stride: 0x1},
+		{Lo: 0x1932, Hi: 0x1939, Stride: 0x7},
+		{Lo: 0x193a, Hi: 0x193b, Stride: 0x1},
+		{Lo: 0x1a17, Hi: 0x1a18, Stride: 0x1},
+		{Lo: 0x1a1b, Hi: 0x1a56, Stride: 0x3b},
+		{Lo: 0x1a58, Hi: 0x1a5e, Stride: 0x1},
+		{Lo: 0x1a60, Hi: 0x1a62, Stride: 0x2},
+		{Lo: 0x1a65, Hi: 0x1a6c, Stride: 0x1},
+		{Lo: 0x1a73, Hi: 0x1a7c, Stride: 0x1},
+		{Lo: 0x1a7f, Hi: 0x1ab0, Stride: 0x31},
+		{Lo: 0x1ab1, Hi: 0x1ace, Stride: 0x1},
+		{Lo: 0x1b00, Hi: 0x1b03, Stride: 0x1},
+		{Lo: 0x1b34, Hi: 0x1b36, Stride: 0x2},
+		{Lo: 0x1b37, Hi: 0x1b3a, Stride: 0x1},
+		{Lo: 0x1b3c, Hi: 0x1b42, Stride: 0x6},
+		{Lo: 0x1b6b, Hi: 0x1b73, Stride: 0x1},
+		{Lo: 0x1b80, Hi: 0x1b81, Stride: 0x1},
+		{Lo: 0x1ba2, Hi: 0x1ba5, Stride: 0x1},
+		{Lo: 0x1ba8, Hi: 0x1ba9, Stride: 0x1},
+		{Lo: 0x1bab, Hi: 0x1bad, Stride: 0x1},
+		{Lo: 0x1be6, Hi: 0x1be8, Stride: 0x2},
+		{Lo: 0x1be9, Hi: 0x1bed, Stride: 0x4},
+		{Lo: 0x1bef, Hi: 0x1bf1, Stride: 0x1},
+		{Lo: 0x1c2c, Hi: 0x1c33, Stride: 0x1},
+		{Lo: 0x1c36, Hi: 0x1c37, Stride: 0x1},
+		{Lo: 0x1cd0, Hi: 0x1cd2, Stride: 0x1},
+		{Lo: 0x1cd4, Hi: 0x1ce0, Stride: 0x1},
+		{Lo: 0x1ce2, Hi: 0x1ce8, Stride: 0x1},
+		{Lo: 0x1ced, Hi: 0x1cf4, Stride: 0x7},
+		{Lo: 0x1cf8, Hi: 0x1cf9, Stride: 0x1},
+		{Lo: 0x1dc0, Hi: 0x1dff, Stride: 0x1},
+		{Lo: 0x200b, Hi: 0x200f, Stride: 0x1},
+		{Lo: 0x202a, Hi: 0x202e, Stride: 0x1},
+		{Lo: 0x2060, Hi: 0x2064, Stride: 0x1},
+		{Lo: 0x2066, Hi: 0x206f, Stride: 0x1},
+		{Lo: 0x20d0, Hi: 0x20f0, Stride: 0x1},
+		{Lo: 0x2cef, Hi: 0x2cf1, Stride: 0x1},
+		{Lo: 0x2d7f, Hi: 0x2de0, Stride: 0x61},
+		{Lo: 0x2de1, Hi: 0x2dff, Stride: 0x1},
+		{Lo: 0x302a, Hi: 0x302d, Stride: 0x1},
+		{Lo: 0x3099, Hi: 0x309a, Stride: 0x1},
+		{Lo: 0xa66f, Hi: 0xa672, Stride: 0x1},
+		{Lo: 0xa674, Hi: 0xa67d, Stride: 0x1},
+		{Lo: 0xa69e, Hi: 0xa69f, Stride: 0x1},
+		{Lo: 0xa6f0, Hi: 0xa6f1, Stride: 0x1},
+		{Lo: 0xa802, Hi: 0xa806, Stride: 0x4},
+		{Lo: 0xa80b, Hi: 0xa825, Stride: 0x1a},
+		{Lo: 0xa826, Hi: 0xa82c, Stride: 0x6},
+		{Lo: 0xa8c4, Hi: 0xa8c5, Stride: 0x1},
+		{Lo: 0xa8e0, Hi: 0xa8f1, Stride: 0x1},
+		{Lo: 0xa8ff, Hi: 0xa926, Stride: 0x27},
+		{Lo: 0xa927, Hi: 0xa92d, Stride: 0x1},
+		{Lo: 0xa947, Hi: 0xa951, Stride: 0x1},
+		{Lo: 0xa980, Hi: 0xa982, Stride: 0x1},
+		{Lo: 0xa9b3, Hi: 0xa9b6, Stride: 0x3},
+		{Lo: 0xa9b7, Hi: 0xa9b9, Stride: 0x1},
+		{Lo: 0xa9bc, Hi: 0xa9bd, Stride: 0x1},
+		{Lo: 0xa9e5, Hi: 0xaa29, Stride: 0x44},
+		{Lo: 0xaa2a, Hi: 0xaa2e, Stride: 0x1},
+		{Lo: 0xaa31, Hi: 0xaa32, Stride: 0x1},
+		{Lo: 0xaa35, Hi: 0xaa36, Stride: 0x1},
+		{Lo: 0xaa43, Hi: 0xaa4c, Stride: 0x9},
+		{Lo: 0xaa7c, Hi: 0xaab0, Stride: 0x34},
+		{Lo: 0xaab2, Hi: 0xaab4, Stride: 0x1},
+		{Lo: 0xaab7, Hi: 0xaab8, Stride: 0x1},
+		{Lo: 0xaabe, Hi: 0xaabf, Stride: 0x1},
+		{Lo: 0xaac1, Hi: 0xaaec, Stride: 0x2b},
+		{Lo: 0xaaed, Hi: 0xaaf6, Stride: 0x9},
+		{Lo: 0xabe5, Hi: 0xabe8, Stride: 0x3},
+		{Lo: 0xabed, Hi: 0xfb1e, Stride: 0x4f31},
+		{Lo: 0xfe00, Hi: 0xfe0f, Stride: 0x1},
+		{Lo: 0xfe20, Hi: 0xfe2f, Stride: 0x1},
+		{Lo: 0xfeff, Hi: 0xfff9, Stride: 0xfa},
+		{Lo: 0xfffa, Hi: 0xfffb, Stride: 0x1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x101fd, Hi: 0x102e0, Stride: 0xe3},
+		{Lo: 0x10376, Hi: 0x1037a, Stride: 0x1},
+		{Lo: 0x10a01, Hi: 0x10a03, Stride: 0x1},
+		{Lo: 0x10a05, Hi: 0x10a06, Stride: 0x1},
+		{Lo: 0x10a0c, Hi: 0x10a0f, Stride: 0x1},
+		{Lo: 0x10a38, Hi: 0x10a3a, Stride: 0x1},
+		{Lo: 0x10a3f, Hi: 0x10ae5, Stride: 0xa6},
+		{Lo: 0x10ae6, Hi: 0x10d24, Stride: 0x23e},
+		{Lo: 0x10d25, Hi: 0x10d27, Stride: 0x1},
+		{Lo: 0x10eab, Hi: 0x10eac, Stride: 0x1},
+		{Lo: 0x10efd, Hi: 0x10eff, Stride: 0x1},
+		{Lo: 0x10f46, Hi: 0x10f50, Stride: 0x1},
+		{Lo: 0x10f82, Hi: 0x10f85, Stride: 0x1},
+		{Lo: 0x11001, Hi: 0x11038, Stride: 0x37},
+		{Lo: 0x11039, Hi: 0x11046, Stride: 0x1},
+		{Lo: 0x11070, Hi: 0x11073, Stride: 0x3},
+		{Lo: 0x11074, Hi: 0x1107f, Stride: 0xb},
+		{Lo: 0x11080, Hi: 0x11081, Stride: 0x1},
+		{Lo: 0x110b3, Hi: 0x110b6, Stride: 0x1},
+		{Lo: 0x110b9, Hi: 0x110ba, Stride: 0x1},
+		{Lo: 0x110bd, Hi: 0x110c2, Stride: 0x5},
+		{Lo: 0x110cd, Hi: 0x11100, Stride: 0x33},
+		{Lo: 0x11101, Hi: 0x11102, Stride: 0x1},
+		{Lo: 0x11127, Hi: 0x1112b, Stride: 0x1},
+		{Lo: 0x1112d, Hi: 0x11134, Stride: 0x1},
+		{Lo: 0x11173, Hi: 0x11180, Stride: 0xd},
+		{Lo: 0x11181, Hi: 0x111b6, Stride: 0x35},
+		{Lo: 0x111b7, Hi: 0x111be, Stride: 0x1},
+		{Lo: 0x111c9, Hi: 0x111cc, Stride: 0x1},
+		{Lo: 0x111cf, Hi: 0x1122f, Stride: 0x60},
+		{Lo: 0x11230, Hi: 0x11231, Stride: 0x1},
+		{Lo: 0x11234, Hi: 0x11236, Stride: 0x2},
+		{Lo: 0x11237, Hi: 0x1123e, Stride: 0x7},
+		{Lo: 0x11241, Hi: 0x112df, Stride: 0x9e},
+		{Lo: 0x112e3, Hi: 0x112ea, Stride: 0x1},
+		{Lo: 0x11300, Hi: 0x11301, Stride: 0x1},
+		{Lo: 0x1133b, Hi: 0x1133c, Stride: 0x1},
+		{Lo: 0x11340, Hi: 0x11366, Stride: 0x26},
+		{Lo: 0x11367, Hi: 0x1136c, Stride: 0x1},
+		{Lo: 0x11370, Hi: 0x11374, Stride: 0x1},
+		{Lo: 0x11438, Hi: 0x1143f, Stride: 0x1},
+		{Lo: 0x11442, Hi: 0x11444, Stride: 0x1},
+		{Lo: 0x11446, Hi: 0x1145e, Stride: 0x18},
+		{Lo: 0x114b3, Hi: 0x114b8, Stride: 0x1},
+		{Lo: 0x114ba, Hi: 0x114bf, Stride: 0x5},
+		{Lo: 0x114c0, Hi: 0x114c2, Stride: 0x2},
+		{Lo: 0x114c3, Hi: 0x115b2, Stride: 0xef},
+		{Lo: 0x115b3, Hi: 0x115b5, Stride: 0x1},
+		{Lo: 0x115bc, Hi: 0x115bd, Stride: 0x1},
+		{Lo: 0x115bf, Hi: 0x115c0, Stride: 0x1},
+		{Lo: 0x115dc, Hi: 0x115dd, Stride: 0x1},
+		{Lo: 0x11633, Hi: 0x1163a, Stride: 0x1},
+		{Lo: 0x1163d, Hi: 0x1163f, Stride: 0x2},
+		{Lo: 0x11640, Hi: 0x116ab, Stride: 0x6b},
+		{Lo: 0x116ad, Hi: 0x116b0, Stride: 0x3},
+		{Lo: 0x116b1, Hi: 0x116b5, Stride: 0x1},
+		{Lo: 0x116b7, Hi: 0x1171d, Stride: 0x66},
+		{Lo: 0x1171f, Hi: 0x11722, Stride: 0x3},
+		{Lo: 0x11723, Hi: 0x11725, Stride: 0x1},
+		{Lo: 0x11727, Hi: 0x1172b, Stride: 0x1},
+		{Lo: 0x1182f, Hi: 0x11837, Stride: 0x1},
+		{Lo: 0x11839, Hi: 0x1183a, Stride: 0x1},
+		{Lo: 0x1193b, Hi: 0x1193c, Stride: 0x1},
+		{Lo: 0x1193e, Hi: 0x11943, Stride: 0x5},
+		{Lo: 0x119d4, Hi: 0x119d7, Stride: 0x1},
+		{Lo: 0x119da, Hi: 0x119db, Stride: 0x1},
+		{Lo: 0x119e0, Hi: 0x11a01, Stride: 0x21},
+		{Lo: 0x11a02, Hi: 0x11a0a, Stride: 0x1},
+		{Lo: 0x11a33, Hi: 0x11a38, Stride: 0x1},
+		{Lo: 0x11a3b, Hi: 0x11a3e, Stride: 0x1},
+		{Lo: 0x11a47, Hi: 0x11a51, Stride: 0xa},
+		{Lo: 0x11a52, Hi: 0x11a56, Stride: 0x1},
+		{Lo: 0x11a59, Hi: 0x11a5b, Stride: 0x1},
+		{Lo: 0x11a8a, Hi: 0x11a96, Stride: 0x1},
+		{Lo: 0x11a98, Hi: 0x11a99, Stride: 0x1},
+		{Lo: 0x11c30, Hi: 0x11c36, Stride: 0x1},
+		{Lo: 0x11c38, Hi: 0x11c3d, Stride: 0x1},
+		{Lo: 0x11c3f, Hi: 0x11c92, Stride: 0x53},
+		{Lo: 0x11c93, Hi: 0x11ca7, Stride: 0x1},
+		{Lo: 0x11caa, Hi: 0x11cb0, Stride: 0x1},
+		{Lo: 0x11cb2, Hi: 0x11cb3, Stride: 0x1},
+		{Lo: 0x11cb5, Hi: 0x11cb6, Stride: 0x1},
+		{Lo: 0x11d31, Hi: 0x11d36, Stride: 0x1},
+		{Lo: 0x11d3a, Hi: 0x11d3c, Stride: 0x2},
+		{Lo: 0x11d3d, Hi: 0x11d3f, Stride: 0x2},
+		{Lo: 0x11d40, Hi: 0x11d45, Stride: 0x1},
+		{Lo: 0x11d47, Hi: 0x11d90, Stride: 0x49},
+		{Lo: 0x11d91, Hi: 0x11d95, Stride: 0x4},
+		{Lo: 0x11d97, Hi: 0x11ef3, Stride: 0x15c},
+		{Lo: 0x11ef4, Hi: 0x11f00, Stride: 0xc},
+		{Lo: 0x11f01, Hi: 0x11f36, Stride: 0x35},
+		{Lo: 0x11f37, Hi: 0x11f3a, Stride: 0x1},
+		{Lo: 0x11f40, Hi: 0x11f42, Stride: 0x2},
+		{Lo: 0x13430, Hi: 0x13440, Stride: 0x1},
+		{Lo: 0x13447, Hi: 0x13455, Stride: 0x1},
+		{Lo: 0x16af0, Hi: 0x16af4, Stride: 0x1},
+		{Lo: 0x16b30, Hi: 0x16b36, Stride: 0x1},
+		{Lo: 0x16f4f, Hi: 0x16f8f, Stride: 0x40},
+		{Lo: 0x16f90, Hi: 0x16f92, Stride: 0x1},
+		{Lo: 0x16fe4, Hi: 0x1bc9d, Stride: 0x4cb9},
+		{Lo: 0x1bc9e, Hi: 0x1bca0, Stride: 0x2},
+		{Lo: 0x1bca1, Hi: 0x1bca3, Stride: 0x1},
+		{Lo: 0x1cf00, Hi: 0x1cf2d, Stride: 0x1},
+		{Lo: 0x1cf30, Hi: 0x1cf46, Stride: 0x1},
+		{Lo: 0x1d167, Hi: 0x1d169, Stride: 0x1},
+		{Lo: 0x1d173, Hi: 0x1d182, Stride: 0x1},
+		{Lo: 0x1d185, Hi: 0x1d18b, Stride: 0x1},
+		{Lo: 0x1d1aa, Hi: 0x1d1ad, Stride: 0x1},
+		{Lo: 0x1d242, Hi: 0x1d244, Stride: 0x1},
+		{Lo: 0x1da00, Hi: 0x1da36, Stride: 0x1},
+		{Lo: 0x1da3b, Hi: 0x1da6c, Stride: 0x1},
+		{Lo: 0x1da75, Hi: 0x1da84, Stride: 0xf},
+		{Lo: 0x1da9b, Hi: 0x1da9f, Stride: 0x1},
+		{Lo: 0x1daa1, Hi: 0x1daaf, Stride: 0x1},
+		{Lo: 0x1e000, Hi: 0x1e006, Stride: 0x1},
+		{Lo: 0x1e008, Hi: 0x1e018, Stride: 0x1},
+		{Lo: 0x1e01b, Hi: 0x1e021, Stride: 0x1},
+		{Lo: 0x1e023, Hi: 0x1e024, Stride: 0x1},
+		{Lo: 0x1e026, Hi: 0x1e02a, Stride: 0x1},
+		{Lo: 0x1e08f, Hi: 0x1e130, Stride: 0xa1},
+		{Lo: 0x1e131, Hi: 0x1e136, Stride: 0x1},
+		{Lo: 0x1e2ae, Hi: 0x1e2ec, Stride: 0x3e},
+		{Lo: 0x1e2ed, Hi: 0x1e2ef, Stride: 0x1},
+		{Lo: 0x1e4ec, Hi: 0x1e4ef, Stride: 0x1},
+		{Lo: 0x1e8d0, Hi: 0x1e8d6, Stride: 0x1},
+		{Lo: 0x1e944, Hi: 0x1e94a, Stride: 0x1},
+		{Lo: 0xe0001, Hi: 0xe0020, Stride: 0x1f},
+		{Lo: 0xe0021, Hi: 0xe007f, Stride: 0x1},
+		{Lo: 0xe0100, Hi: 0xe01ef, Stride: 0x1},
+	},
+}
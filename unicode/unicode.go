@@ -1,2 +1,8 @@
 // Unicode related utilities.
 package unicode
+
+// The width and emoji tables are generated from the same Unicode version, from the official data
+// files vendored at ucd/ and emoji-test.txt.
+//
+//go:generate go run gen_width.go -version 15.1.0 -ucd ucd
+//go:generate go run gen_emoji.go -version 15.1 -input emoji-test.txt
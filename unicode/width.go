@@ -0,0 +1,160 @@
+package unicode
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner     = '\u200d'
+	variationSelector15 = '\ufe0e'
+	variationSelector16 = '\ufe0f'
+)
+
+// RuneWidth returns the number of columns r takes when displayed by a terminal: 0 for control
+// characters, combining marks and other zero width code points, 2 for East Asian wide and
+// fullwidth code points, and 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case unicode.IsControl(r):
+		return 0
+	case unicode.Is(rangeTableZeroWidth, r):
+		return 0
+	case unicode.Is(rangeTableWide, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1f1e6 <= r && r <= 0x1f1ff
+}
+
+func isEmojiModifier(r rune) bool {
+	return 0x1f3fb <= r && r <= 0x1f3ff
+}
+
+// FirstGrapheme returns the first grapheme cluster of s and the number of columns it takes when
+// displayed by a terminal. Clusters are a base code point followed by combining marks and other
+// zero width code points, variation selectors, emoji modifiers, zero width joiner sequences or a
+// second regional indicator, and CR LF. A variation selector 16 makes the cluster take 2 columns,
+// as terminals display it with emoji presentation.
+func FirstGrapheme(s string) (string, int) {
+	if len(s) == 0 {
+		return "", 0
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == '\r' && len(s) > size && s[size] == '\n' {
+		return s[:size+1], 0
+	}
+	width := RuneWidth(r)
+	if unicode.IsControl(r) {
+		return s[:size], width
+	}
+	regionalIndicators := 0
+	if isRegionalIndicator(r) {
+		regionalIndicators = 1
+	}
+	i := size
+	prev := r
+	for i < len(s) {
+		next, nextSize := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsControl(next):
+			return s[:i], width
+		case prev == zeroWidthJoiner:
+		case next == variationSelector16:
+			width = max(width, 2)
+		case next == variationSelector15:
+		case isEmojiModifier(next):
+		case regionalIndicators == 1 && isRegionalIndicator(next):
+			regionalIndicators++
+			width = 2
+		case RuneWidth(next) == 0:
+		default:
+			return s[:i], width
+		}
+		prev = next
+		i += nextSize
+	}
+	return s, width
+}
+
+// ansiSequenceLength returns the length of the ANSI escape sequence at the start of s: a Control
+// Sequence Introducer (CSI) sequence, or an Operating System Command (OSC) sequence terminated by
+// BEL or ST. It returns 0 if s does not start with one of them.
+func ansiSequenceLength(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if 0x40 <= s[i] && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x3f {
+				return 0
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	}
+	return 0
+}
+
+// StringWidth returns the number of columns s takes when displayed by a terminal, measured by
+// grapheme cluster, not counting ANSI escape sequences.
+func StringWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		grapheme, graphemeWidth := FirstGrapheme(s[i:])
+		width += graphemeWidth
+		i += len(grapheme)
+	}
+	return width
+}
+
+// TruncateToWidth returns the longest prefix of s, made of whole grapheme clusters, that takes at
+// most width columns when displayed by a terminal. ANSI escape sequences take no columns, and the
+// ones after the truncation point are kept, so that styles and hyperlinks are still terminated.
+func TruncateToWidth(s string, width int) string {
+	columns := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		grapheme, graphemeWidth := FirstGrapheme(s[i:])
+		if columns+graphemeWidth > width {
+			return s[:i] + ansiSequences(s[i:])
+		}
+		columns += graphemeWidth
+		i += len(grapheme)
+	}
+	return s
+}
+
+// ansiSequences returns all ANSI escape sequences in s.
+func ansiSequences(s string) string {
+	var sequences []byte
+	for i := 0; i < len(s); i++ {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			sequences = append(sequences, s[i:i+n]...)
+			i += n - 1
+		}
+	}
+	return string(sequences)
+}
@@ -0,0 +1,118 @@
+package unicode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        rune
+		expected int
+	}{
+		{"ASCII", 'a', 1},
+		{"control", '\t', 0},
+		{"C1 control", '\u0085', 0},
+		{"combining mark", '\u0301', 0},
+		{"zero width joiner", '\u200d', 0},
+		{"soft hyphen", '\u00ad', 1},
+		{"CJK", '世', 2},
+		{"fullwidth", 'Ａ', 2},
+		{"halfwidth", 'ｱ', 1},
+		{"emoji", '😀', 2},
+		{"text presentation symbol", '❤', 1},
+		{"Hangul Jungseong", '\u1161', 0},
+		{"Unicode 15.1 ideographic description", '\u2ffc', 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, RuneWidth(tt.r))
+		})
+	}
+}
+
+func TestUnicodeVersion(t *testing.T) {
+	require.Equal(t, EmojiVersion+".0", UnicodeWidthVersion)
+}
+
+func TestFirstGrapheme(t *testing.T) {
+	tests := []struct {
+		name             string
+		s                string
+		expectedGrapheme string
+		expectedWidth    int
+	}{
+		{"empty", "", "", 0},
+		{"ASCII", "ab", "a", 1},
+		{"CRLF", "\r\nx", "\r\n", 0},
+		{"combining mark", "éx", "é", 1},
+		{"CJK", "世界", "世", 2},
+		{"variation selector 16", "❤️x", "❤️", 2},
+		{"variation selector 15", "☺︎x", "☺︎", 1},
+		{"emoji modifier", "👍🏽x", "👍🏽", 2},
+		{"ZWJ sequence", "👨‍👩‍👧x", "👨‍👩‍👧", 2},
+		{"flag", "🇧🇷🇺🇸", "🇧🇷", 2},
+		{"control after base", "a\n", "a", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grapheme, width := FirstGrapheme(tt.s)
+			require.Equal(t, tt.expectedGrapheme, grapheme)
+			require.Equal(t, tt.expectedWidth, width)
+		})
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected int
+	}{
+		{"empty", "", 0},
+		{"ASCII", "hello", 5},
+		{"CJK", "日本語", 6},
+		{"combining marks", "café", 4},
+		{"emoji", "ok 👍🏽", 5},
+		{"ZWJ sequence", "👨‍👩‍👧", 2},
+		{"flags", "🇧🇷🇺🇸", 4},
+		{"SGR", "\033[1;31mred\033[0m", 3},
+		{"hyperlink", "\033]8;;https://example.com/\033\\link\033]8;;\033\\", 4},
+		{"hyperlink BEL", "\033]8;;https://example.com/\alink\033]8;;\a", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, StringWidth(tt.s))
+		})
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		width    int
+		expected string
+	}{
+		{"fits", "hello", 5, "hello"},
+		{"ASCII", "hello", 3, "hel"},
+		{"zero", "hello", 0, ""},
+		{"wide", "日本語", 3, "日"},
+		{"combining marks", "cafés", 4, "café"},
+		{"ZWJ sequence", "a👨‍👩‍👧", 2, "a"},
+		{"SGR", "\033[31mhello\033[0m", 2, "\033[31mhe\033[0m"},
+		{
+			"hyperlink",
+			"\033]8;;https://example.com/\033\\link\033]8;;\033\\",
+			2,
+			"\033]8;;https://example.com/\033\\li\033]8;;\033\\",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, TruncateToWidth(tt.s, tt.width))
+		})
+	}
+}